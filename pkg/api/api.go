@@ -485,6 +485,10 @@ func (hs *HTTPServer) registerRoutes() {
 				}
 			})
 
+			dashboardRoute.Get("/trash", authorize(reqSignedIn, ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsDelete), ac.EvalPermission(dashboards.ActionFoldersDelete))), routing.Wrap(hs.GetDeletedDashboards))
+			dashboardRoute.Post("/trash/:uid/restore", authorize(reqSignedIn, ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsDelete), ac.EvalPermission(dashboards.ActionFoldersDelete))), routing.Wrap(hs.RestoreDeletedDashboard))
+			dashboardRoute.Delete("/trash/:uid", authorize(reqSignedIn, ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsDelete), ac.EvalPermission(dashboards.ActionFoldersDelete))), routing.Wrap(hs.DeleteDashboardFromTrash))
+
 			dashboardRoute.Post("/calculate-diff", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.CalculateDashboardDiff))
 			dashboardRoute.Post("/validate", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.ValidateDashboard))
 			dashboardRoute.Post("/trim", routing.Wrap(hs.TrimDashboard))
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/dashmerge"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
@@ -314,19 +315,14 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 		return dashboardGuardianResponse(err)
 	}
 
-	// disconnect all library elements for this dashboard
+	// disconnect all library elements for this dashboard, they are connected again when it is restored
 	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
 	if err != nil {
 		hs.log.Error("Failed to disconnect library elements", "dashboard", dash.ID, "user", c.SignedInUser.UserID, "error", err)
 	}
 
-	// deletes all related public dashboard entities
-	err = hs.PublicDashboardsApi.PublicDashboardService.DeleteByDashboard(c.Req.Context(), dash)
-	if err != nil {
-		hs.log.Error("Failed to delete public dashboard")
-	}
-
-	err = hs.DashboardService.DeleteDashboard(c.Req.Context(), dash.ID, c.OrgID)
+	// the dashboard is moved to the trash, related public dashboards are deleted once it is removed from there
+	err = hs.DashboardService.SoftDeleteDashboard(c.Req.Context(), dash.ID, c.OrgID)
 	if err != nil {
 		var dashboardErr dashboards.DashboardErr
 		if ok := errors.As(err, &dashboardErr); ok {
@@ -356,12 +352,17 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 //
 // Creates a new dashboard or updates an existing dashboard.
 //
+// With `merge` set, saving a dashboard which was changed by someone else in the meantime
+// merges both changes using the version they were made from. Panels, template variables and
+// annotations changed on both sides are returned as conflicts and nothing is saved.
+//
 // Responses:
 // 200: postDashboardResponse
 // 400: badRequestError
 // 401: unauthorisedError
 // 403: forbiddenError
 // 404: notFoundError
+// 409: postDashboardConflictResponse
 // 412: preconditionFailedError
 // 422: unprocessableEntityError
 // 500: internalServerError
@@ -448,6 +449,19 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
 	}
 
+	merged := false
+	if cmd.Merge && !cmd.Overwrite && !newDashboard {
+		mergedData, rsp := hs.mergeDashboard(c, dash)
+		if rsp != nil {
+			return rsp
+		}
+		if mergedData != nil {
+			cmd.Dashboard = mergedData
+			dash = cmd.GetDashboardModel()
+			merged = true
+		}
+	}
+
 	dashItem := &dashboards.SaveDashboardDTO{
 		Dashboard: dash,
 		Message:   cmd.Message,
@@ -499,14 +513,64 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 	}
 
 	c.TimeRequest(metrics.MApiDashboardSave)
-	return response.JSON(http.StatusOK, util.DynMap{
+	result := util.DynMap{
 		"status":  "success",
 		"slug":    dashboard.Slug,
 		"version": dashboard.Version,
 		"id":      dashboard.ID,
 		"uid":     dashboard.UID,
 		"url":     dashboard.GetURL(),
+	}
+	if merged {
+		// the saved dashboard contains changes the client does not have yet
+		result["merged"] = true
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// mergeDashboard merges changes of dash with the changes saved since the version it was created from.
+// It returns nil if dash is not outdated, so there is nothing to merge.
+func (hs *HTTPServer) mergeDashboard(c *contextmodel.ReqContext, dash *dashboards.Dashboard) (*simplejson.Json, response.Response) {
+	ctx := c.Req.Context()
+	current, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: dash.ID, UID: dash.UID, OrgID: c.OrgID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			// let the save fail with the usual error
+			return nil, nil
+		}
+		return nil, response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+	if current.Version == dash.Version {
+		return nil, nil
+	}
+
+	base, err := hs.dashboardVersionService.Get(ctx, &dashver.GetDashboardVersionQuery{
+		DashboardID:  current.ID,
+		DashboardUID: current.UID,
+		OrgID:        c.OrgID,
+		Version:      dash.Version,
 	})
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			// the version the changes were made from is unknown or already deleted
+			return nil, apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, dashboards.ErrDashboardVersionMismatch)
+		}
+		return nil, response.Error(http.StatusInternalServerError, "Failed to get dashboard version", err)
+	}
+
+	result, err := dashmerge.Merge(base.Data, current.Data, dash.Data)
+	if err != nil {
+		return nil, response.Error(http.StatusInternalServerError, "Failed to merge dashboard", err)
+	}
+	if len(result.Conflicts) > 0 {
+		return nil, response.JSON(http.StatusConflict, util.DynMap{
+			"status":    "merge-conflict",
+			"message":   "The dashboard has been changed by someone else and the changes conflict",
+			"version":   current.Version,
+			"conflicts": result.Conflicts,
+		})
+	}
+	return result.Dashboard, nil
 }
 
 // swagger:route GET /dashboards/home dashboards getHomeDashboard
@@ -927,7 +991,7 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *contextmodel.ReqContext) respons
 		return response.Error(500, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	if options.DiffType == dashdiffs.DiffDelta || options.DiffType == dashdiffs.DiffSemantic {
 		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
@@ -1151,7 +1215,8 @@ type CalcDashboardDiffParams struct {
 		// Description:
 		// * `basic`
 		// * `json`
-		// Enum: basic,json
+		// * `semantic` - list of changes of panels, variables, annotations and dashboard properties
+		// Enum: basic,json,semantic
 		DiffType string `json:"diffType" binding:"Required"`
 	}
 }
@@ -1225,6 +1290,34 @@ type PostDashboardResponse struct {
 		// required: true
 		// example: /d/nHz3SXiiz/my-dashboard
 		URL string `json:"url"`
+
+		// Merged The saved dashboard was merged with changes made by someone else.
+		// required: false
+		Merged bool `json:"merged,omitempty"`
+	} `json:"body"`
+}
+
+// swagger:response postDashboardConflictResponse
+type PostDashboardConflictResponse struct {
+	// in: body
+	Body struct {
+		// Status status of the response.
+		// required: true
+		// example: merge-conflict
+		Status string `json:"status"`
+
+		// Message Message of the response.
+		// required: true
+		Message string `json:"message"`
+
+		// Version The current version of the dashboard.
+		// required: true
+		// example: 3
+		Version int64 `json:"version"`
+
+		// Conflicts Changes made to the same parts of the dashboard.
+		// required: true
+		Conflicts []dashmerge.Conflict `json:"conflicts"`
 	} `json:"body"`
 }
 
@@ -393,11 +393,9 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 				dashboardService := dashboards.NewFakeDashboardService(t)
 				qResult := dashboards.NewDashboard("test")
 				dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(qResult, nil)
-				dashboardService.On("DeleteDashboard", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(nil)
+				dashboardService.On("SoftDeleteDashboard", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(nil)
 
-				pubdashService := publicdashboards.NewFakePublicDashboardService(t)
-				pubdashService.On("DeleteByDashboard", mock.Anything, mock.Anything).Return(nil)
-				hs.callDeleteDashboardByUID(t, sc, dashboardService, pubdashService)
+				hs.callDeleteDashboardByUID(t, sc, dashboardService, nil)
 
 				assert.Equal(t, 200, sc.resp.Code)
 			}, mockSQLStore)
@@ -467,10 +465,8 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 				dashboardService := dashboards.NewFakeDashboardService(t)
 				qResult := dashboards.NewDashboard("test")
 				dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(qResult, nil)
-				dashboardService.On("DeleteDashboard", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(nil)
-				pubdashService := publicdashboards.NewFakePublicDashboardService(t)
-				pubdashService.On("DeleteByDashboard", mock.Anything, mock.Anything).Return(nil)
-				hs.callDeleteDashboardByUID(t, sc, dashboardService, pubdashService)
+				dashboardService.On("SoftDeleteDashboard", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).Return(nil)
+				hs.callDeleteDashboardByUID(t, sc, dashboardService, nil)
 
 				assert.Equal(t, 200, sc.resp.Code)
 			}, mockSQLStore)
@@ -689,6 +685,88 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 		})
 	})
 
+	t.Run("Given a request to merge an outdated dashboard", func(t *testing.T) {
+		base := simplejson.NewFromAny(map[string]interface{}{
+			"id": 2, "uid": "uid", "version": 1, "title": "Dash",
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "title": "CPU"},
+				map[string]interface{}{"id": 2, "title": "Memory"},
+			},
+		})
+		current := simplejson.NewFromAny(map[string]interface{}{
+			"id": 2, "uid": "uid", "version": 2, "title": "Dash",
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "title": "CPU usage"},
+				map[string]interface{}{"id": 2, "title": "Memory"},
+			},
+		})
+		currentDash := dashboards.NewDashboardFromJson(current)
+
+		fakeDashboardVersionService := dashvertest.NewDashboardVersionServiceFake()
+		fakeDashboardVersionService.ExpectedDashboardVersion = &dashver.DashboardVersionDTO{DashboardID: 2, Version: 1, Data: base}
+
+		t.Run("Should save changes merged with the current version", func(t *testing.T) {
+			cmd := dashboards.SaveDashboardCommand{
+				OrgID: 1,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"id": 2, "uid": "uid", "version": 1, "title": "Dash",
+					"panels": []interface{}{
+						map[string]interface{}{"id": 1, "title": "CPU"},
+						map[string]interface{}{"id": 2, "title": "Memory usage"},
+					},
+				}),
+				Merge: true,
+			}
+
+			var saved *dashboards.Dashboard
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(currentDash, nil)
+			dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+			}).Return(&dashboards.Dashboard{ID: 2, UID: "uid", Title: "Dash", Slug: "dash", Version: 3}, nil)
+
+			postDashboardMergeScenario(t, "When calling POST on", "/api/dashboards", "/api/dashboards", cmd, dashboardService, fakeDashboardVersionService, func(sc *scenarioContext) {
+				callPostDashboardShouldReturnSuccess(sc)
+				assert.True(t, sc.ToJSON().Get("merged").MustBool())
+
+				require.NotNil(t, saved)
+				assert.Equal(t, 2, saved.Version)
+				assert.Equal(t, "CPU usage", saved.Data.Get("panels").GetIndex(0).Get("title").MustString())
+				assert.Equal(t, "Memory usage", saved.Data.Get("panels").GetIndex(1).Get("title").MustString())
+			})
+		})
+
+		t.Run("Should return conflicts without saving", func(t *testing.T) {
+			cmd := dashboards.SaveDashboardCommand{
+				OrgID: 1,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"id": 2, "uid": "uid", "version": 1, "title": "Dash",
+					"panels": []interface{}{
+						map[string]interface{}{"id": 1, "title": "CPU load"},
+						map[string]interface{}{"id": 2, "title": "Memory"},
+					},
+				}),
+				Merge: true,
+			}
+
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(currentDash, nil)
+
+			postDashboardMergeScenario(t, "When calling POST on", "/api/dashboards", "/api/dashboards", cmd, dashboardService, fakeDashboardVersionService, func(sc *scenarioContext) {
+				callPostDashboard(sc)
+				assert.Equal(t, http.StatusConflict, sc.resp.Code)
+
+				result := sc.ToJSON()
+				assert.Equal(t, "merge-conflict", result.Get("status").MustString())
+				assert.Equal(t, 2, result.Get("version").MustInt())
+				conflicts := result.Get("conflicts").MustArray()
+				require.Len(t, conflicts, 1)
+				assert.Equal(t, "panel", result.Get("conflicts").GetIndex(0).Get("kind").MustString())
+				assert.Equal(t, "1", result.Get("conflicts").GetIndex(0).Get("key").MustString())
+			})
+		})
+	})
+
 	t.Run("Given a dashboard to validate", func(t *testing.T) {
 		sqlmock := dbtest.NewFakeDB()
 
@@ -1067,6 +1145,40 @@ func postDashboardScenario(t *testing.T, desc string, url string, routePattern s
 	})
 }
 
+func postDashboardMergeScenario(t *testing.T, desc string, url string, routePattern string, cmd dashboards.SaveDashboardCommand,
+	dashboardService dashboards.DashboardService, fakeDashboardVersionService *dashvertest.FakeDashboardVersionService, fn scenarioFunc) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		hs := HTTPServer{
+			Cfg:                          setting.NewCfg(),
+			ProvisioningService:          provisioning.NewProvisioningServiceMock(context.Background()),
+			QuotaService:                 quotatest.New(false, nil),
+			pluginStore:                  &plugins.FakePluginStore{},
+			LibraryPanelService:          &mockLibraryPanelService{},
+			LibraryElementService:        &mockLibraryElementService{},
+			DashboardService:             dashboardService,
+			dashboardProvisioningService: mockDashboardProvisioningService{},
+			dashboardVersionService:      fakeDashboardVersionService,
+			Features:                     featuremgmt.WithFeatures(),
+			Kinds:                        corekind.NewBase(nil),
+			accesscontrolService:         actest.FakeService{},
+		}
+
+		sc := setupScenarioContext(t, url)
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			sc.context = c
+			sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+
+			return hs.PostDashboard(c)
+		})
+
+		sc.m.Post(routePattern, sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
 func postValidateScenario(t *testing.T, desc string, url string, routePattern string, cmd dashboards.ValidateDashboardCommand,
 	role org.RoleType, fn scenarioFunc, sqlmock db.DB) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/trash dashboards getDeletedDashboards
+//
+// List deleted dashboards and folders.
+//
+// Returns dashboards and folders of the current organization which are in the trash and can be deleted by the user.
+// Dashboards deleted together with their folder are restored with the folder and are not listed.
+//
+// Responses:
+// 200: getDeletedDashboardsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDeletedDashboards(c *contextmodel.ReqContext) response.Response {
+	hits, err := hs.DashboardService.FindDashboards(c.Req.Context(), &dashboards.FindPersistedDashboardsQuery{
+		Title:        c.Query("query"),
+		OrgId:        c.OrgID,
+		SignedInUser: c.SignedInUser,
+		Limit:        c.QueryInt64("limit"),
+		Page:         c.QueryInt64("page"),
+		Permission:   dashboards.PERMISSION_VIEW,
+		Deleted:      true,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+
+	// A search hit is returned for every tag of a dashboard.
+	result := make([]dtos.DeletedDashboard, 0, len(hits))
+	seen := make(map[int64]bool, len(hits))
+	for _, hit := range hits {
+		if seen[hit.ID] {
+			continue
+		}
+		seen[hit.ID] = true
+		if !hs.canDeleteFromTrash(c, hit) {
+			continue
+		}
+		result = append(result, dtos.DeletedDashboard{
+			Uid:         hit.UID,
+			Title:       hit.Title,
+			IsFolder:    hit.IsFolder,
+			FolderUid:   hit.FolderUID,
+			FolderTitle: hit.FolderTitle,
+			Deleted:     hit.Deleted,
+		})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /dashboards/trash/{uid}/restore dashboards restoreDeletedDashboardByUID
+//
+// Restore a deleted dashboard or folder.
+//
+// Moves the dashboard or folder out of the trash back to its original folder, keeping its versions and permissions.
+// A restored folder brings back the dashboards which were deleted together with it.
+//
+// Responses:
+// 200: restoreDeletedDashboardResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreDeletedDashboard(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+
+	// Only dashboards which the user could delete can be restored, the same as the ones which can be
+	// deleted permanently.
+	if _, rsp := hs.getDeletedDashboardHelper(c, uid); rsp != nil {
+		return rsp
+	}
+
+	dash, err := hs.DashboardService.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{
+		UID:   uid,
+		OrgID: c.OrgID,
+		User:  c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+
+	if !dash.IsFolder {
+		// connect library panels, they were disconnected when the dashboard was deleted
+		if err := hs.LibraryPanelService.ConnectLibraryPanelsForDashboard(ctx, c.SignedInUser, dash); err != nil {
+			hs.log.Error("Failed to connect library panels", "dashboard", dash.UID, "user", c.SignedInUser.UserID, "error", err)
+		}
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"title":   dash.Title,
+		"uid":     dash.UID,
+		"url":     dash.GetURL(),
+		"message": "Dashboard " + dash.Title + " restored",
+	})
+}
+
+// swagger:route DELETE /dashboards/trash/{uid} dashboards deleteDashboardFromTrashByUID
+//
+// Permanently delete a dashboard or folder in the trash.
+//
+// Deletes the dashboard or folder with its versions and permissions without waiting for the trash retention
+// period to pass. A folder is deleted together with the dashboards which were deleted with it.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteDashboardFromTrash(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+	uid := web.Params(c.Req)[":uid"]
+
+	hit, rsp := hs.getDeletedDashboardHelper(c, uid)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.DashboardService.DeleteDashboardFromTrash(ctx, &dashboards.DeleteDashboardFromTrashCommand{
+		UID:   uid,
+		OrgID: c.OrgID,
+	})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
+	}
+
+	return response.Success("Dashboard " + hit.Title + " deleted permanently")
+}
+
+// getDeletedDashboardHelper returns the dashboard or folder in the trash if the user is allowed to delete it.
+func (hs *HTTPServer) getDeletedDashboardHelper(c *contextmodel.ReqContext, uid string) (dashboards.DashboardSearchProjection, response.Response) {
+	hits, err := hs.DashboardService.FindDashboards(c.Req.Context(), &dashboards.FindPersistedDashboardsQuery{
+		OrgId:         c.OrgID,
+		SignedInUser:  c.SignedInUser,
+		DashboardUIDs: []string{uid},
+		Permission:    dashboards.PERMISSION_VIEW,
+		Deleted:       true,
+	})
+	if err != nil {
+		return dashboards.DashboardSearchProjection{}, response.Error(http.StatusInternalServerError, "Failed to get deleted dashboard", err)
+	}
+	if len(hits) == 0 {
+		return dashboards.DashboardSearchProjection{}, response.Error(http.StatusNotFound, "Dashboard not found", nil)
+	}
+	if !hs.canDeleteFromTrash(c, hits[0]) {
+		return dashboards.DashboardSearchProjection{}, dashboardGuardianResponse(nil)
+	}
+	return hits[0], nil
+}
+
+// canDeleteFromTrash checks that the user has the delete permission on a dashboard or folder in the trash,
+// the same as when it was deleted. The scopes are built from the search hit because the scope resolvers
+// only find dashboards and folders which are not deleted.
+func (hs *HTTPServer) canDeleteFromTrash(c *contextmodel.ReqContext, hit dashboards.DashboardSearchProjection) bool {
+	action := dashboards.ActionFoldersDelete
+	scopes := []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(hit.UID)}
+	folderUID := hit.UID
+	if !hit.IsFolder {
+		folderUID = hit.FolderUID
+		if folderUID == "" {
+			folderUID = accesscontrol.GeneralFolderUID
+		}
+		action = dashboards.ActionDashboardsDelete
+		scopes = []string{
+			dashboards.ScopeDashboardsProvider.GetResourceScopeUID(hit.UID),
+			dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID),
+		}
+	}
+
+	if folderUID != accesscontrol.GeneralFolderUID {
+		// The parents are not found when they are in the trash as well, the direct scopes are used then.
+		if inherited, err := dashboards.GetInheritedScopes(c.Req.Context(), c.OrgID, folderUID, hs.folderService); err == nil {
+			scopes = append(scopes, inherited...)
+		}
+	}
+
+	return accesscontrol.EvalPermission(action, scopes...).Evaluate(c.SignedInUser.Permissions[c.OrgID])
+}
+
+// swagger:parameters getDeletedDashboards
+type GetDeletedDashboardsParams struct {
+	// Search deleted dashboards and folders by title
+	// in:query
+	// required:false
+	Query string `json:"query"`
+
+	// Maximum number of results to return
+	// in:query
+	// required:false
+	// default:1000
+	Limit int64 `json:"limit"`
+
+	// Page of the results to return
+	// in:query
+	// required:false
+	// default:1
+	Page int64 `json:"page"`
+}
+
+// swagger:parameters restoreDeletedDashboardByUID deleteDashboardFromTrashByUID
+type RestoreDeletedDashboardByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getDeletedDashboardsResponse
+type GetDeletedDashboardsResponse struct {
+	// in: body
+	Body []dtos.DeletedDashboard `json:"body"`
+}
+
+// swagger:response restoreDeletedDashboardResponse
+type RestoreDeletedDashboardResponse struct {
+	// in: body
+	Body struct {
+		// UID The unique identifier (uid) of the restored dashboard.
+		// required: true
+		// example: nHz3SXiiz
+		UID string `json:"uid"`
+
+		// Title Title of the restored dashboard.
+		// required: true
+		// example: My Dashboard
+		Title string `json:"title"`
+
+		// URL The relative URL for accessing the restored dashboard.
+		// required: true
+		// example: /d/nHz3SXiiz/my-dashboard
+		URL string `json:"url"`
+
+		// Message Message of the restored dashboard.
+		// required: true
+		// example: Dashboard My Dashboard restored
+		Message string `json:"message"`
+	} `json:"body"`
+}
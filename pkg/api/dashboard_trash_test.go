@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestDashboardTrashAPIEndpoint(t *testing.T) {
+	trashed := []dashboards.DashboardSearchProjection{
+		{ID: 1, UID: "dash", Title: "Dashboard", FolderUID: "folder"},
+		{ID: 2, UID: "other", Title: "Folder", IsFolder: true},
+	}
+
+	setup := func(t *testing.T, hits []dashboards.DashboardSearchProjection) (*webtest.Server, *dashboards.FakeDashboardService) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("FindDashboards", mock.Anything, mock.Anything).Return(hits, nil).Maybe()
+		srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = &setting.Cfg{RBACEnabled: true}
+			hs.DashboardService = dashSvc
+			hs.LibraryPanelService = &mockLibraryPanelService{}
+			hs.folderService = &foldertest.FakeService{ExpectedFolders: []*folder.Folder{{UID: "parent"}}}
+		})
+		return srv, dashSvc
+	}
+
+	editOnly := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsProvider.GetResourceScopeUID("dash")},
+		{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeDashboardsProvider.GetResourceScopeUID("dash")},
+		{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeDashboardsProvider.GetResourceScopeUID("another")},
+		{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("another")},
+	}
+	deleteInParentFolder := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("parent")},
+	}
+
+	t.Run("list only contains dashboards and folders the user can delete", func(t *testing.T) {
+		srv, _ := setup(t, trashed)
+
+		for _, tc := range []struct {
+			permissions []accesscontrol.Permission
+			expected    []string
+		}{
+			{permissions: editOnly, expected: []string{}},
+			{permissions: deleteInParentFolder, expected: []string{"dash"}},
+			{permissions: []accesscontrol.Permission{{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll}}, expected: []string{"other"}},
+		} {
+			req := webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/dashboards/trash"), userWithPermissions(1, tc.permissions))
+			resp, err := srv.Send(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var result []dtos.DeletedDashboard
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.NoError(t, resp.Body.Close())
+			uids := make([]string, 0, len(result))
+			for _, d := range result {
+				uids = append(uids, d.Uid)
+			}
+			assert.Equal(t, tc.expected, uids)
+		}
+	})
+
+	t.Run("restore and permanent delete fail without delete permission on the dashboard", func(t *testing.T) {
+		srv, dashSvc := setup(t, trashed[:1])
+
+		req := webtest.RequestWithSignedInUser(srv.NewPostRequest("/api/dashboards/trash/dash/restore", nil), userWithPermissions(1, editOnly))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		req = webtest.RequestWithSignedInUser(srv.NewRequest(http.MethodDelete, "/api/dashboards/trash/dash", nil), userWithPermissions(1, editOnly))
+		resp, err = srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		dashSvc.AssertNotCalled(t, "RestoreDeletedDashboard", mock.Anything, mock.Anything)
+		dashSvc.AssertNotCalled(t, "DeleteDashboardFromTrash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("permanent delete fails without delete permission on the folder", func(t *testing.T) {
+		srv, dashSvc := setup(t, trashed[1:])
+
+		req := webtest.RequestWithSignedInUser(srv.NewRequest(http.MethodDelete, "/api/dashboards/trash/other", nil), userWithPermissions(1, deleteInParentFolder))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		dashSvc.AssertNotCalled(t, "DeleteDashboardFromTrash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("restore and permanent delete succeed with delete permission inherited from a folder", func(t *testing.T) {
+		srv, dashSvc := setup(t, trashed[:1])
+		dashSvc.On("RestoreDeletedDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash", Title: "Dashboard"}, nil)
+		dashSvc.On("DeleteDashboardFromTrash", mock.Anything, mock.Anything).Return(nil)
+
+		req := webtest.RequestWithSignedInUser(srv.NewPostRequest("/api/dashboards/trash/dash/restore", nil), userWithPermissions(1, deleteInParentFolder))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req = webtest.RequestWithSignedInUser(srv.NewRequest(http.MethodDelete, "/api/dashboards/trash/dash", nil), userWithPermissions(1, deleteInParentFolder))
+		resp, err = srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
@@ -76,3 +76,12 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type DeletedDashboard struct {
+	Uid         string    `json:"uid"`
+	Title       string    `json:"title"`
+	IsFolder    bool      `json:"isFolder"`
+	FolderUid   string    `json:"folderUid,omitempty"`
+	FolderTitle string    `json:"folderTitle,omitempty"`
+	Deleted     time.Time `json:"deleted"`
+}
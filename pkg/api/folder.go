@@ -274,17 +274,24 @@ func (hs *HTTPServer) UpdateFolder(c *contextmodel.ReqContext) response.Response
 // 404: notFoundError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteFolder(c *contextmodel.ReqContext) response.Response { // temporarily adding this function to HTTPServer, will be removed from HTTPServer when librarypanels featuretoggle is removed
-	err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
-	if err != nil {
-		if errors.Is(err, model.ErrFolderHasConnectedLibraryElements) {
-			return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+	uid := web.Params(c.Req)[":uid"]
+	// Folders are moved to the trash unless nested folders are enabled, library elements are kept
+	// with them and deleted when the folder is removed from the trash.
+	if hs.Features.IsEnabled(featuremgmt.FlagNestedFolders) {
+		err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, uid)
+		if err != nil {
+			if errors.Is(err, model.ErrFolderHasConnectedLibraryElements) {
+				return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+			}
+			return apierrors.ToFolderErrorResponse(err)
 		}
-		return apierrors.ToFolderErrorResponse(err)
 	}
 
-	uid := web.Params(c.Req)[":uid"]
-	err = hs.folderService.Delete(c.Req.Context(), &folder.DeleteFolderCommand{UID: uid, OrgID: c.OrgID, ForceDeleteRules: c.QueryBool("forceDeleteRules"), SignedInUser: c.SignedInUser})
+	err := hs.folderService.Delete(c.Req.Context(), &folder.DeleteFolderCommand{UID: uid, OrgID: c.OrgID, ForceDeleteRules: c.QueryBool("forceDeleteRules"), SignedInUser: c.SignedInUser})
 	if err != nil {
+		if errors.Is(err, model.ErrFolderHasConnectedLibraryElements) {
+			return response.Error(403, "Folder could not be deleted because it contains library elements in use", err)
+		}
 		return apierrors.ToFolderErrorResponse(err)
 	}
 
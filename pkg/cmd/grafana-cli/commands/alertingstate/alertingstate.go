@@ -0,0 +1,79 @@
+package alertingstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/server"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ExportState writes the runtime state of Grafana Alerting of all organizations to the file specified by the first argument.
+func ExportState(cmd utils.CommandLine, runner server.Runner) error {
+	path := cmd.Args().First()
+	if path == "" {
+		return errors.New("please specify a path to the file to write the archive to")
+	}
+
+	archive, err := newService(runner).Export(context.Background())
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(path), b, 0600); err != nil {
+		return fmt.Errorf("could not write the archive: %w", err)
+	}
+
+	logger.Infof("Alerting state of %d organizations exported to %s %s", len(archive.Orgs), path, color.GreenString("✔"))
+	return nil
+}
+
+// ImportState replaces the runtime state of Grafana Alerting with the archive in the file specified by the first argument.
+// Grafana must not be running, otherwise the Alertmanagers overwrite the imported silences and notification log when they stop.
+func ImportState(cmd utils.CommandLine, runner server.Runner) error {
+	path := cmd.Args().First()
+	if path == "" {
+		return errors.New("please specify a path to the file to read the archive from")
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("could not read the archive: %w", err)
+	}
+	var archive apimodels.StateArchive
+	if err := json.Unmarshal(b, &archive); err != nil {
+		return fmt.Errorf("could not parse the archive: %w", err)
+	}
+
+	if err := newService(runner).Import(context.Background(), &archive); err != nil {
+		return err
+	}
+
+	logger.Infof("Alerting state of %d organizations imported from %s %s", len(archive.Orgs), path, color.GreenString("✔"))
+	return nil
+}
+
+func newService(runner server.Runner) *statearchive.Service {
+	dbstore := &store.DBstore{
+		Cfg:            runner.Cfg.UnifiedAlerting,
+		FeatureToggles: runner.Features,
+		SQLStore:       runner.SQLStore,
+		Logger:         log.New("ngalert.dbstore"),
+	}
+	return statearchive.NewService(dbstore, dbstore, dbstore, kvstore.ProvideService(runner.SQLStore), log.New("ngalert.state.archive"))
+}
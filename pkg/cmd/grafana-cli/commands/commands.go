@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingstate"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -185,6 +186,22 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting-state",
+		Usage: "Exports and imports the runtime state of Grafana Alerting: alert instances, silences, notification log and Alertmanager configuration",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "export <archive file>. Writes the state of all organizations to the file. Stop Grafana before running it to include the latest silences and notification log.",
+				Action: runRunnerCommand(alertingstate.ExportState),
+			},
+			{
+				Name:   "import",
+				Usage:  "import <archive file>. Replaces the state of the organizations in the archive. Grafana must be stopped. The secret key must be the same as in the exported instance.",
+				Action: runRunnerCommand(alertingstate.ImportState),
+			},
+		},
+	},
 	{
 		Name:  "user-manager",
 		Usage: "Runs different helpful user commands",
@@ -0,0 +1,382 @@
+package dashdiffs
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/components/dashmatch"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	ChangeKindDashboard  = "dashboard"
+	ChangeKindPanel      = "panel"
+	ChangeKindVariable   = "variable"
+	ChangeKindAnnotation = "annotation"
+
+	ChangeOpAdded   = "added"
+	ChangeOpRemoved = "removed"
+	ChangeOpChanged = "changed"
+	ChangeOpMoved   = "moved"
+)
+
+// Change is a single semantic change of a dashboard, e.g. a panel query which changed
+// or a variable which was added.
+type Change struct {
+	// Kind is one of dashboard, panel, variable or annotation.
+	Kind string `json:"kind"`
+	// Key identifies the panel, variable or annotation, it is empty for dashboard properties.
+	Key string `json:"key,omitempty"`
+	// Op is one of added, removed, changed or moved.
+	Op string `json:"op"`
+	// Path is the changed property, e.g. datasource or targets.A for the query with refId A.
+	Path string `json:"path,omitempty"`
+	// Summary is a human readable description of the change.
+	Summary string      `json:"summary"`
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+}
+
+// dashboard properties which change with every save.
+var ignoredDashboardKeys = map[string]bool{"id": true, "version": true, "iteration": true}
+
+// DiffDashboards returns semantic changes between two dashboard models. Panels are matched
+// by id, then by title, then by position, so that moving a panel or reordering the panels
+// array does not show up as removing and adding panels. Variables and annotations are
+// matched by name. Dashboard merges match them the same way, see dashmatch.
+func DiffDashboards(baseData, newData *simplejson.Json) ([]Change, error) {
+	base, err := dashmatch.ToMap(baseData)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := dashmatch.ToMap(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, key := range dashmatch.UnionKeys(base, updated) {
+		if ignoredDashboardKeys[key] || key == "panels" || key == "templating" || key == "annotations" {
+			continue
+		}
+		if change, ok := diffValue(ChangeKindDashboard, "", key, fmt.Sprintf("dashboard %s", key), base[key], updated[key]); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	changes = append(changes, diffPanelLists(base["panels"], updated["panels"])...)
+	changes = append(changes, diffNamedLists(ChangeKindVariable, listOf(base["templating"]), listOf(updated["templating"]), diffVariable)...)
+	changes = append(changes, diffNamedLists(ChangeKindAnnotation, listOf(base["annotations"]), listOf(updated["annotations"]), diffObject)...)
+	return changes, nil
+}
+
+// DiffPanels returns semantic changes between two models of the same panel, e.g. of a library panel.
+// Alert rules are not diffed with it, as they only reference a panel by annotations and keep no panel model.
+func DiffPanels(baseData, newData *simplejson.Json) ([]Change, error) {
+	base, err := dashmatch.ToMap(baseData)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := dashmatch.ToMap(newData)
+	if err != nil {
+		return nil, err
+	}
+	return diffPanel(panelKey(updated), panelLabel(updated), base, updated), nil
+}
+
+// DiffVariables returns semantic changes between two models of the same template variable,
+// e.g. of a library variable.
+func DiffVariables(baseData, newData *simplejson.Json) ([]Change, error) {
+	base, err := dashmatch.ToMap(baseData)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := dashmatch.ToMap(newData)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := updated["name"].(string)
+	return diffVariable(ChangeKindVariable, name, base, updated), nil
+}
+
+// panelItem is a panel with the row it is placed in, panels of collapsed rows are nested in the row.
+type panelItem struct {
+	panel map[string]interface{}
+	row   string
+}
+
+func diffPanelLists(baseList, newList interface{}) []Change {
+	base := flattenPanels(baseList)
+	updated := flattenPanels(newList)
+
+	basePanels := make([]interface{}, 0, len(base))
+	for _, b := range base {
+		basePanels = append(basePanels, b.panel)
+	}
+	newPanels := make([]interface{}, 0, len(updated))
+	for _, n := range updated {
+		newPanels = append(newPanels, n.panel)
+	}
+	keys := dashmatch.PanelKeys(basePanels, newPanels)
+	baseByKey := make(map[string]int, len(base))
+	for bi, key := range keys[0] {
+		baseByKey[key] = bi
+	}
+	matched := make(map[int]int, len(updated))
+	usedBase := make(map[int]bool, len(base))
+	for ni, key := range keys[1] {
+		if bi, ok := baseByKey[key]; ok {
+			matched[ni] = bi
+			usedBase[bi] = true
+		}
+	}
+
+	var changes []Change
+	for ni, n := range updated {
+		key, label := panelKey(n.panel), panelLabel(n.panel)
+		bi, ok := matched[ni]
+		if !ok {
+			changes = append(changes, Change{
+				Kind:    ChangeKindPanel,
+				Key:     key,
+				Op:      ChangeOpAdded,
+				Summary: fmt.Sprintf("panel %s added", label),
+				New:     n.panel,
+			})
+			continue
+		}
+		b := base[bi]
+		if b.row != n.row {
+			changes = append(changes, Change{
+				Kind:    ChangeKindPanel,
+				Key:     key,
+				Op:      ChangeOpMoved,
+				Path:    "row",
+				Summary: fmt.Sprintf("panel %s moved to another row", label),
+				Old:     b.row,
+				New:     n.row,
+			})
+		}
+		changes = append(changes, diffPanel(key, label, b.panel, n.panel)...)
+	}
+	for bi, b := range base {
+		if !usedBase[bi] {
+			changes = append(changes, Change{
+				Kind:    ChangeKindPanel,
+				Key:     panelKey(b.panel),
+				Op:      ChangeOpRemoved,
+				Summary: fmt.Sprintf("panel %s removed", panelLabel(b.panel)),
+				Old:     b.panel,
+			})
+		}
+	}
+	return changes
+}
+
+// panel properties reported with a dedicated description.
+var panelPropertyNames = map[string]string{
+	"type":        "visualization",
+	"fieldConfig": "field config",
+	"alert":       "alert",
+}
+
+func diffPanel(key, label string, base, updated map[string]interface{}) []Change {
+	var changes []Change
+	for _, prop := range dashmatch.UnionKeys(base, updated) {
+		switch prop {
+		case "id", "panels":
+			// ids are only used for matching, panels of rows are compared on their own
+			continue
+		case "gridPos":
+			if !reflect.DeepEqual(base[prop], updated[prop]) {
+				changes = append(changes, Change{
+					Kind:    ChangeKindPanel,
+					Key:     key,
+					Op:      ChangeOpMoved,
+					Path:    prop,
+					Summary: fmt.Sprintf("panel %s moved", label),
+					Old:     base[prop],
+					New:     updated[prop],
+				})
+			}
+		case "targets":
+			changes = append(changes, diffQueries(key, label, base[prop], updated[prop])...)
+		default:
+			name := prop
+			if n, ok := panelPropertyNames[prop]; ok {
+				name = n
+			}
+			if change, ok := diffValue(ChangeKindPanel, key, prop, fmt.Sprintf("panel %s %s", label, name), base[prop], updated[prop]); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+// diffQueries compares panel queries matched by refId.
+func diffQueries(key, label string, base, updated interface{}) []Change {
+	queryKey := func(query interface{}, pos int) string {
+		if q, ok := query.(map[string]interface{}); ok {
+			if refID, ok := q["refId"].(string); ok && refID != "" {
+				return refID
+			}
+		}
+		return fmt.Sprintf("#%d", pos)
+	}
+	baseQueries := map[string]interface{}{}
+	var order []string
+	for pos, q := range listOrEmpty(base) {
+		k := queryKey(q, pos)
+		baseQueries[k] = q
+		order = append(order, k)
+	}
+	newQueries := map[string]interface{}{}
+	for pos, q := range listOrEmpty(updated) {
+		k := queryKey(q, pos)
+		newQueries[k] = q
+		if _, ok := baseQueries[k]; !ok {
+			order = append(order, k)
+		}
+	}
+
+	var changes []Change
+	for _, k := range order {
+		if change, ok := diffValue(ChangeKindPanel, key, "targets."+k, fmt.Sprintf("panel %s query %s", label, k), baseQueries[k], newQueries[k]); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func diffVariable(kind, name string, base, updated map[string]interface{}) []Change {
+	var changes []Change
+	for _, prop := range dashmatch.UnionKeys(base, updated) {
+		// current and options hold the selected value, which is not a change of the variable
+		if prop == "current" || prop == "options" {
+			continue
+		}
+		if change, ok := diffValue(kind, name, prop, fmt.Sprintf("%s %q %s", kind, name, prop), base[prop], updated[prop]); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func diffObject(kind, name string, base, updated map[string]interface{}) []Change {
+	if reflect.DeepEqual(base, updated) {
+		return nil
+	}
+	return []Change{{
+		Kind:    kind,
+		Key:     name,
+		Op:      ChangeOpChanged,
+		Summary: fmt.Sprintf("%s %q changed", kind, name),
+		Old:     base,
+		New:     updated,
+	}}
+}
+
+// diffNamedLists compares items matched by name.
+func diffNamedLists(kind string, base, updated []interface{}, diffItem func(kind, name string, base, updated map[string]interface{}) []Change) []Change {
+	baseKeys := dashmatch.NameKeys(base)
+	baseItems := map[string]map[string]interface{}{}
+	for pos, item := range base {
+		if obj, ok := item.(map[string]interface{}); ok {
+			baseItems[baseKeys[pos]] = obj
+		}
+	}
+
+	var changes []Change
+	seen := map[string]bool{}
+	for pos, name := range dashmatch.NameKeys(updated) {
+		obj, ok := updated[pos].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		seen[name] = true
+		b, ok := baseItems[name]
+		if !ok {
+			changes = append(changes, Change{Kind: kind, Key: name, Op: ChangeOpAdded, Summary: fmt.Sprintf("%s %q added", kind, name), New: obj})
+			continue
+		}
+		changes = append(changes, diffItem(kind, name, b, obj)...)
+	}
+	for pos, name := range baseKeys {
+		if obj, ok := base[pos].(map[string]interface{}); ok && !seen[name] {
+			changes = append(changes, Change{Kind: kind, Key: name, Op: ChangeOpRemoved, Summary: fmt.Sprintf("%s %q removed", kind, name), Old: obj})
+		}
+	}
+	return changes
+}
+
+// diffValue returns a change of a single value, described by subject.
+func diffValue(kind, key, path, subject string, base, updated interface{}) (Change, bool) {
+	if reflect.DeepEqual(base, updated) {
+		return Change{}, false
+	}
+	change := Change{Kind: kind, Key: key, Path: path, Old: base, New: updated}
+	switch {
+	case base == nil:
+		change.Op = ChangeOpAdded
+	case updated == nil:
+		change.Op = ChangeOpRemoved
+	default:
+		change.Op = ChangeOpChanged
+	}
+	change.Summary = fmt.Sprintf("%s %s", subject, change.Op)
+	return change, true
+}
+
+// flattenPanels returns panels with panels nested in collapsed rows.
+func flattenPanels(list interface{}) []panelItem {
+	var items []panelItem
+	row := ""
+	for _, p := range listOrEmpty(list) {
+		panel, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if panel["type"] == "row" {
+			row = panelKey(panel)
+			items = append(items, panelItem{panel: panel})
+			for _, nested := range listOrEmpty(panel["panels"]) {
+				if n, ok := nested.(map[string]interface{}); ok {
+					items = append(items, panelItem{panel: n, row: row})
+				}
+			}
+			continue
+		}
+		items = append(items, panelItem{panel: panel, row: row})
+	}
+	return items
+}
+
+// panelKey returns the id of the panel, or its title if the panel has no id.
+func panelKey(panel map[string]interface{}) string {
+	if id, ok := panel["id"]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	title, _ := panel["title"].(string)
+	return title
+}
+
+// panelLabel returns the quoted title of the panel used in summaries, or its id if the panel has no title.
+func panelLabel(panel map[string]interface{}) string {
+	if title, ok := panel["title"].(string); ok && title != "" {
+		return fmt.Sprintf("%q", title)
+	}
+	return "#" + panelKey(panel)
+}
+
+func listOf(container interface{}) []interface{} {
+	if obj, ok := container.(map[string]interface{}); ok {
+		return listOrEmpty(obj["list"])
+	}
+	return nil
+}
+
+func listOrEmpty(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
@@ -0,0 +1,126 @@
+package dashdiffs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestDiffDashboards(t *testing.T) {
+	const baseJSON = `{
+		"id": 1,
+		"version": 1,
+		"title": "Dashboard",
+		"refresh": "5s",
+		"panels": [
+			{"id": 1, "title": "CPU", "type": "timeseries", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+				"datasource": {"uid": "prom"}, "targets": [{"refId": "A", "expr": "cpu"}]},
+			{"id": 2, "title": "Memory", "type": "timeseries", "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}},
+			{"id": 3, "title": "Disk", "type": "timeseries", "gridPos": {"x": 0, "y": 8, "w": 12, "h": 8}},
+			{"id": 4, "title": "Details", "type": "row", "collapsed": true, "gridPos": {"x": 0, "y": 16, "w": 24, "h": 1},
+				"panels": [{"id": 5, "title": "Network", "type": "timeseries", "gridPos": {"x": 0, "y": 17, "w": 12, "h": 8}}]}
+		],
+		"templating": {"list": [
+			{"name": "host", "query": "hosts", "current": {"value": "a"}},
+			{"name": "env", "query": "envs"}
+		]},
+		"annotations": {"list": [{"name": "Deploys", "enable": true}]}
+	}`
+
+	t.Run("Should not report reordered panels", func(t *testing.T) {
+		base, err := simplejson.NewJson([]byte(baseJSON))
+		require.NoError(t, err)
+		updated, err := simplejson.NewJson([]byte(baseJSON))
+		require.NoError(t, err)
+		panels := updated.Get("panels").MustArray()
+		panels[0], panels[2] = panels[2], panels[0]
+		updated.Set("panels", panels)
+		updated.Set("version", 2)
+		updated.Get("templating").Get("list").GetIndex(0).Set("current", map[string]interface{}{"value": "b"})
+
+		changes, err := DiffDashboards(base, updated)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Should report changes of panels, variables, annotations and dashboard properties", func(t *testing.T) {
+		base, err := simplejson.NewJson([]byte(baseJSON))
+		require.NoError(t, err)
+		updated, err := simplejson.NewJson([]byte(`{
+			"id": 1,
+			"version": 2,
+			"title": "Dashboard",
+			"refresh": "1m",
+			"panels": [
+				{"id": 1, "title": "CPU", "type": "timeseries", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+					"datasource": {"uid": "loki"}, "targets": [{"refId": "A", "expr": "cpu_usage"}, {"refId": "B", "expr": "load"}]},
+				{"id": 7, "title": "Memory", "type": "timeseries", "gridPos": {"x": 12, "y": 8, "w": 12, "h": 8}},
+				{"id": 4, "title": "Details", "type": "row", "collapsed": false, "gridPos": {"x": 0, "y": 16, "w": 24, "h": 1}},
+				{"id": 5, "title": "Network", "type": "timeseries", "gridPos": {"x": 0, "y": 17, "w": 12, "h": 8}},
+				{"id": 6, "title": "Errors", "type": "stat", "gridPos": {"x": 12, "y": 17, "w": 12, "h": 8}}
+			],
+			"templating": {"list": [
+				{"name": "host", "query": "servers"},
+				{"name": "region", "query": "regions"}
+			]},
+			"annotations": {"list": [{"name": "Deploys", "enable": false}]}
+		}`))
+		require.NoError(t, err)
+
+		changes, err := DiffDashboards(base, updated)
+		require.NoError(t, err)
+
+		summaries := make([]string, 0, len(changes))
+		for _, change := range changes {
+			summaries = append(summaries, change.Summary)
+		}
+		assert.Equal(t, []string{
+			"dashboard refresh changed",
+			`panel "CPU" datasource changed`,
+			`panel "CPU" query A changed`,
+			`panel "CPU" query B added`,
+			`panel "Memory" moved`,
+			`panel "Details" collapsed changed`,
+			`panel "Errors" added`,
+			`panel "Disk" removed`,
+			`variable "host" query changed`,
+			`variable "region" added`,
+			`variable "env" removed`,
+			`annotation "Deploys" changed`,
+		}, summaries)
+
+		assert.Equal(t, ChangeKindPanel, changes[2].Kind)
+		assert.Equal(t, "1", changes[2].Key)
+		assert.Equal(t, "targets.A", changes[2].Path)
+		assert.Equal(t, ChangeOpMoved, changes[4].Op)
+		assert.Equal(t, "7", changes[4].Key)
+	})
+
+	t.Run("Should serve semantic diff from CalculateDiff", func(t *testing.T) {
+		base, err := simplejson.NewJson([]byte(baseJSON))
+		require.NoError(t, err)
+		updated, err := simplejson.NewJson([]byte(baseJSON))
+		require.NoError(t, err)
+		updated.Set("title", "Renamed")
+
+		result, err := CalculateDiff(context.Background(), &Options{DiffType: ParseDiffType("semantic")}, base, updated)
+		require.NoError(t, err)
+
+		var changes []Change
+		require.NoError(t, json.Unmarshal(result.Delta, &changes))
+		require.Len(t, changes, 1)
+		assert.Equal(t, Change{
+			Kind:    ChangeKindDashboard,
+			Op:      ChangeOpChanged,
+			Path:    "title",
+			Summary: "dashboard title changed",
+			Old:     "Dashboard",
+			New:     "Renamed",
+		}, changes[0])
+	})
+}
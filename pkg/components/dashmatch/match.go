@@ -0,0 +1,150 @@
+// Package dashmatch identifies panels, template variables and annotations across versions
+// of a dashboard model, so that comparing and merging versions agree on what changed.
+//
+// Panels are matched by id, then by title, then by position in the grid, so that a panel
+// which got a new id or was moved is still recognized. Variables and annotations are
+// matched by name.
+package dashmatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// panelMatchers are tried in order, a panel is matched by the first one which finds a base panel.
+var panelMatchers = []func(base, other map[string]interface{}) bool{
+	func(b, o map[string]interface{}) bool { return b["id"] != nil && reflect.DeepEqual(b["id"], o["id"]) },
+	func(b, o map[string]interface{}) bool {
+		return b["title"] != nil && b["title"] != "" && reflect.DeepEqual(b["title"], o["title"])
+	},
+	func(b, o map[string]interface{}) bool {
+		return b["gridPos"] != nil && reflect.DeepEqual(b["gridPos"], o["gridPos"])
+	},
+}
+
+// PanelKeys returns keys of the panels of base followed by keys of the panels of every other
+// list. A panel matched to a base panel gets the key of the base panel. Other panels are
+// identified by their id, or by their position if they have no id. Panels which are not
+// matched to base are new in their list, so they get keys of their own unless the same panel
+// was added to several lists.
+func PanelKeys(base []interface{}, others ...[]interface{}) [][]string {
+	baseKeys := itemKeys(base, "id")
+	keys := [][]string{baseKeys}
+	taken := make(map[string]bool, len(baseKeys))
+	for _, key := range baseKeys {
+		taken[key] = true
+	}
+	added := map[string]interface{}{}
+	for _, other := range others {
+		keys = append(keys, matchPanels(base, baseKeys, other, taken, added))
+	}
+	return keys
+}
+
+// NameKeys returns keys of named items, like template variables or annotations. Items
+// without a name are identified by their position.
+func NameKeys(list []interface{}) []string {
+	return itemKeys(list, "name")
+}
+
+// matchPanels returns keys of the panels of other. Keys of panels which are not matched to
+// base are added to taken, and the panels to added.
+func matchPanels(base []interface{}, baseKeys []string, other []interface{}, taken map[string]bool, added map[string]interface{}) []string {
+	keys := make([]string, len(other))
+	used := make(map[int]bool, len(base))
+	for _, same := range panelMatchers {
+		for oi, o := range other {
+			op, ok := o.(map[string]interface{})
+			if !ok || keys[oi] != "" {
+				continue
+			}
+			for bi, b := range base {
+				if bp, ok := b.(map[string]interface{}); ok && !used[bi] && same(bp, op) {
+					keys[oi] = baseKeys[bi]
+					used[bi] = true
+					break
+				}
+			}
+		}
+	}
+
+	// keys of panels which were not matched must not clash with keys of base panels,
+	// nor with keys of panels added to other lists
+	for oi, key := range itemKeys(other, "id") {
+		if keys[oi] != "" {
+			continue
+		}
+		if panel, ok := added[key]; ok && reflect.DeepEqual(panel, other[oi]) {
+			keys[oi] = key
+			continue
+		}
+		unique := key
+		for n := 0; taken[unique]; n++ {
+			unique = fmt.Sprintf("%s#new%d", key, oi)
+			if n > 0 {
+				unique = fmt.Sprintf("%s#%d", unique, n)
+			}
+		}
+		keys[oi] = unique
+		taken[unique] = true
+		added[unique] = other[oi]
+	}
+	return keys
+}
+
+// itemKeys returns keys of list items by the value of idField, or by their position.
+func itemKeys(list []interface{}, idField string) []string {
+	keys := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for pos, item := range list {
+		key := fmt.Sprintf("#%d", pos)
+		if obj, ok := item.(map[string]interface{}); ok {
+			if id, ok := obj[idField]; ok && id != nil {
+				key = fmt.Sprint(id)
+			}
+		}
+		if seen[key] {
+			key = fmt.Sprintf("%s#%d", key, pos)
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnionKeys returns sorted keys of all maps, so that changes are reported in a stable order.
+func UnionKeys(maps ...map[string]interface{}) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ToMap converts the model to plain JSON values, so that values decoded
+// differently (e.g. json.Number and float64) compare equal.
+func ToMap(data *simplejson.Json) (map[string]interface{}, error) {
+	if data == nil {
+		return map[string]interface{}{}, nil
+	}
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
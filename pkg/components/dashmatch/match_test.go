@@ -0,0 +1,76 @@
+package dashmatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func list(t *testing.T, s string) []interface{} {
+	t.Helper()
+	var l []interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &l))
+	return l
+}
+
+func TestPanelKeys(t *testing.T) {
+	base := list(t, `[
+		{"id": 1, "title": "CPU", "gridPos": {"x": 0, "y": 0}},
+		{"id": 2, "title": "Memory", "gridPos": {"x": 12, "y": 0}},
+		{"id": 3, "title": "", "gridPos": {"x": 0, "y": 8}}
+	]`)
+
+	t.Run("Should match panels by id, then by title, then by position", func(t *testing.T) {
+		other := list(t, `[
+			{"id": 7, "title": "", "gridPos": {"x": 0, "y": 8}},
+			{"id": 5, "title": "Memory", "gridPos": {"x": 0, "y": 16}},
+			{"id": 1, "title": "CPU usage", "gridPos": {"x": 0, "y": 0}}
+		]`)
+
+		keys := PanelKeys(base, other)
+		require.Len(t, keys, 2)
+		assert.Equal(t, []string{"1", "2", "3"}, keys[0])
+		assert.Equal(t, []string{"3", "2", "1"}, keys[1])
+	})
+
+	t.Run("Should not give unmatched panels keys of base panels", func(t *testing.T) {
+		other := list(t, `[
+			{"id": 1, "title": "CPU", "gridPos": {"x": 0, "y": 0}},
+			{"id": 1, "title": "Disk", "gridPos": {"x": 0, "y": 24}},
+			{"id": 4, "title": "Network", "gridPos": {"x": 12, "y": 24}}
+		]`)
+
+		keys := PanelKeys(base, other)
+		assert.Equal(t, []string{"1", "1#1", "4"}, keys[1])
+
+		keys = PanelKeys(list(t, `[{"title": "CPU"}]`), list(t, `[{"title": "Disk"}]`))
+		assert.Equal(t, []string{"#0"}, keys[0])
+		assert.Equal(t, []string{"#0#new0"}, keys[1])
+	})
+
+	t.Run("Should match every list against base", func(t *testing.T) {
+		current := list(t, `[{"id": 9, "title": "CPU"}]`)
+		incoming := list(t, `[{"id": 4, "title": "Network"}, {"id": 2, "title": "Memory"}]`)
+
+		keys := PanelKeys(base, current, incoming)
+		require.Len(t, keys, 3)
+		assert.Equal(t, []string{"1"}, keys[1])
+		assert.Equal(t, []string{"4", "2"}, keys[2])
+	})
+
+	t.Run("Should give panels added to several lists different keys", func(t *testing.T) {
+		current := list(t, `[{"id": 4, "title": "Network"}, {"id": 5, "title": "Uptime"}]`)
+		incoming := list(t, `[{"id": 4, "title": "Disk"}, {"id": 5, "title": "Uptime"}]`)
+
+		keys := PanelKeys(base, current, incoming)
+		assert.Equal(t, []string{"4", "5"}, keys[1])
+		assert.Equal(t, []string{"4#new0", "5"}, keys[2])
+	})
+}
+
+func TestNameKeys(t *testing.T) {
+	keys := NameKeys(list(t, `[{"name": "host"}, {"query": "x"}, {"name": "host"}]`))
+	assert.Equal(t, []string{"host", "#1", "host#2"}, keys)
+}
@@ -0,0 +1,253 @@
+// Package dashmerge implements a structural three-way merge of dashboard JSON models.
+//
+// Panels, template variables and annotations are matched across versions the same way
+// dashboard diffs match them, see dashmatch, and are merged as a whole. Other properties
+// of the dashboard are merged key by key.
+package dashmerge
+
+import (
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/components/dashmatch"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	ConflictKindProperty   = "property"
+	ConflictKindPanel      = "panel"
+	ConflictKindVariable   = "variable"
+	ConflictKindAnnotation = "annotation"
+)
+
+// Conflict is a change made to the same part of the dashboard on both sides of the merge.
+// A missing value means the part was deleted, or did not exist in the base version.
+type Conflict struct {
+	// Kind is one of property, panel, variable or annotation.
+	Kind string `json:"kind"`
+	// Key is a property name, a panel id, or a variable or annotation name. Panels and
+	// variables matched to the base version are identified by their key in the base.
+	Key      string      `json:"key"`
+	Base     interface{} `json:"base,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+	Incoming interface{} `json:"incoming,omitempty"`
+}
+
+// Result of a merge. Dashboard is only set when there are no conflicts.
+type Result struct {
+	Dashboard *simplejson.Json
+	Conflicts []Conflict
+}
+
+// keys which are owned by the stored dashboard and are never merged.
+var ignoredKeys = map[string]bool{"id": true, "uid": true, "version": true}
+
+// properties with a list of named items, e.g. templating.list, which is merged item by item.
+var nestedKeyedLists = map[string]string{
+	"templating":  ConflictKindVariable,
+	"annotations": ConflictKindAnnotation,
+}
+
+// Merge applies changes made between base and incoming to current. Base is the
+// version both current and incoming were created from. The version, id and uid of
+// the result are the ones of current.
+func Merge(base, current, incoming *simplejson.Json) (*Result, error) {
+	b, err := dashmatch.ToMap(base)
+	if err != nil {
+		return nil, err
+	}
+	c, err := dashmatch.ToMap(current)
+	if err != nil {
+		return nil, err
+	}
+	i, err := dashmatch.ToMap(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &merger{}
+	merged := map[string]interface{}{}
+	for _, key := range dashmatch.UnionKeys(b, c, i) {
+		switch {
+		case ignoredKeys[key]:
+			if v, ok := c[key]; ok {
+				merged[key] = v
+			}
+		case key == "panels" && (b[key] == nil || isList(b[key])) && isList(c[key]) && isList(i[key]):
+			bl, cl, il := listOf(b[key]), listOf(c[key]), listOf(i[key])
+			keys := dashmatch.PanelKeys(bl, cl, il)
+			panels, panelKeys := m.mergeList(ConflictKindPanel, keys, bl, cl, il)
+			merged[key] = renumberAddedPanels(panels, panelKeys, addedKeys(keys[2], keys[0], keys[1]))
+		case nestedKeyedLists[key] != "" && isObject(c[key]) && isObject(i[key]):
+			merged[key] = m.mergeNested(nestedKeyedLists[key], key, b[key], c[key], i[key])
+		default:
+			m.mergeValue(ConflictKindProperty, key, key, b, c, i, merged)
+		}
+	}
+
+	if len(m.conflicts) > 0 {
+		return &Result{Conflicts: m.conflicts}, nil
+	}
+	return &Result{Dashboard: simplejson.NewFromAny(merged)}, nil
+}
+
+type merger struct {
+	conflicts []Conflict
+}
+
+// mergeValue merges the value of key into result, recording a conflict reported as
+// conflictKey if both sides changed it differently.
+func (m *merger) mergeValue(kind, conflictKey, key string, b, c, i, result map[string]interface{}) {
+	bv, bok := b[key]
+	cv, cok := c[key]
+	iv, iok := i[key]
+
+	var v interface{}
+	var ok bool
+	switch {
+	case cok == iok && reflect.DeepEqual(cv, iv):
+		v, ok = cv, cok
+	case bok == cok && reflect.DeepEqual(bv, cv):
+		v, ok = iv, iok
+	case bok == iok && reflect.DeepEqual(bv, iv):
+		v, ok = cv, cok
+	default:
+		m.conflicts = append(m.conflicts, Conflict{Kind: kind, Key: conflictKey, Base: bv, Current: cv, Incoming: iv})
+		return
+	}
+	if ok {
+		result[key] = v
+	}
+}
+
+// mergeNested merges an object with a list of named items, like templating or annotations.
+func (m *merger) mergeNested(kind, parent string, b, c, i interface{}) map[string]interface{} {
+	bm, _ := b.(map[string]interface{})
+	cm := c.(map[string]interface{})
+	im := i.(map[string]interface{})
+
+	merged := map[string]interface{}{}
+	for _, key := range dashmatch.UnionKeys(bm, cm, im) {
+		if key == "list" && (bm[key] == nil || isList(bm[key])) && isList(cm[key]) && isList(im[key]) {
+			bl, cl, il := listOf(bm[key]), listOf(cm[key]), listOf(im[key])
+			keys := [][]string{dashmatch.NameKeys(bl), dashmatch.NameKeys(cl), dashmatch.NameKeys(il)}
+			merged[key], _ = m.mergeList(kind, keys, bl, cl, il)
+			continue
+		}
+		m.mergeValue(ConflictKindProperty, parent+"."+key, key, bm, cm, im, merged)
+	}
+	return merged
+}
+
+// mergeList merges lists of objects identified by keys of base, current and incoming items.
+// The order of the result follows incoming, items added only to current are appended in their order.
+// The keys of the merged items are returned as well.
+func (m *merger) mergeList(kind string, keys [][]string, b, c, i []interface{}) ([]interface{}, []string) {
+	bIdx, cIdx, iIdx := index(keys[0], b), index(keys[1], c), index(keys[2], i)
+
+	var order []string
+	seen := map[string]bool{}
+	for _, list := range [][]string{keys[2], keys[1], keys[0]} {
+		for _, key := range list {
+			if !seen[key] {
+				seen[key] = true
+				order = append(order, key)
+			}
+		}
+	}
+
+	merged := map[string]interface{}{}
+	for _, key := range order {
+		m.mergeValue(kind, key, key, bIdx, cIdx, iIdx, merged)
+	}
+
+	result := make([]interface{}, 0, len(merged))
+	resultKeys := make([]string, 0, len(merged))
+	for _, key := range order {
+		if v, ok := merged[key]; ok {
+			result = append(result, v)
+			resultKeys = append(resultKeys, key)
+		}
+	}
+	return result, resultKeys
+}
+
+// addedKeys returns the keys of items which are in none of the other lists.
+func addedKeys(keys []string, others ...[]string) map[string]bool {
+	added := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		added[key] = true
+	}
+	for _, other := range others {
+		for _, key := range other {
+			delete(added, key)
+		}
+	}
+	return added
+}
+
+// renumberAddedPanels gives panels added by incoming a new id if another panel has the same id, which is
+// the case when a panel was added on both sides. Panels in rows are taken into account.
+func renumberAddedPanels(panels []interface{}, keys []string, added map[string]bool) []interface{} {
+	ids := map[float64]bool{}
+	var maxID float64
+	var walk func(list []interface{}, keys []string)
+	walk = func(list []interface{}, keys []string) {
+		for pos, item := range list {
+			panel, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if id, ok := panel["id"].(float64); ok {
+				if keys == nil || !added[keys[pos]] {
+					ids[id] = true
+				}
+				if id > maxID {
+					maxID = id
+				}
+			}
+			walk(listOf(panel["panels"]), nil)
+		}
+	}
+	walk(panels, keys)
+
+	for pos, item := range panels {
+		panel, ok := item.(map[string]interface{})
+		if !ok || !added[keys[pos]] {
+			continue
+		}
+		id, ok := panel["id"].(float64)
+		if !ok {
+			continue
+		}
+		if ids[id] {
+			maxID++
+			id = maxID
+			panel["id"] = id
+		}
+		ids[id] = true
+	}
+	return panels
+}
+
+func index(keys []string, list []interface{}) map[string]interface{} {
+	items := make(map[string]interface{}, len(list))
+	for pos, item := range list {
+		items[keys[pos]] = item
+	}
+	return items
+}
+
+func listOf(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func isList(v interface{}) bool {
+	_, ok := v.([]interface{})
+	return ok
+}
+
+func isObject(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
@@ -0,0 +1,181 @@
+package dashmerge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/dashmatch"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func mustJSON(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+	j, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return j
+}
+
+const baseDashboard = `{
+	"id": 1,
+	"uid": "abc",
+	"version": 1,
+	"title": "Dashboard",
+	"refresh": "5s",
+	"panels": [
+		{"id": 1, "title": "CPU", "type": "timeseries"},
+		{"id": 2, "title": "Memory", "type": "timeseries"}
+	],
+	"templating": {"list": [{"name": "host", "query": "hosts"}]},
+	"annotations": {"list": [{"name": "Deploys", "enable": true}]}
+}`
+
+func TestMerge(t *testing.T) {
+	t.Run("Should apply changes made to different panels on both sides", func(t *testing.T) {
+		current := mustJSON(t, `{
+			"id": 1, "uid": "abc", "version": 3, "title": "Dashboard", "refresh": "5s",
+			"panels": [
+				{"id": 1, "title": "CPU", "type": "timeseries"},
+				{"id": 2, "title": "Memory", "type": "timeseries"},
+				{"id": 4, "title": "Network", "type": "timeseries"}
+			],
+			"templating": {"list": [{"name": "host", "query": "hosts"}, {"name": "env", "query": "envs"}]},
+			"annotations": {"list": [{"name": "Deploys", "enable": true}]}
+		}`)
+		incoming := mustJSON(t, `{
+			"id": 1, "uid": "abc", "version": 1, "title": "Dashboard", "refresh": "1m",
+			"panels": [
+				{"id": 2, "title": "Memory", "type": "stat"},
+				{"id": 3, "title": "Disk", "type": "timeseries"}
+			],
+			"templating": {"list": [{"name": "host", "query": "hosts"}]},
+			"annotations": {"list": [{"name": "Deploys", "enable": false}]}
+		}`)
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+
+		expected := mustJSON(t, `{
+			"id": 1, "uid": "abc", "version": 3, "title": "Dashboard", "refresh": "1m",
+			"panels": [
+				{"id": 2, "title": "Memory", "type": "stat"},
+				{"id": 3, "title": "Disk", "type": "timeseries"},
+				{"id": 4, "title": "Network", "type": "timeseries"}
+			],
+			"templating": {"list": [{"name": "host", "query": "hosts"}, {"name": "env", "query": "envs"}]},
+			"annotations": {"list": [{"name": "Deploys", "enable": false}]}
+		}`)
+		expectedData, err := dashmatch.ToMap(expected)
+		require.NoError(t, err)
+		actualData, err := dashmatch.ToMap(result.Dashboard)
+		require.NoError(t, err)
+		assert.Equal(t, expectedData, actualData)
+	})
+
+	t.Run("Should keep panels changed on one side only", func(t *testing.T) {
+		current := mustJSON(t, baseDashboard)
+		current.Set("version", 2)
+		current.Get("panels").GetIndex(0).Set("title", "CPU usage")
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Set("title", "Renamed")
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+		assert.Equal(t, "Renamed", result.Dashboard.Get("title").MustString())
+		assert.Equal(t, 2, result.Dashboard.Get("version").MustInt())
+		assert.Equal(t, "CPU usage", result.Dashboard.Get("panels").GetIndex(0).Get("title").MustString())
+	})
+
+	t.Run("Should return conflicts for parts changed differently on both sides", func(t *testing.T) {
+		current := mustJSON(t, baseDashboard)
+		current.Get("panels").GetIndex(0).Set("title", "CPU usage")
+		current.Get("templating").Get("list").GetIndex(0).Set("query", "servers")
+		current.Set("refresh", "10s")
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Get("panels").GetIndex(0).Set("title", "CPU load")
+		incoming.Get("templating").Set("list", []interface{}{})
+		incoming.Set("refresh", "1m")
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Nil(t, result.Dashboard)
+		require.Len(t, result.Conflicts, 3)
+
+		assert.Equal(t, ConflictKindPanel, result.Conflicts[0].Kind)
+		assert.Equal(t, "1", result.Conflicts[0].Key)
+		assert.Equal(t, "CPU usage", result.Conflicts[0].Current.(map[string]interface{})["title"])
+		assert.Equal(t, "CPU load", result.Conflicts[0].Incoming.(map[string]interface{})["title"])
+
+		assert.Equal(t, ConflictKindProperty, result.Conflicts[1].Kind)
+		assert.Equal(t, "refresh", result.Conflicts[1].Key)
+
+		assert.Equal(t, ConflictKindVariable, result.Conflicts[2].Kind)
+		assert.Equal(t, "host", result.Conflicts[2].Key)
+		assert.Nil(t, result.Conflicts[2].Incoming)
+	})
+
+	t.Run("Should match a panel which got a new id by its title", func(t *testing.T) {
+		current := mustJSON(t, baseDashboard)
+		current.Get("panels").GetIndex(0).Set("id", 5)
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Get("panels").GetIndex(0).Set("type", "stat")
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		assert.Equal(t, ConflictKindPanel, result.Conflicts[0].Kind)
+		assert.Equal(t, "1", result.Conflicts[0].Key)
+	})
+
+	t.Run("Should not conflict when both sides made the same change", func(t *testing.T) {
+		current := mustJSON(t, baseDashboard)
+		current.Get("panels").GetIndex(1).Set("type", "stat")
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Get("panels").GetIndex(1).Set("type", "stat")
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+		assert.Equal(t, "stat", result.Dashboard.Get("panels").GetIndex(1).Get("type").MustString())
+	})
+	t.Run("Should keep panels added on both sides and renumber the incoming one", func(t *testing.T) {
+		current := mustJSON(t, baseDashboard)
+		current.Set("panels", append(current.Get("panels").MustArray(),
+			map[string]interface{}{"id": 3, "title": "Disk", "type": "timeseries"}))
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Set("panels", append(incoming.Get("panels").MustArray(),
+			map[string]interface{}{"id": 3, "title": "Network", "type": "timeseries"}))
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+
+		expected := mustJSON(t, `[
+			{"id": 1, "title": "CPU", "type": "timeseries"},
+			{"id": 2, "title": "Memory", "type": "timeseries"},
+			{"id": 4, "title": "Network", "type": "timeseries"},
+			{"id": 3, "title": "Disk", "type": "timeseries"}
+		]`)
+		expectedData, err := expected.Encode()
+		require.NoError(t, err)
+		actualData, err := result.Dashboard.Get("panels").Encode()
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expectedData), string(actualData))
+	})
+
+	t.Run("Should add a panel once when both sides added the same panel", func(t *testing.T) {
+		panel := map[string]interface{}{"id": 3, "title": "Disk", "type": "timeseries"}
+		current := mustJSON(t, baseDashboard)
+		current.Set("panels", append(current.Get("panels").MustArray(), panel))
+		incoming := mustJSON(t, baseDashboard)
+		incoming.Set("panels", append(incoming.Get("panels").MustArray(), panel))
+
+		result, err := Merge(mustJSON(t, baseDashboard), current, incoming)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+		assert.Len(t, result.Dashboard.Get("panels").MustArray(), 3)
+	})
+}
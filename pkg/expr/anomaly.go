@@ -0,0 +1,184 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const (
+	// AnomalyOutputAnomaly returns a number per series that is 1 if the last point of the series
+	// is outside the bands of its baseline and 0 if it is not.
+	AnomalyOutputAnomaly = "anomaly"
+	// AnomalyOutputBands returns the expected value and the upper and lower bands of each series
+	// as series with the label "band".
+	AnomalyOutputBands = "bands"
+
+	anomalyBandLabel = "band"
+
+	defaultAnomalyDeviations = 3
+	defaultAnomalyAlpha      = 0.5
+	defaultAnomalyBeta       = 0.1
+	defaultAnomalyGamma      = 0.3
+)
+
+// AnomalyCommand is an expression command that builds a baseline for each series from its own history,
+// and detects if the series deviates from it.
+type AnomalyCommand struct {
+	VarToCheck string
+	Settings   mathexp.BaselineSettings
+	Output     string
+	refID      string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToCheck string, settings mathexp.BaselineSettings, output string) (*AnomalyCommand, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	switch output {
+	case AnomalyOutputAnomaly, AnomalyOutputBands:
+	default:
+		return nil, fmt.Errorf("anomaly output %q is not supported. Supported only: [%s,%s]", output, AnomalyOutputAnomaly, AnomalyOutputBands)
+	}
+	return &AnomalyCommand{
+		VarToCheck: varToCheck,
+		Settings:   settings,
+		Output:     output,
+		refID:      refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	rawVar, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("no expression ID to check for anomalies. must be a reference to an existing query or expression")
+	}
+	varToCheck, ok := rawVar.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected anomaly input variable to be type string, but got type %T", rawVar)
+	}
+	varToCheck = strings.TrimPrefix(varToCheck, "$")
+
+	settings := mathexp.BaselineSettings{
+		Algorithm:  mathexp.BaselineSeasonal,
+		Deviations: defaultAnomalyDeviations,
+		Alpha:      defaultAnomalyAlpha,
+		Beta:       defaultAnomalyBeta,
+		Gamma:      defaultAnomalyGamma,
+	}
+	if rawAlgorithm, ok := rn.Query["algorithm"]; ok {
+		if settings.Algorithm, ok = rawAlgorithm.(string); !ok {
+			return nil, fmt.Errorf("expected anomaly algorithm to be a string, got type %T", rawAlgorithm)
+		}
+	}
+
+	var err error
+	if settings.Interval, err = anomalyDuration(rn.Query, "interval"); err != nil {
+		return nil, err
+	}
+	if settings.Interval == 0 {
+		return nil, errors.New("no interval specified in anomaly command")
+	}
+	if settings.Season, err = anomalyDuration(rn.Query, "season"); err != nil {
+		return nil, err
+	}
+
+	for key, f := range map[string]*float64{
+		"deviations": &settings.Deviations,
+		"alpha":      &settings.Alpha,
+		"beta":       &settings.Beta,
+		"gamma":      &settings.Gamma,
+	} {
+		raw, ok := rn.Query[key]
+		if !ok {
+			continue
+		}
+		if *f, ok = raw.(float64); !ok {
+			return nil, fmt.Errorf("expected anomaly %s to be a number, got type %T", key, raw)
+		}
+	}
+
+	output := AnomalyOutputAnomaly
+	if rawOutput, ok := rn.Query["output"]; ok {
+		if output, ok = rawOutput.(string); !ok {
+			return nil, fmt.Errorf("expected anomaly output to be a string, got type %T", rawOutput)
+		}
+	}
+
+	return NewAnomalyCommand(rn.RefID, varToCheck, settings, output)
+}
+
+func anomalyDuration(query map[string]interface{}, key string) (time.Duration, error) {
+	raw, ok := query[key]
+	if !ok {
+		return 0, nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return 0, fmt.Errorf("anomaly %s is expected to be a string, got %T", key, raw)
+	}
+	d, err := gtime.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf(`failed to parse anomaly %q duration field %q: %w`, key, s, err)
+	}
+	return d, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToCheck}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToCheck].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			if v.Len() == 0 {
+				continue
+			}
+			baseline, err := v.Baseline(ac.refID, ac.Settings)
+			if err != nil {
+				return newRes, err
+			}
+			if ac.Output == AnomalyOutputBands {
+				newRes.Values = append(newRes.Values,
+					withAnomalyBand(baseline.Expected, "baseline"),
+					withAnomalyBand(baseline.Upper, "upper"),
+					withAnomalyBand(baseline.Lower, "lower"),
+				)
+				continue
+			}
+			n := mathexp.NewNumber(ac.refID, v.GetLabels())
+			n.SetValue(baseline.IsAnomaly())
+			newRes.Values = append(newRes.Values, n)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func withAnomalyBand(s mathexp.Series, band string) mathexp.Series {
+	labels := data.Labels{}
+	for k, v := range s.GetLabels() {
+		labels[k] = v
+	}
+	labels[anomalyBandLabel] = band
+	s.SetLabels(labels)
+	return s
+}
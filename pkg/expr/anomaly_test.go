@@ -0,0 +1,143 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		errorIs  require.ErrorAssertionFunc
+		expected *AnomalyCommand
+	}{
+		{
+			name:    "defaults to seasonal anomaly detection",
+			query:   `{"expression": "$A", "interval": "5m", "season": "1d"}`,
+			errorIs: require.NoError,
+			expected: &AnomalyCommand{
+				VarToCheck: "A",
+				Settings: mathexp.BaselineSettings{
+					Algorithm:  mathexp.BaselineSeasonal,
+					Interval:   5 * time.Minute,
+					Season:     24 * time.Hour,
+					Deviations: defaultAnomalyDeviations,
+					Alpha:      defaultAnomalyAlpha,
+					Beta:       defaultAnomalyBeta,
+					Gamma:      defaultAnomalyGamma,
+				},
+				Output: AnomalyOutputAnomaly,
+				refID:  "B",
+			},
+		},
+		{
+			name:    "holt winters with a weekly season and bands",
+			query:   `{"expression": "A", "algorithm": "holt_winters", "interval": "1h", "season": "1w", "deviations": 2, "alpha": 0.2, "beta": 0.05, "gamma": 0.4, "output": "bands"}`,
+			errorIs: require.NoError,
+			expected: &AnomalyCommand{
+				VarToCheck: "A",
+				Settings: mathexp.BaselineSettings{
+					Algorithm:  mathexp.BaselineHoltWinters,
+					Interval:   time.Hour,
+					Season:     7 * 24 * time.Hour,
+					Deviations: 2,
+					Alpha:      0.2,
+					Beta:       0.05,
+					Gamma:      0.4,
+				},
+				Output: AnomalyOutputBands,
+				refID:  "B",
+			},
+		},
+		{
+			name:    "missing interval",
+			query:   `{"expression": "A", "algorithm": "linear_regression"}`,
+			errorIs: require.Error,
+		},
+		{
+			name:    "seasonal without a season",
+			query:   `{"expression": "A", "interval": "1m"}`,
+			errorIs: require.Error,
+		},
+		{
+			name:    "unknown output",
+			query:   `{"expression": "A", "algorithm": "linear_regression", "interval": "1m", "output": "forecast"}`,
+			errorIs: require.Error,
+		},
+		{
+			name:    "invalid deviations",
+			query:   `{"expression": "A", "algorithm": "linear_regression", "interval": "1m", "deviations": "3"}`,
+			errorIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal([]byte(tt.query), &q))
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{RefID: "B", Query: q})
+			tt.errorIs(t, err)
+			if tt.expected != nil {
+				require.Equal(t, tt.expected, cmd)
+			}
+		})
+	}
+}
+
+func TestAnomalyCommandExecute(t *testing.T) {
+	cmd, err := NewAnomalyCommand("B", "A", mathexp.BaselineSettings{
+		Algorithm:  mathexp.BaselineLinearRegression,
+		Interval:   time.Minute,
+		Deviations: 3,
+	}, AnomalyOutputAnomaly)
+	require.NoError(t, err)
+
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{
+		makeSQLTestSeries(data.Labels{"host": "a"}, 1, 2.1, 2.9, 4, 5.1, 6),
+		makeSQLTestSeries(data.Labels{"host": "b"}, 1, 2.1, 2.9, 4, 5.1, 20),
+	}}}
+
+	t.Run("returns a number per series", func(t *testing.T) {
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"host": "a"}, res.Values[0].GetLabels())
+		require.Equal(t, 0.0, *res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, data.Labels{"host": "b"}, res.Values[1].GetLabels())
+		require.Equal(t, 1.0, *res.Values[1].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("returns bands per series", func(t *testing.T) {
+		bands := *cmd
+		bands.Output = AnomalyOutputBands
+		res, err := bands.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 6)
+		for i, band := range []string{"baseline", "upper", "lower"} {
+			require.Equal(t, data.Labels{"host": "a", "band": band}, res.Values[i].GetLabels())
+			require.Equal(t, 6, res.Values[i].(mathexp.Series).Len())
+		}
+	})
+
+	t.Run("passes through no data", func(t *testing.T) {
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}})
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, parse.TypeNoData, res.Values[0].Type())
+	})
+
+	t.Run("numbers are rejected", func(t *testing.T) {
+		_, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{
+			makeSQLTestNumber("A", nil, 1),
+		}}})
+		require.Error(t, err)
+	})
+}
@@ -45,6 +45,10 @@ type condition struct {
 	// min and max functions.
 	Reducer reducer
 
+	// ReducerParams are the parameters of parameterised reducers, such as the percentile of the
+	// percentile reducer.
+	ReducerParams []float64
+
 	// Evaluator evaluates the reduced time series, instant metric, or result of another expression
 	// against an evaluator. An example of an evaluator is checking if it exceeds a threshold,
 	// falls within a range, or does not contain a value.
@@ -174,7 +178,7 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 			number = v
 		case mathexp.Series:
 			name = v.GetName()
-			number = cond.Reducer.Reduce(v)
+			number = cond.Reducer.Reduce(v, cond.ReducerParams...)
 		default:
 			return false, false, nil, fmt.Errorf("can only reduce type series, got type %v", v.Type())
 		}
@@ -269,7 +273,8 @@ type ConditionQueryJSON struct {
 
 type ConditionReducerJSON struct {
 	Type string `json:"type"`
-	// Params []interface{} `json:"params"` (Unused)
+	// Params are only used by parameterised reducers, such as percentile.
+	Params []interface{} `json:"params"`
 }
 
 // UnmarshalConditionsCmd creates a new ConditionsCmd.
@@ -306,6 +311,16 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 			return nil, fmt.Errorf("invalid reducer '%v' in condition %v", cond.Reducer, i+1)
 		}
 
+		if cond.Reducer == "percentile" {
+			cond.ReducerParams, err = parseReducerParams(cj.Reducer.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reducer parameters in condition %v: %w", i+1, err)
+			}
+		}
+		if err := cond.Reducer.ValidateParams(cond.ReducerParams); err != nil {
+			return nil, fmt.Errorf("invalid reducer in condition %v: %w", i+1, err)
+		}
+
 		cond.Evaluator, err = newAlertEvaluator(cj.Evaluator)
 		if err != nil {
 			return nil, err
@@ -316,3 +331,23 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 
 	return c, nil
 }
+
+// parseReducerParams converts the parameters of a reducer into numbers. Numeric strings are accepted as well.
+func parseReducerParams(raw []interface{}) ([]float64, error) {
+	params := make([]float64, 0, len(raw))
+	for _, r := range raw {
+		switch v := r.(type) {
+		case float64:
+			params = append(params, v)
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %q is not a number", v)
+			}
+			params = append(params, f)
+		default:
+			return nil, fmt.Errorf("parameter must be a number, got %T", r)
+		}
+	}
+	return params, nil
+}
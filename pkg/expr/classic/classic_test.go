@@ -691,6 +691,46 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 			},
 			needsVars: []string{"A"},
 		},
+		{
+			name: "percentile condition",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						2
+					  ],
+					  "type": "gt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A"
+					  ]
+					},
+					"reducer": {
+					  "params": [95],
+					  "type": "percentile"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID:    "A",
+						Reducer:       reducer("percentile"),
+						ReducerParams: []float64{95},
+						Operator:      "and",
+						Evaluator:     &thresholdEvaluator{Type: "gt", Threshold: 2},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -706,4 +746,23 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 			require.Equal(t, tt.needsVars, cmd.NeedsVars())
 		})
 	}
+
+	t.Run("percentile condition without parameter fails", func(t *testing.T) {
+		rawJSON := `{
+			"conditions": [
+			  {
+				"evaluator": { "params": [2], "type": "gt" },
+				"operator": { "type": "and" },
+				"query": { "params": ["A"] },
+				"reducer": { "params": [], "type": "percentile" },
+				"type": "query"
+			  }
+			]
+		}`
+		var rq map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(rawJSON), &rq))
+
+		_, err := UnmarshalConditionsCmd(rq, "")
+		require.Error(t, err)
+	})
 }
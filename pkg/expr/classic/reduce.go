@@ -1,8 +1,10 @@
 package classic
 
 import (
+	"fmt"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
@@ -15,12 +17,30 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "percentile", "stddev", "range", "increase", "rate":
+		return true
 	}
 	return false
 }
 
+// ValidateParams checks that the parameters are valid for the reducer.
+func (cr reducer) ValidateParams(params []float64) error {
+	if cr != "percentile" {
+		return nil
+	}
+	if len(params) != 1 {
+		return fmt.Errorf("reducer %v requires exactly one parameter, got %d", cr, len(params))
+	}
+	if params[0] < 0 || params[0] > 100 || math.IsNaN(params[0]) {
+		return fmt.Errorf("reducer %v requires a parameter between 0 and 100, got %v", cr, params[0])
+	}
+	return nil
+}
+
+// Reduce reduces the series to a single number. Parameterised reducers, such as percentile, read their parameters from params.
+//
 //nolint:gocyclo
-func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
+func (cr reducer) Reduce(series mathexp.Series, params ...float64) mathexp.Number {
 	num := mathexp.NewNumber("", nil)
 
 	if series.GetLabels() != nil {
@@ -144,6 +164,60 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "percentile":
+		values := nonNullValues(ff)
+		if len(values) >= 1 && len(params) == 1 {
+			value = mathexp.PercentileOf(values, params[0])
+			allNull = false
+		}
+	case "stddev":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			value = mathexp.StddevOf(values)
+			allNull = false
+		}
+	case "range":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			sort.Float64s(values)
+			value = values[len(values)-1] - values[0]
+			allNull = false
+		}
+	case "increase":
+		values := nonNullValues(ff)
+		if len(values) >= 2 {
+			value = mathexp.CounterIncrease(values)
+			allNull = false
+		}
+	case "rate":
+		var (
+			values      []float64
+			first, last time.Time
+		)
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if nilOrNaN(f) {
+				continue
+			}
+			if len(values) == 0 {
+				first = series.GetTime(i)
+			}
+			last = series.GetTime(i)
+			values = append(values, *f)
+		}
+		if elapsed := last.Sub(first).Seconds(); len(values) >= 2 && elapsed > 0 {
+			value = mathexp.CounterIncrease(values) / elapsed
+			allNull = false
+		}
 	}
 
 	if allNull {
@@ -182,6 +256,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// nonNullValues returns all values of the field that are neither null nor NaN.
+func nonNullValues(ff mathexp.Float64Field) []float64 {
+	values := make([]float64, 0, ff.Len())
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
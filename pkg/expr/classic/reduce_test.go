@@ -126,6 +126,83 @@ func TestReducer(t *testing.T) {
 	}
 }
 
+func TestExtendedReducers(t *testing.T) {
+	counter := newSeries(util.Pointer(10.0), nil, util.Pointer(20.0), util.Pointer(5.0), util.Pointer(15.0))
+
+	var tests = []struct {
+		name           string
+		reducer        reducer
+		params         []float64
+		inputSeries    mathexp.Series
+		expectedNumber mathexp.Number
+	}{
+		{
+			name:           "first skips nulls",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN()), util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "percentile",
+			reducer:        reducer("percentile"),
+			params:         []float64{90},
+			inputSeries:    newSeries(util.Pointer(10.0), util.Pointer(20.0), util.Pointer(5.0), util.Pointer(15.0), util.Pointer(25.0)),
+			expectedNumber: newNumber(util.Pointer(23.0)),
+		},
+		{
+			name:           "percentile with only nulls",
+			reducer:        reducer("percentile"),
+			params:         []float64{90},
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), nil, util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    counter,
+			expectedNumber: newNumber(util.Pointer(15.0)),
+		},
+		{
+			name:           "increase handles counter resets",
+			reducer:        reducer("increase"),
+			inputSeries:    counter,
+			expectedNumber: newNumber(util.Pointer(25.0)),
+		},
+		{
+			name:           "increase with a single point",
+			reducer:        reducer("increase"),
+			inputSeries:    newSeries(util.Pointer(1.0), nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "rate handles counter resets",
+			reducer:        reducer("rate"),
+			inputSeries:    counter,
+			expectedNumber: newNumber(util.Pointer(25.0 / 4)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, tt.reducer.ValidReduceFunc())
+			require.NoError(t, tt.reducer.ValidateParams(tt.params))
+			num := tt.reducer.Reduce(tt.inputSeries, tt.params...)
+			require.Equal(t, tt.expectedNumber, num)
+		})
+	}
+
+	t.Run("percentile requires a valid parameter", func(t *testing.T) {
+		require.Error(t, reducer("percentile").ValidateParams(nil))
+		require.Error(t, reducer("percentile").ValidateParams([]float64{-1}))
+		require.Error(t, reducer("percentile").ValidateParams([]float64{90, 95}))
+	})
+}
+
 func TestDiffReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
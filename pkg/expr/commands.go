@@ -72,24 +72,27 @@ func (gm *MathCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars
 
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
 type ReduceCommand struct {
-	Reducer      string
-	VarToReduce  string
-	refID        string
-	seriesMapper mathexp.ReduceMapper
+	Reducer string
+	// ReducerParams are the parameters of parameterised reducers, such as the percentile of the percentile reducer.
+	ReducerParams []float64
+	VarToReduce   string
+	refID         string
+	seriesMapper  mathexp.ReduceMapper
 }
 
 // NewReduceCommand creates a new ReduceCMD.
-func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper, params ...float64) (*ReduceCommand, error) {
+	_, err := mathexp.GetSeriesReduceFunc(reducer, params...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ReduceCommand{
-		Reducer:      reducer,
-		VarToReduce:  varToReduce,
-		refID:        refID,
-		seriesMapper: mapper,
+		Reducer:       reducer,
+		ReducerParams: params,
+		VarToReduce:   varToReduce,
+		refID:         refID,
+		seriesMapper:  mapper,
 	}, nil
 }
 
@@ -114,6 +117,21 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T", rawReducer)
 	}
 
+	var params []float64
+	if rawParams, ok := rn.Query["reducerParams"]; ok {
+		rawList, ok := rawParams.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("reducerParams must be an array, got %T", rawParams)
+		}
+		for _, rawParam := range rawList {
+			param, ok := rawParam.(float64)
+			if !ok {
+				return nil, fmt.Errorf("reducer parameter must be a number, got %T", rawParam)
+			}
+			params = append(params, param)
+		}
+	}
+
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
@@ -143,7 +161,7 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper, params...)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -159,7 +177,7 @@ func (gr *ReduceCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Va
 	for _, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper, gr.ReducerParams...)
 			if err != nil {
 				return newRes, err
 			}
@@ -302,6 +320,10 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeSQL is the CMDType for running a SQL query over the results of other queries.
+	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies against a baseline built from the history of a series.
+	TypeAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -314,6 +336,12 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeThreshold:
+		return "threshold"
+	case TypeSQL:
+		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
 	default:
 		return "unknown"
 	}
@@ -332,6 +360,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "sql":
+		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -97,9 +97,70 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_Params(t *testing.T) {
+	var tests = []struct {
+		name           string
+		query          string
+		isError        bool
+		expectedParams []float64
+	}{
+		{
+			name:           "no params when reducerParams is not specified",
+			query:          `{ "expression" : "$A", "reducer": "sum" }`,
+			expectedParams: nil,
+		},
+		{
+			name:           "parses params of percentile",
+			query:          `{ "expression" : "$A", "reducer": "percentile", "reducerParams": [95] }`,
+			expectedParams: []float64{95},
+		},
+		{
+			name:    "error if percentile has no params",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error if percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": [101] }`,
+			isError: true,
+		},
+		{
+			name:    "error if reducerParams is not an array",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": 95 }`,
+			isError: true,
+		},
+		{
+			name:    "error if a param is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": ["95"] }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NotNil(t, cmd)
+			require.Equal(t, test.expectedParams, cmd.ReducerParams)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
-	cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
+	// The parameter is only used by parameterised reducers, such as percentile.
+	cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil, 50)
 	require.NoError(t, err)
 
 	t.Run("should noop if Number", func(t *testing.T) {
@@ -49,12 +49,34 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	// results of data source queries as they are, for SQL expressions
+	tables := make(mathexp.Vars)
 	for _, node := range *dp {
-		res, err := node.Execute(c, now, vars, s)
+		nodeVars := vars
+		if cmdNode, ok := node.(*CMDNode); ok && cmdNode.CMDType == TypeSQL && len(tables) > 0 {
+			nodeVars = make(mathexp.Vars, len(vars))
+			for refID, res := range vars {
+				nodeVars[refID] = res
+			}
+			for refID, res := range tables {
+				nodeVars[refID] = res
+			}
+		}
+
+		res, err := node.Execute(c, now, nodeVars, s)
 		if err != nil {
 			return nil, err
 		}
 
+		// Other consumers and the caller get the results of queries used by SQL expressions as numbers or series.
+		if dsNode, ok := node.(*DSNode); ok && dsNode.isInputToSQLExpr {
+			tables[node.RefID()] = res
+			res, err = dsNode.readTableData(c, res)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		vars[node.RefID()] = res
 	}
 	return vars, nil
@@ -203,6 +225,21 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 				}
 			}
 
+			if dsNode, ok := neededNode.(*DSNode); ok {
+				if cmdNode.CMDType == TypeSQL {
+					dsNode.isInputToSQLExpr = true
+				} else {
+					dsNode.isInputToOtherExpr = true
+				}
+			}
+
+			if cmdNode.CMDType == TypeAnomaly && neededNode.NodeType() == TypeCMDNode {
+				switch neededNode.(*CMDNode).CMDType {
+				case TypeReduce, TypeThreshold:
+					return fmt.Errorf("anomaly detection needs a time series input, but %v returns numbers", neededVar)
+				}
+			}
+
 			if neededNode.NodeType() == TypeCMDNode {
 				if neededNode.(*CMDNode).CMDType == TypeClassicConditions {
 					return fmt.Errorf("classic conditions may not be the input for other expressions, but %v is the input for %v", neededVar, cmdNode.RefID())
@@ -0,0 +1,300 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// BaselineSeasonal uses the average of the values at the same time of the previous seasons as the expected value.
+	BaselineSeasonal = "seasonal"
+	// BaselineHoltWinters uses the one step ahead forecast of additive Holt-Winters (triple exponential) smoothing.
+	// Without a season it falls back to Holt's linear (double exponential) smoothing.
+	BaselineHoltWinters = "holt_winters"
+	// BaselineLinearRegression uses a least squares line fitted through all but the last point.
+	BaselineLinearRegression = "linear_regression"
+)
+
+// BaselineSettings configures how the baseline of a series is calculated.
+type BaselineSettings struct {
+	Algorithm string
+	// Interval is the interval the series is resampled to before the baseline is calculated.
+	Interval time.Duration
+	// Season is the length of the seasonal period, such as a day or a week. It is required by
+	// the seasonal algorithm and optional for Holt-Winters.
+	Season time.Duration
+	// Deviations is the distance of the bands from the expected value, in standard deviations of the residuals.
+	Deviations float64
+	// Alpha, Beta and Gamma are the level, trend and seasonal smoothing factors of Holt-Winters.
+	Alpha float64
+	Beta  float64
+	Gamma float64
+}
+
+// Validate returns an error if the settings can not be used to calculate a baseline.
+func (s BaselineSettings) Validate() error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if s.Deviations <= 0 {
+		return fmt.Errorf("deviations must be positive")
+	}
+	if s.Season < 0 {
+		return fmt.Errorf("season must not be negative")
+	}
+	if s.Season > 0 && s.Season < 2*s.Interval {
+		return fmt.Errorf("season %s must be at least twice the interval %s", s.Season, s.Interval)
+	}
+	switch s.Algorithm {
+	case BaselineSeasonal:
+		if s.Season == 0 {
+			return fmt.Errorf("the %s algorithm requires a season", BaselineSeasonal)
+		}
+	case BaselineHoltWinters:
+		for name, f := range map[string]float64{"alpha": s.Alpha, "beta": s.Beta, "gamma": s.Gamma} {
+			if f <= 0 || f > 1 {
+				return fmt.Errorf("%s must be greater than 0 and at most 1, got %v", name, f)
+			}
+		}
+	case BaselineLinearRegression:
+	default:
+		return fmt.Errorf("baseline algorithm %q is not supported. Supported only: [%s,%s,%s]", s.Algorithm, BaselineSeasonal, BaselineHoltWinters, BaselineLinearRegression)
+	}
+	return nil
+}
+
+// Baseline holds the expected values of a series and the bands around them.
+// All series have the same timestamps as Resampled.
+type Baseline struct {
+	// Resampled is the series the baseline was calculated for, resampled to the interval of the settings.
+	Resampled Series
+	Expected  Series
+	Upper     Series
+	Lower     Series
+}
+
+// Baseline resamples the series to the interval of the settings and calculates the expected value
+// and the bands of each point from the history of the series.
+func (s Series) Baseline(refID string, settings BaselineSettings) (Baseline, error) {
+	if err := settings.Validate(); err != nil {
+		return Baseline{}, err
+	}
+	if s.Len() == 0 {
+		return Baseline{}, fmt.Errorf("can not calculate the baseline of an empty series")
+	}
+
+	sorted := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		sorted.SetPoint(i, t, f)
+	}
+	sorted.SortByTime(false)
+	from := sorted.GetTime(0).Truncate(settings.Interval)
+	to := sorted.GetTime(sorted.Len() - 1).Truncate(settings.Interval)
+	if to.Before(sorted.GetTime(sorted.Len() - 1)) {
+		to = to.Add(settings.Interval)
+	}
+	resampled, err := sorted.Resample(refID, settings.Interval, "mean", "fillna", from, to)
+	if err != nil {
+		return Baseline{}, err
+	}
+
+	values := make([]float64, resampled.Len())
+	for i := range values {
+		values[i] = math.NaN()
+		if f := resampled.GetValue(i); f != nil {
+			values[i] = *f
+		}
+	}
+
+	season := int(settings.Season / settings.Interval)
+	var expected []float64
+	switch settings.Algorithm {
+	case BaselineSeasonal:
+		expected = seasonalBaseline(values, season)
+	case BaselineHoltWinters:
+		expected = holtWintersBaseline(values, season, settings.Alpha, settings.Beta, settings.Gamma)
+	case BaselineLinearRegression:
+		expected = linearRegressionBaseline(values)
+	}
+
+	width := settings.Deviations * residualStddev(values, expected)
+	b := Baseline{
+		Resampled: resampled,
+		Expected:  NewSeries(refID, s.GetLabels(), resampled.Len()),
+		Upper:     NewSeries(refID, s.GetLabels(), resampled.Len()),
+		Lower:     NewSeries(refID, s.GetLabels(), resampled.Len()),
+	}
+	for i, e := range expected {
+		t := resampled.GetTime(i)
+		b.Expected.SetPoint(i, t, nanToNil(e))
+		b.Upper.SetPoint(i, t, nanToNil(e+width))
+		b.Lower.SetPoint(i, t, nanToNil(e-width))
+	}
+	return b, nil
+}
+
+// IsAnomaly returns 1 if the last point of the resampled series is outside the bands and 0 if it is not.
+// It returns nil if the last point or its bands are unknown.
+func (b Baseline) IsAnomaly() *float64 {
+	last := b.Resampled.Len() - 1
+	if last < 0 {
+		return nil
+	}
+	v, upper, lower := b.Resampled.GetValue(last), b.Upper.GetValue(last), b.Lower.GetValue(last)
+	if v == nil || upper == nil || lower == nil || math.IsNaN(*v) {
+		return nil
+	}
+	anomaly := 0.0
+	if *v > *upper || *v < *lower {
+		anomaly = 1
+	}
+	return &anomaly
+}
+
+// seasonalBaseline returns for each point the average of the points at the same position in the previous seasons.
+func seasonalBaseline(values []float64, season int) []float64 {
+	expected := make([]float64, len(values))
+	for i := range values {
+		var sum float64
+		var count int
+		for j := i - season; j >= 0; j -= season {
+			if !math.IsNaN(values[j]) {
+				sum += values[j]
+				count++
+			}
+		}
+		expected[i] = math.NaN()
+		if count > 0 {
+			expected[i] = sum / float64(count)
+		}
+	}
+	return expected
+}
+
+// holtWintersBaseline returns for each point the forecast made from the points before it, using additive Holt-Winters
+// smoothing. The first season is used to initialize the level and the seasonal components, and has no forecast.
+// When season is zero, Holt's linear smoothing is used, and the first point has no forecast.
+func holtWintersBaseline(values []float64, season int, alpha, beta, gamma float64) []float64 {
+	expected := make([]float64, len(values))
+	for i := range expected {
+		expected[i] = math.NaN()
+	}
+
+	initLen := season
+	if initLen == 0 {
+		initLen = 1
+	}
+	if len(values) <= initLen {
+		return expected
+	}
+
+	level := meanOfValid(values[:initLen])
+	if math.IsNaN(level) {
+		return expected
+	}
+	trend := 0.0
+	if season > 0 && len(values) >= 2*season {
+		if next := meanOfValid(values[season : 2*season]); !math.IsNaN(next) {
+			trend = (next - level) / float64(season)
+		}
+	}
+	seasonal := make([]float64, initLen)
+	if season > 0 {
+		for i := 0; i < season; i++ {
+			if !math.IsNaN(values[i]) {
+				seasonal[i] = values[i] - level
+			}
+		}
+	}
+
+	for i := initLen; i < len(values); i++ {
+		phase := 0
+		if season > 0 {
+			phase = i % season
+		}
+		forecast := level + trend + seasonal[phase]
+		expected[i] = forecast
+		if math.IsNaN(values[i]) {
+			// missing points do not update the model, which carries on with its forecast
+			level += trend
+			continue
+		}
+		lastLevel := level
+		level = alpha*(values[i]-seasonal[phase]) + (1-alpha)*(level+trend)
+		trend = beta*(level-lastLevel) + (1-beta)*trend
+		if season > 0 {
+			seasonal[phase] = gamma*(values[i]-level) + (1-gamma)*seasonal[phase]
+		}
+	}
+	return expected
+}
+
+// linearRegressionBaseline fits a line through all points but the last one, so that the last point
+// does not influence its own expected value.
+func linearRegressionBaseline(values []float64) []float64 {
+	expected := make([]float64, len(values))
+	var n, sumX, sumY, sumXY, sumXX float64
+	for i := 0; i < len(values)-1; i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+		x := float64(i)
+		n++
+		sumX += x
+		sumY += values[i]
+		sumXY += x * values[i]
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	for i := range expected {
+		expected[i] = math.NaN()
+		if n < 2 || denominator == 0 {
+			continue
+		}
+		slope := (n*sumXY - sumX*sumY) / denominator
+		intercept := (sumY - slope*sumX) / n
+		expected[i] = intercept + slope*float64(i)
+	}
+	return expected
+}
+
+// residualStddev returns the standard deviation of the differences between the values and the expected values.
+// The last point is left out, so that an anomaly does not widen its own bands.
+// It returns NaN if there are less than two points with both a value and an expected value.
+func residualStddev(values, expected []float64) float64 {
+	residuals := make([]float64, 0, len(values))
+	for i := 0; i < len(values)-1; i++ {
+		if math.IsNaN(values[i]) || math.IsNaN(expected[i]) {
+			continue
+		}
+		residuals = append(residuals, values[i]-expected[i])
+	}
+	if len(residuals) < 2 {
+		return math.NaN()
+	}
+	return StddevOf(residuals)
+}
+
+func meanOfValid(values []float64) float64 {
+	var sum float64
+	var count int
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum / float64(count)
+}
+
+func nanToNil(f float64) *float64 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return &f
+}
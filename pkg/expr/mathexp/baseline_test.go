@@ -0,0 +1,127 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesBaseline(t *testing.T) {
+	// four seasons of four points with a small amount of noise and a spike at the end
+	seasonal := []float64{10, 20, 30, 20, 11, 21, 29, 19, 10, 19, 31, 21, 9, 20, 30, 80}
+	// a line with a small amount of noise and a drop at the end
+	linear := []float64{0, 1.1, 1.9, 3, 4.1, 5, 5.9, 7, 8, 9.1, 0}
+
+	tests := []struct {
+		name     string
+		values   []float64
+		settings BaselineSettings
+		anomaly  *float64
+		expected *float64
+	}{
+		{
+			name:     "seasonal detects a spike",
+			values:   seasonal,
+			settings: BaselineSettings{Algorithm: BaselineSeasonal, Interval: time.Minute, Season: 4 * time.Minute, Deviations: 3},
+			anomaly:  float64Pointer(1),
+			expected: float64Pointer(20),
+		},
+		{
+			name:     "seasonal does not flag a normal point",
+			values:   seasonal[:15],
+			settings: BaselineSettings{Algorithm: BaselineSeasonal, Interval: time.Minute, Season: 4 * time.Minute, Deviations: 3},
+			anomaly:  float64Pointer(0),
+			expected: float64Pointer(30),
+		},
+		{
+			name:     "holt winters detects a spike",
+			values:   seasonal,
+			settings: BaselineSettings{Algorithm: BaselineHoltWinters, Interval: time.Minute, Season: 4 * time.Minute, Deviations: 3, Alpha: 0.5, Beta: 0.1, Gamma: 0.3},
+			anomaly:  float64Pointer(1),
+		},
+		{
+			name:     "holt without a season follows the trend",
+			values:   linear[:10],
+			settings: BaselineSettings{Algorithm: BaselineHoltWinters, Interval: time.Minute, Deviations: 3, Alpha: 0.8, Beta: 0.8, Gamma: 0.5},
+			anomaly:  float64Pointer(0),
+		},
+		{
+			name:     "linear regression detects a drop",
+			values:   linear,
+			settings: BaselineSettings{Algorithm: BaselineLinearRegression, Interval: time.Minute, Deviations: 3},
+			anomaly:  float64Pointer(1),
+		},
+		{
+			name:     "not enough history is unknown",
+			values:   seasonal[:4],
+			settings: BaselineSettings{Algorithm: BaselineSeasonal, Interval: time.Minute, Season: 4 * time.Minute, Deviations: 3},
+			anomaly:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSeries("A", data.Labels{"host": "a"}, len(tt.values))
+			for i := range tt.values {
+				s.SetPoint(i, time.Unix(int64(60*i), 0), &tt.values[i])
+			}
+			b, err := s.Baseline("A", tt.settings)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.values), b.Resampled.Len())
+			require.Equal(t, data.Labels{"host": "a"}, b.Upper.GetLabels())
+			require.Equal(t, tt.anomaly, b.IsAnomaly())
+			if tt.expected != nil {
+				require.InDelta(t, *tt.expected, *b.Expected.GetValue(b.Expected.Len() - 1), 1)
+			}
+		})
+	}
+}
+
+func TestSeriesBaselineResamples(t *testing.T) {
+	s := NewSeries("A", nil, 3)
+	s.SetPoint(0, time.Unix(0, 0), float64Pointer(1))
+	s.SetPoint(1, time.Unix(30, 0), float64Pointer(3))
+	s.SetPoint(2, time.Unix(120, 0), float64Pointer(5))
+
+	b, err := s.Baseline("A", BaselineSettings{Algorithm: BaselineLinearRegression, Interval: time.Minute, Deviations: 3})
+	require.NoError(t, err)
+	require.Equal(t, 3, b.Resampled.Len())
+	require.Equal(t, float64Pointer(1), b.Resampled.GetValue(0))
+	require.Equal(t, float64Pointer(3), b.Resampled.GetValue(1))
+	require.Equal(t, float64Pointer(5), b.Resampled.GetValue(2))
+}
+
+func TestBaselineSettingsValidate(t *testing.T) {
+	valid := BaselineSettings{Algorithm: BaselineHoltWinters, Interval: time.Minute, Season: time.Hour, Deviations: 2, Alpha: 0.5, Beta: 0.5, Gamma: 0.5}
+	require.NoError(t, valid.Validate())
+
+	tests := map[string]func(s *BaselineSettings){
+		"unknown algorithm":            func(s *BaselineSettings) { s.Algorithm = "magic" },
+		"no interval":                  func(s *BaselineSettings) { s.Interval = 0 },
+		"no deviations":                func(s *BaselineSettings) { s.Deviations = 0 },
+		"season shorter than interval": func(s *BaselineSettings) { s.Season = time.Minute },
+		"alpha out of range":           func(s *BaselineSettings) { s.Alpha = 1.5 },
+		"seasonal without season":      func(s *BaselineSettings) { s.Algorithm = BaselineSeasonal; s.Season = 0 },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := valid
+			change(&s)
+			require.Error(t, s.Validate())
+		})
+	}
+}
+
+func TestHoltWintersBaselineFollowsSeasonality(t *testing.T) {
+	values := make([]float64, 0, 40)
+	for i := 0; i < 40; i++ {
+		values = append(values, float64(i%4)*10)
+	}
+	expected := holtWintersBaseline(values, 4, 0.5, 0.1, 0.3)
+	for i := 0; i < 4; i++ {
+		require.True(t, math.IsNaN(expected[i]))
+	}
+	require.InDelta(t, values[39], expected[39], 0.5)
+}
@@ -0,0 +1,423 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// The functions in this file act on whole series or on sets of series rather than on single values.
+// Window functions expect the points of a series to be sorted by time, oldest first.
+
+// movingAvg returns, for each point of each series, the average of the last n points, including the point itself.
+func movingAvg(e *State, varSet Results, n Results) (Results, error) {
+	points, err := pointsArg("moving_avg", n)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, varSet, "moving_avg", func(s Series) Series {
+		return movingWindowByPoints(e.RefID, s, points, avgOf)
+	})
+}
+
+// movingSum returns, for each point of each series, the sum of the last n points, including the point itself.
+func movingSum(e *State, varSet Results, n Results) (Results, error) {
+	points, err := pointsArg("moving_sum", n)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, varSet, "moving_sum", func(s Series) Series {
+		return movingWindowByPoints(e.RefID, s, points, sumOf)
+	})
+}
+
+// avgOverTime returns, for each point of each series, the average of the points within the preceding window.
+func avgOverTime(e *State, varSet Results, window string) (Results, error) {
+	d, err := durationArg("avg_over_time", window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, varSet, "avg_over_time", func(s Series) Series {
+		return movingWindowByDuration(e.RefID, s, d, avgOf)
+	})
+}
+
+// sumOverTime returns, for each point of each series, the sum of the points within the preceding window.
+func sumOverTime(e *State, varSet Results, window string) (Results, error) {
+	d, err := durationArg("sum_over_time", window)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, varSet, "sum_over_time", func(s Series) Series {
+		return movingWindowByDuration(e.RefID, s, d, sumOf)
+	})
+}
+
+// delta returns the difference between each point and the point before it. The first point of each series is dropped.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "delta", func(s Series) Series {
+		return consecutiveDiff(e.RefID, s, func(diff float64, _ time.Duration) *float64 {
+			return &diff
+		})
+	})
+}
+
+// derivative returns the per-second rate of change between each point and the point before it.
+// The first point of each series is dropped.
+func derivative(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "derivative", func(s Series) Series {
+		return consecutiveDiff(e.RefID, s, func(diff float64, elapsed time.Duration) *float64 {
+			if elapsed <= 0 {
+				return nil
+			}
+			f := diff / elapsed.Seconds()
+			return &f
+		})
+	})
+}
+
+// timeShift moves every point of each series forward in time by the given duration,
+// so time_shift($A, 1w) lines up last week's values with the current ones.
+func timeShift(e *State, varSet Results, shift string) (Results, error) {
+	d, err := gtime.ParseDuration(shift)
+	if err != nil {
+		return Results{}, fmt.Errorf("time_shift: invalid duration %q: %w", shift, err)
+	}
+	return perSeries(e, varSet, "time_shift", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// sumBy adds up the series or numbers that share the same values for the given comma separated labels.
+func sumBy(e *State, varSet Results, labels string) (Results, error) {
+	return aggregateBy(e, varSet, labels, sumOf)
+}
+
+// avgBy averages the series or numbers that share the same values for the given comma separated labels.
+func avgBy(e *State, varSet Results, labels string) (Results, error) {
+	return aggregateBy(e, varSet, labels, avgOf)
+}
+
+// minBy returns the minimum of the series or numbers that share the same values for the given comma separated labels.
+func minBy(e *State, varSet Results, labels string) (Results, error) {
+	return aggregateBy(e, varSet, labels, minOf)
+}
+
+// maxBy returns the maximum of the series or numbers that share the same values for the given comma separated labels.
+func maxBy(e *State, varSet Results, labels string) (Results, error) {
+	return aggregateBy(e, varSet, labels, maxOf)
+}
+
+// topk returns the k series or numbers with the highest values. Series are ranked by their average.
+func topk(e *State, varSet Results, k Results) (Results, error) {
+	return rankValues(varSet, k, "topk", true)
+}
+
+// bottomk returns the k series or numbers with the lowest values. Series are ranked by their average.
+func bottomk(e *State, varSet Results, k Results) (Results, error) {
+	return rankValues(varSet, k, "bottomk", false)
+}
+
+// perSeries applies seriesF to each series in varSet. NoData values are kept as they are.
+func perSeries(e *State, varSet Results, name string, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series, got type %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func movingWindowByPoints(refID string, s Series, points int, aggF func([]float64) *float64) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		start := i - points + 1
+		if start < 0 {
+			start = 0
+		}
+		newSeries.SetPoint(i, s.GetTime(i), aggF(validValues(s, start, i+1)))
+	}
+	return newSeries
+}
+
+func movingWindowByDuration(refID string, s Series, window time.Duration, aggF func([]float64) *float64) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	start := 0
+	for i := 0; i < s.Len(); i++ {
+		t := s.GetTime(i)
+		for start < i && !s.GetTime(start).After(t.Add(-window)) {
+			start++
+		}
+		newSeries.SetPoint(i, t, aggF(validValues(s, start, i+1)))
+	}
+	return newSeries
+}
+
+func consecutiveDiff(refID string, s Series, diffF func(diff float64, elapsed time.Duration) *float64) Series {
+	size := s.Len() - 1
+	if size < 0 {
+		size = 0
+	}
+	newSeries := NewSeries(refID, s.GetLabels(), size)
+	for i := 1; i < s.Len(); i++ {
+		prevT, prev := s.GetPoint(i - 1)
+		t, cur := s.GetPoint(i)
+		var f *float64
+		if prev != nil && cur != nil {
+			f = diffF(*cur-*prev, t.Sub(prevT))
+		}
+		newSeries.SetPoint(i-1, t, f)
+	}
+	return newSeries
+}
+
+// validValues returns the values of the points between start and end that are neither null nor NaN.
+func validValues(s Series, start, end int) []float64 {
+	values := make([]float64, 0, end-start)
+	for i := start; i < end; i++ {
+		f := s.GetValue(i)
+		if f == nil || math.IsNaN(*f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
+func aggregateBy(e *State, varSet Results, rawLabels string, aggF func([]float64) *float64) (Results, error) {
+	var byLabels []string
+	for _, l := range strings.Split(rawLabels, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			byLabels = append(byLabels, l)
+		}
+	}
+
+	type group struct {
+		labels data.Labels
+		series []Series
+		values []float64
+	}
+	groups := map[string]*group{}
+	var isSeries, isNumber bool
+	for _, res := range varSet.Values {
+		switch res.(type) {
+		case Series:
+			isSeries = true
+		case Number:
+			isNumber = true
+		case NoData:
+			continue
+		default:
+			return Results{}, fmt.Errorf("can only aggregate series or numbers, got type %v", res.Type())
+		}
+		if isSeries && isNumber {
+			return Results{}, fmt.Errorf("can not aggregate series and numbers together")
+		}
+
+		lbls := data.Labels{}
+		for _, l := range byLabels {
+			if v, ok := res.GetLabels()[l]; ok {
+				lbls[l] = v
+			}
+		}
+		key := lbls.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: lbls}
+			groups[key] = g
+		}
+		switch v := res.(type) {
+		case Series:
+			g.series = append(g.series, v)
+		case Number:
+			if f := v.GetFloat64Value(); f != nil && !math.IsNaN(*f) {
+				g.values = append(g.values, *f)
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		return Results{Values: Values{NewNoData()}}, nil
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	newRes := Results{}
+	for _, k := range keys {
+		g := groups[k]
+		if isNumber {
+			n := NewNumber(e.RefID, g.labels)
+			n.SetValue(aggF(g.values))
+			newRes.Values = append(newRes.Values, n)
+			continue
+		}
+		newRes.Values = append(newRes.Values, aggregateSeries(e.RefID, g.labels, g.series, aggF))
+	}
+	return newRes, nil
+}
+
+// aggregateSeries combines the series into one series by aggregating the values of all points with the same timestamp.
+func aggregateSeries(refID string, labels data.Labels, series []Series, aggF func([]float64) *float64) Series {
+	byTime := map[time.Time][]float64{}
+	var times []time.Time
+	for _, s := range series {
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			vals, seen := byTime[t]
+			if !seen {
+				times = append(times, t)
+			}
+			if f != nil && !math.IsNaN(*f) {
+				vals = append(vals, *f)
+			}
+			byTime[t] = vals
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	newSeries := NewSeries(refID, labels, len(times))
+	for i, t := range times {
+		newSeries.SetPoint(i, t, aggF(byTime[t]))
+	}
+	return newSeries
+}
+
+func rankValues(varSet Results, kRes Results, name string, desc bool) (Results, error) {
+	k, err := pointsArg(name, kRes)
+	if err != nil {
+		return Results{}, err
+	}
+
+	type ranked struct {
+		value Value
+		rank  float64
+	}
+	values := make([]ranked, 0, len(varSet.Values))
+	for _, res := range varSet.Values {
+		var f *float64
+		switch v := res.(type) {
+		case Series:
+			f = avgOf(validValues(v, 0, v.Len()))
+		case Number:
+			f = v.GetFloat64Value()
+		case NoData:
+			continue
+		default:
+			return Results{}, fmt.Errorf("%s: expected series or numbers, got type %v", name, res.Type())
+		}
+		rank := math.NaN()
+		if f != nil {
+			rank = *f
+		}
+		values = append(values, ranked{value: res, rank: rank})
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		a, b := values[i].rank, values[j].rank
+		// NaNs are always ranked last.
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+
+	newRes := Results{}
+	for i := 0; i < len(values) && i < k; i++ {
+		newRes.Values = append(newRes.Values, values[i].value)
+	}
+	return newRes, nil
+}
+
+// pointsArg returns the positive integer held by a scalar argument.
+func pointsArg(name string, res Results) (int, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("%s: expected a single scalar argument", name)
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil || math.IsNaN(*f) || *f < 1 || *f != math.Trunc(*f) {
+		return 0, fmt.Errorf("%s: expected a positive integer argument", name)
+	}
+	return int(*f), nil
+}
+
+func durationArg(name string, raw string) (time.Duration, error) {
+	d, err := gtime.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q: %w", name, raw, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s: duration must be positive, got %q", name, raw)
+	}
+	return d, nil
+}
+
+func sumOf(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return &sum
+}
+
+func avgOf(values []float64) *float64 {
+	sum := sumOf(values)
+	if sum == nil {
+		return nil
+	}
+	f := *sum / float64(len(values))
+	return &f
+}
+
+func minOf(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return &min
+}
+
+func maxOf(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return &max
+}
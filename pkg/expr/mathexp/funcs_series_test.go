@@ -0,0 +1,253 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFuncs(t *testing.T) {
+	series := makeSeries("", data.Labels{"host": "a"},
+		tp{time.Unix(0, 0), float64Pointer(1)},
+		tp{time.Unix(60, 0), float64Pointer(3)},
+		tp{time.Unix(120, 0), float64Pointer(5)},
+		tp{time.Unix(180, 0), float64Pointer(11)},
+	)
+	vars := Vars{"A": Results{[]Value{series}}}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "moving_avg over the last two points",
+			expr:      "moving_avg($A, 2)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(2)},
+				tp{time.Unix(120, 0), float64Pointer(4)},
+				tp{time.Unix(180, 0), float64Pointer(8)},
+			)}},
+		},
+		{
+			name:      "moving_sum over the last three points",
+			expr:      "moving_sum($A, 3)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(4)},
+				tp{time.Unix(120, 0), float64Pointer(9)},
+				tp{time.Unix(180, 0), float64Pointer(19)},
+			)}},
+		},
+		{
+			name:      "moving_avg with a fractional number of points should error",
+			expr:      "moving_avg($A, 1.5)",
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "avg_over_time with a duration literal",
+			expr:      "avg_over_time($A, 2m)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(2)},
+				tp{time.Unix(120, 0), float64Pointer(4)},
+				tp{time.Unix(180, 0), float64Pointer(8)},
+			)}},
+		},
+		{
+			name:      "sum_over_time with a duration string",
+			expr:      `sum_over_time($A, "3m")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(4)},
+				tp{time.Unix(120, 0), float64Pointer(9)},
+				tp{time.Unix(180, 0), float64Pointer(19)},
+			)}},
+		},
+		{
+			name:      "avg_over_time with an invalid duration should error",
+			expr:      `avg_over_time($A, "soon")`,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "delta",
+			expr:      "delta($A)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(60, 0), float64Pointer(2)},
+				tp{time.Unix(120, 0), float64Pointer(2)},
+				tp{time.Unix(180, 0), float64Pointer(6)},
+			)}},
+		},
+		{
+			name:      "derivative",
+			expr:      "derivative($A)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(60, 0), float64Pointer(2.0 / 60)},
+				tp{time.Unix(120, 0), float64Pointer(2.0 / 60)},
+				tp{time.Unix(180, 0), float64Pointer(6.0 / 60)},
+			)}},
+		},
+		{
+			name:      "time_shift",
+			expr:      "time_shift($A, 1d)",
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(86400, 0), float64Pointer(1)},
+				tp{time.Unix(86460, 0), float64Pointer(3)},
+				tp{time.Unix(86520, 0), float64Pointer(5)},
+				tp{time.Unix(86580, 0), float64Pointer(11)},
+			)}},
+		},
+		{
+			name:     "window function on a scalar should error",
+			expr:     "delta(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "window function with missing argument should error",
+			expr:     "moving_avg($A)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", vars)
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregationFuncs(t *testing.T) {
+	seriesVars := Vars{
+		"A": Results{[]Value{
+			makeSeries("", data.Labels{"host": "a", "dc": "east"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(2)},
+			),
+			makeSeries("", data.Labels{"host": "b", "dc": "east"},
+				tp{time.Unix(0, 0), float64Pointer(3)},
+				tp{time.Unix(60, 0), float64Pointer(4)},
+			),
+			makeSeries("", data.Labels{"host": "c", "dc": "west"},
+				tp{time.Unix(0, 0), float64Pointer(10)},
+				tp{time.Unix(60, 0), float64Pointer(20)},
+			),
+		}},
+	}
+	numberVars := Vars{
+		"A": Results{[]Value{
+			makeNumber("", data.Labels{"host": "a", "dc": "east"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"host": "b", "dc": "east"}, float64Pointer(3)),
+			makeNumber("", data.Labels{"host": "c", "dc": "west"}, float64Pointer(10)),
+		}},
+	}
+
+	var tests = []struct {
+		name    string
+		expr    string
+		vars    Vars
+		results Results
+	}{
+		{
+			name: "sum_by on series",
+			expr: `sum_by($A, "dc")`,
+			vars: seriesVars,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"dc": "east"},
+					tp{time.Unix(0, 0), float64Pointer(4)},
+					tp{time.Unix(60, 0), float64Pointer(6)},
+				),
+				makeSeries("", data.Labels{"dc": "west"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(60, 0), float64Pointer(20)},
+				),
+			}},
+		},
+		{
+			name: "avg_by on numbers",
+			expr: `avg_by($A, "dc")`,
+			vars: numberVars,
+			results: Results{[]Value{
+				makeNumber("", data.Labels{"dc": "east"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"dc": "west"}, float64Pointer(10)),
+			}},
+		},
+		{
+			name: "max_by without labels aggregates everything",
+			expr: `max_by($A, "")`,
+			vars: numberVars,
+			results: Results{[]Value{
+				makeNumber("", data.Labels{}, float64Pointer(10)),
+			}},
+		},
+		{
+			name: "min_by with several labels",
+			expr: `min_by($A, "dc, host")`,
+			vars: numberVars,
+			results: Results{[]Value{
+				makeNumber("", data.Labels{"dc": "east", "host": "a"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"dc": "east", "host": "b"}, float64Pointer(3)),
+				makeNumber("", data.Labels{"dc": "west", "host": "c"}, float64Pointer(10)),
+			}},
+		},
+		{
+			name: "topk on series ranks by average",
+			expr: "topk($A, 2)",
+			vars: seriesVars,
+			results: Results{[]Value{
+				seriesVars["A"].Values[2],
+				seriesVars["A"].Values[1],
+			}},
+		},
+		{
+			name: "bottomk on numbers",
+			expr: "bottomk($A, 1)",
+			vars: numberVars,
+			results: Results{[]Value{
+				numberVars["A"].Values[0],
+			}},
+		},
+		{
+			name:    "sum_by on no data",
+			expr:    `sum_by($A, "dc")`,
+			vars:    Vars{"A": Results{[]Value{NewNoData()}}},
+			results: Results{[]Value{NewNoData()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars)
+			require.NoError(t, err)
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
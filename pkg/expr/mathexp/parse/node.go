@@ -403,6 +403,8 @@ const (
 	TypeVariantSet
 	// TypeNoData is a no data response without a known data type.
 	TypeNoData
+	// TypeTableData is a data frame that has not been converted to numbers or series.
+	TypeTableData
 )
 
 // String returns a string representation of the ReturnType.
@@ -420,6 +422,8 @@ func (f ReturnType) String() string {
 		return "variant"
 	case TypeNoData:
 		return "noData"
+	case TypeTableData:
+		return "tableData"
 	default:
 		return "unknown"
 	}
@@ -3,6 +3,7 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +82,137 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
-func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer that calculates the p-th percentile (0 <= p <= 100) of the values,
+// interpolating linearly between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := strictValues(fv)
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+		f := PercentileOf(values, p)
+		return &f
+	}
+}
+
+// Stddev calculates the population standard deviation of the values.
+func Stddev(fv *Float64Field) *float64 {
+	values, ok := strictValues(fv)
+	if !ok || len(values) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := StddevOf(values)
+	return &f
+}
+
+// Range calculates the difference between the maximum and the minimum value.
+func Range(fv *Float64Field) *float64 {
+	max := Max(fv)
+	min := Min(fv)
+	f := *max - *min
+	return &f
+}
+
+// Increase calculates the increase of a counter over the values, taking counter resets into account.
+func Increase(fv *Float64Field) *float64 {
+	values, ok := strictValues(fv)
+	if !ok || len(values) < 2 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := CounterIncrease(values)
+	return &f
+}
+
+// Rate calculates the per-second average rate of increase of a counter over the series, taking counter resets into account.
+func Rate(s Series) *float64 {
+	fVec := s.Frame.Fields[seriesTypeValIdx]
+	ff := Float64Field(*fVec)
+	inc := Increase(&ff)
+	if math.IsNaN(*inc) {
+		return inc
+	}
+	f := math.NaN()
+	if elapsed := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds(); elapsed > 0 {
+		f = *inc / elapsed
+	}
+	return &f
+}
+
+// strictValues returns the values of the field. It returns false if any of the values is null or NaN.
+func strictValues(fv *Float64Field) ([]float64, bool) {
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		f := fv.GetValue(i)
+		if f == nil || math.IsNaN(*f) {
+			return nil, false
+		}
+		values = append(values, *f)
+	}
+	return values, true
+}
+
+// PercentileOf calculates the p-th percentile (0 <= p <= 100) of a non-empty slice of values,
+// interpolating linearly between the closest ranks. The slice is not modified.
+func PercentileOf(values []float64, p float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// StddevOf calculates the population standard deviation of a non-empty slice of values.
+func StddevOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var sqDiff float64
+	for _, v := range values {
+		sqDiff += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sqDiff / float64(len(values)))
+}
+
+// CounterIncrease calculates the increase of a counter over the values. A value that is lower than the previous one
+// is treated as a counter reset, in which case the counter is assumed to have restarted from zero.
+func CounterIncrease(values []float64) float64 {
+	var inc float64
+	for i := 1; i < len(values); i++ {
+		if values[i] < values[i-1] {
+			inc += values[i]
+			continue
+		}
+		inc += values[i] - values[i-1]
+	}
+	return inc
+}
+
+// GetReduceFunc returns the reducer with the given name. Parameterised reducers, such as percentile, read their parameters from params.
+// Reducers that depend on the timestamps of a series, such as rate, are only available via GetSeriesReduceFunc.
+func GetReduceFunc(rFunc string, params ...float64) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,20 +226,59 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "percentile":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("reduction %v requires exactly one parameter, got %d", rFunc, len(params))
+		}
+		if params[0] < 0 || params[0] > 100 || math.IsNaN(params[0]) {
+			return nil, fmt.Errorf("reduction %v requires a parameter between 0 and 100, got %v", rFunc, params[0])
+		}
+		return Percentile(params[0]), nil
+	case "stddev":
+		return Stddev, nil
+	case "range":
+		return Range, nil
+	case "increase":
+		return Increase, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
+// SeriesReducerFunc reduces a series to a single value. Unlike ReducerFunc, it has access to the timestamps of the series.
+type SeriesReducerFunc = func(s Series) *float64
+
+// GetSeriesReduceFunc returns the reducer with the given name. It supports all reducers of GetReduceFunc
+// as well as those that depend on the timestamps of the series.
+func GetSeriesReduceFunc(rFunc string, params ...float64) (SeriesReducerFunc, error) {
+	if strings.ToLower(rFunc) == "rate" {
+		return Rate, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc, params...)
+	if err != nil {
+		return nil, err
+	}
+	return func(s Series) *float64 {
+		fVec := s.Frame.Fields[seriesTypeValIdx]
+		floatField := Float64Field(*fVec)
+		return reduceFunc(&floatField)
+	}, nil
+}
+
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "percentile", "stddev", "range", "increase", "rate"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
-func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error) {
+// Parameterised reducers, such as percentile, read their parameters from params.
+func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper, params ...float64) (Number, error) {
 	var l data.Labels
 	if s.GetLabels() != nil {
 		l = s.GetLabels().Copy()
@@ -119,13 +289,11 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc, params...)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
@@ -514,3 +514,124 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesReduceExtended(t *testing.T) {
+	counter := makeSeries("temp", nil,
+		tp{time.Unix(0, 0), float64Pointer(10)},
+		tp{time.Unix(10, 0), float64Pointer(20)},
+		tp{time.Unix(20, 0), float64Pointer(5)}, // counter reset
+		tp{time.Unix(30, 0), float64Pointer(15)},
+		tp{time.Unix(40, 0), float64Pointer(25)},
+	)
+
+	var tests = []struct {
+		name     string
+		red      string
+		params   []float64
+		series   Series
+		errIs    require.ErrorAssertionFunc
+		expected *float64
+	}{
+		{
+			name:     "first",
+			red:      "first",
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(10),
+		},
+		{
+			name:     "first of empty series",
+			red:      "first",
+			series:   makeSeries("temp", nil),
+			errIs:    require.NoError,
+			expected: NaN,
+		},
+		{
+			name:     "median",
+			red:      "median",
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(15),
+		},
+		{
+			name:     "median with a nil value",
+			red:      "median",
+			series:   seriesWithNil["A"].Values[0].(Series),
+			errIs:    require.NoError,
+			expected: NaN,
+		},
+		{
+			name:     "percentile interpolates between ranks",
+			red:      "percentile",
+			params:   []float64{90},
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(23),
+		},
+		{
+			name:   "percentile without parameter will error",
+			red:    "percentile",
+			series: counter,
+			errIs:  require.Error,
+		},
+		{
+			name:     "stddev",
+			red:      "stddev",
+			series:   makeSeries("temp", nil, tp{time.Unix(0, 0), float64Pointer(2)}, tp{time.Unix(1, 0), float64Pointer(4)}),
+			errIs:    require.NoError,
+			expected: float64Pointer(1),
+		},
+		{
+			name:     "range",
+			red:      "range",
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(20),
+		},
+		{
+			name:     "increase handles counter resets",
+			red:      "increase",
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(35),
+		},
+		{
+			name:     "increase of a single point",
+			red:      "increase",
+			series:   makeSeries("temp", nil, tp{time.Unix(0, 0), float64Pointer(2)}),
+			errIs:    require.NoError,
+			expected: NaN,
+		},
+		{
+			name:     "rate handles counter resets",
+			red:      "rate",
+			series:   counter,
+			errIs:    require.NoError,
+			expected: float64Pointer(35.0 / 40),
+		},
+		{
+			name:     "rate of empty series",
+			red:      "rate",
+			series:   makeSeries("temp", nil),
+			errIs:    require.NoError,
+			expected: NaN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, err := tt.series.Reduce("", tt.red, nil, tt.params...)
+			tt.errIs(t, err)
+			if err != nil {
+				return
+			}
+			actual := num.GetFloat64Value()
+			require.NotNil(t, actual)
+			if math.IsNaN(*tt.expected) {
+				require.True(t, math.IsNaN(*actual))
+				return
+			}
+			require.InDelta(t, *tt.expected, *actual, 1e-9)
+		})
+	}
+}
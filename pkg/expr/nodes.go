@@ -120,6 +120,10 @@ func buildCMDNode(dp *simple.DirectedGraph, rn *rawNode) (*CMDNode, error) {
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn)
+	case TypeSQL:
+		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeAnomaly:
+		node.Command, err = UnmarshalAnomalyCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -147,6 +151,13 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is set when the results are used by a SQL expression,
+	// in which case the frames are returned as they are.
+	isInputToSQLExpr bool
+	// isInputToOtherExpr is set when the results are also used by expressions other
+	// than SQL, which need the frames to be read as numbers or series.
+	isInputToOtherExpr bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -258,40 +269,82 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, QueryError{RefID: dn.refID, Err: response.Error}
 	}
 
+	if dn.isInputToSQLExpr {
+		responseType = "table data"
+		if len(response.Frames) == 0 {
+			return mathexp.Results{Values: mathexp.Values{mathexp.NoData{}.New()}}, nil
+		}
+		for _, frame := range response.Frames {
+			vals = append(vals, mathexp.TableData{Frame: frame})
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	res, responseType, err := dn.readFrames(logger, response.Frames)
+	return res, err
+}
+
+// readTableData reads the frames returned to SQL expressions as numbers or series, for the
+// other consumers of the results. If only SQL expressions use the results, frames which can't
+// be read are returned as they are.
+func (dn *DSNode) readTableData(ctx context.Context, tables mathexp.Results) (mathexp.Results, error) {
+	frames := make(data.Frames, 0, len(tables.Values))
+	for _, val := range tables.Values {
+		if t, ok := val.(mathexp.TableData); ok {
+			frames = append(frames, t.Frame)
+		}
+	}
+	if len(frames) == 0 {
+		return tables, nil
+	}
+
+	logger := logger.FromContext(ctx).New("datasourceType", dn.datasource.Type, "queryRefId", dn.refID, "datasourceUid", dn.datasource.UID)
+	res, _, err := dn.readFrames(logger, frames)
+	if err != nil {
+		if dn.isInputToOtherExpr {
+			return mathexp.Results{}, fmt.Errorf("failed to read the results of %s, which is used by a sql expression and other expressions: %w", dn.refID, err)
+		}
+		return tables, nil
+	}
+	return res, nil
+}
+
+// readFrames reads the frames of a data source response as numbers or series.
+func (dn *DSNode) readFrames(logger log.Logger, frames data.Frames) (mathexp.Results, string, error) {
+	var err error
+	vals := make([]mathexp.Value, 0)
 	dataSource := dn.datasource.Type
-	if isAllFrameVectors(dataSource, response.Frames) { // Prometheus Specific Handling
-		vals, err = framesToNumbers(response.Frames)
+	if isAllFrameVectors(dataSource, frames) { // Prometheus Specific Handling
+		vals, err = framesToNumbers(frames)
 		if err != nil {
-			return mathexp.Results{}, fmt.Errorf("failed to read frames as numbers: %w", err)
+			return mathexp.Results{}, "", fmt.Errorf("failed to read frames as numbers: %w", err)
 		}
-		responseType = "vector"
-		return mathexp.Results{Values: vals}, nil
+		return mathexp.Results{Values: vals}, "vector", nil
 	}
 
-	if len(response.Frames) == 1 {
-		frame := response.Frames[0]
+	if len(frames) == 1 {
+		frame := frames[0]
 		// Handle Untyped NoData
 		if len(frame.Fields) == 0 {
-			return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+			return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, "no data", nil
 		}
 
 		// Handle Numeric Table
 		if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeNot && isNumberTable(frame) {
 			numberSet, err := extractNumberSet(frame)
 			if err != nil {
-				return mathexp.Results{}, err
+				return mathexp.Results{}, "", err
 			}
 			for _, n := range numberSet {
 				vals = append(vals, n)
 			}
-			responseType = "number set"
 			return mathexp.Results{
 				Values: vals,
-			}, nil
+			}, "number set", nil
 		}
 	}
 
-	for _, frame := range response.Frames {
+	for _, frame := range frames {
 		// Check for TimeSeriesTypeNot in InfluxDB queries. A data frame of this type will cause
 		// the WideToMany() function to error out, which results in unhealthy alerts.
 		// This check should be removed once inconsistencies in data source responses are solved.
@@ -301,17 +354,16 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		}
 		series, err := WideToMany(frame)
 		if err != nil {
-			return mathexp.Results{}, err
+			return mathexp.Results{}, "", err
 		}
 		for _, s := range series {
 			vals = append(vals, s)
 		}
 	}
 
-	responseType = "series set"
 	return mathexp.Results{
 		Values: vals, // TODO vals can be empty. Should we replace with no-data?
-	}, nil
+	}, "series set", nil
 }
 
 func isAllFrameVectors(datasourceType string, frames data.Frames) bool {
@@ -404,7 +456,11 @@ func extractNumberSet(frame *data.Frame) ([]mathexp.Number, error) {
 				labels = make(data.Labels)
 			}
 			key := stringFieldNames[i] // TODO check for duplicate string column names
-			val, _ := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			val, ok := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			if !ok {
+				// null values of nullable string fields are left out of the labels
+				continue
+			}
 			labels[key] = val.(string) // TODO check assertion / return error
 		}
 
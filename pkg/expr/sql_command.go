@@ -0,0 +1,748 @@
+package expr
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// SQLCommand is a command that runs a SQL SELECT statement over the results of other queries.
+// The results of every query referenced by the statement are loaded into an in-memory SQLite
+// table named after the query's refID, so no external database is involved.
+type SQLCommand struct {
+	RawSQL      string
+	varsToQuery []string
+	refID       string
+}
+
+// NewSQLCommand creates a new SQLCommand. It will return an error if the statement
+// is not a single SELECT statement.
+func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
+	tokens, err := tokenizeSQL(rawSQL)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSQL(tokens); err != nil {
+		return nil, err
+	}
+	return &SQLCommand{
+		RawSQL:      rawSQL,
+		varsToQuery: tablesInSQL(tokens),
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	rawExpr, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("command is missing an expression")
+	}
+	expressionRaw, ok := rawExpr.(string)
+	if !ok {
+		return nil, fmt.Errorf("sql expression is expected to be a string, got %T", rawExpr)
+	}
+
+	cmd, err := NewSQLCommand(rn.RefID, expressionRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sql command: %w", err)
+	}
+	return cmd, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gr *SQLCommand) NeedsVars() []string {
+	return gr.varsToQuery
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gr *SQLCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars) (mathexp.Results, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to open in-memory database: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.Warn("Failed to close in-memory database of sql expression", "refId", gr.refID, "error", err)
+		}
+	}()
+	// Every connection to ":memory:" gets its own database, so all statements must share one connection.
+	db.SetMaxOpenConns(1)
+
+	for _, refID := range gr.varsToQuery {
+		if err := loadSQLTable(ctx, db, refID, vars[refID]); err != nil {
+			return mathexp.Results{}, fmt.Errorf("failed to load the results of %s: %w", refID, err)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA query_only = 1"); err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to make in-memory database read only: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, gr.RawSQL)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute sql expression %s: %w", gr.refID, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	frame, err := sqlRowsToFrame(gr.refID, rows)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to read the results of sql expression %s: %w", gr.refID, err)
+	}
+	return sqlFrameToResults(frame)
+}
+
+// sqlFrameToResults converts the result of a SQL statement to numbers when it has a single numeric column,
+// to series when it is a time series in either the wide or the long format, and to table data otherwise.
+func sqlFrameToResults(frame *data.Frame) (mathexp.Results, error) {
+	if frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+	}
+
+	vals := make([]mathexp.Value, 0)
+	if isNumberTable(frame) {
+		numberSet, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		for _, n := range numberSet {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	switch frame.TimeSeriesSchema().Type {
+	case data.TimeSeriesTypeLong:
+		wideFrame, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return mathexp.Results{}, fmt.Errorf("failed to convert the result to a wide time series: %w", err)
+		}
+		frame = wideFrame
+		fallthrough
+	case data.TimeSeriesTypeWide:
+		series, err := WideToMany(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		for _, s := range series {
+			vals = append(vals, s)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// sqlTable is the content of a single SQLite table, built from the results of a query.
+type sqlTable struct {
+	columns []string
+	types   map[string]string
+	rows    []map[string]interface{}
+}
+
+func (t *sqlTable) addColumn(name, sqlType string) {
+	if _, ok := t.types[name]; ok {
+		return
+	}
+	t.columns = append(t.columns, name)
+	t.types[name] = sqlType
+}
+
+// loadSQLTable creates a table named after refID and fills it with the results.
+// Series are loaded as time, value and one column per label, numbers as value and one column per label,
+// and table data with one column per field.
+func loadSQLTable(ctx context.Context, db *sql.DB, refID string, results mathexp.Results) error {
+	table := &sqlTable{types: map[string]string{}}
+	for _, val := range results.Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			for _, k := range sortedLabelKeys(v.GetLabels()) {
+				table.addColumn(k, "TEXT")
+			}
+			table.addColumn("time", "DATETIME")
+			table.addColumn("value", "REAL")
+			for i := 0; i < v.Len(); i++ {
+				t, f := v.GetPoint(i)
+				row := labelsRow(v.GetLabels())
+				row["time"] = t
+				row["value"] = sqlFloat(f)
+				table.rows = append(table.rows, row)
+			}
+		case mathexp.Number:
+			for _, k := range sortedLabelKeys(v.GetLabels()) {
+				table.addColumn(k, "TEXT")
+			}
+			table.addColumn("value", "REAL")
+			row := labelsRow(v.GetLabels())
+			row["value"] = sqlFloat(v.GetFloat64Value())
+			table.rows = append(table.rows, row)
+		case mathexp.Scalar:
+			table.addColumn("value", "REAL")
+			table.rows = append(table.rows, map[string]interface{}{"value": sqlFloat(v.GetFloat64Value())})
+		case mathexp.TableData:
+			addFrameToSQLTable(table, v.Frame)
+		case mathexp.NoData:
+			continue
+		default:
+			return fmt.Errorf("can not use type %v in a sql expression", val.Type())
+		}
+	}
+	if len(table.columns) == 0 {
+		// SQLite tables need at least one column, queries on a table without results return no rows.
+		table.addColumn("value", "REAL")
+	}
+
+	columns := make([]string, 0, len(table.columns))
+	placeholders := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		columns = append(columns, quoteSQLIdentifier(c)+" "+table.types[c])
+		placeholders = append(placeholders, "?")
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quoteSQLIdentifier(refID), strings.Join(columns, ", "))); err != nil {
+		return err
+	}
+	if len(table.rows) == 0 {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	quotedColumns := make([]string, 0, len(table.columns))
+	for _, c := range table.columns {
+		quotedColumns = append(quotedColumns, quoteSQLIdentifier(c))
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteSQLIdentifier(refID), strings.Join(quotedColumns, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	args := make([]interface{}, len(table.columns))
+	for _, row := range table.rows {
+		for i, c := range table.columns {
+			args[i] = row[c]
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			_ = stmt.Close()
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// addFrameToSQLTable adds a column for every field of the frame and a row for every row of the frame.
+// Field labels are added as columns that hold the same value for every row.
+func addFrameToSQLTable(table *sqlTable, frame *data.Frame) {
+	names := make([]string, len(frame.Fields))
+	used := map[string]bool{}
+	for i, field := range frame.Fields {
+		base := field.Name
+		if base == "" {
+			base = "value"
+		}
+		name := base
+		for n := 1; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+		table.addColumn(name, sqlColumnType(field.Type()))
+	}
+	for _, field := range frame.Fields {
+		for _, k := range sortedLabelKeys(field.Labels) {
+			if !used[k] {
+				table.addColumn(k, "TEXT")
+			}
+		}
+	}
+
+	for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
+		row := map[string]interface{}{}
+		for i, field := range frame.Fields {
+			for k, v := range field.Labels {
+				if !used[k] {
+					row[k] = v
+				}
+			}
+			row[names[i]] = sqlFieldValue(field, rowIdx)
+		}
+		table.rows = append(table.rows, row)
+	}
+}
+
+func sortedLabelKeys(labels data.Labels) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func labelsRow(labels data.Labels) map[string]interface{} {
+	row := make(map[string]interface{}, len(labels)+2)
+	for k, v := range labels {
+		row[k] = v
+	}
+	return row
+}
+
+func sqlColumnType(fieldType data.FieldType) string {
+	switch fieldType.NonNullableType() {
+	case data.FieldTypeTime:
+		return "DATETIME"
+	case data.FieldTypeBool:
+		return "BOOLEAN"
+	case data.FieldTypeFloat32, data.FieldTypeFloat64:
+		return "REAL"
+	case data.FieldTypeInt8, data.FieldTypeInt16, data.FieldTypeInt32, data.FieldTypeInt64,
+		data.FieldTypeUint8, data.FieldTypeUint16, data.FieldTypeUint32, data.FieldTypeUint64:
+		return "INTEGER"
+	default:
+		return "TEXT"
+	}
+}
+
+func sqlFieldValue(field *data.Field, idx int) interface{} {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return nil
+	}
+	switch t := v.(type) {
+	case json.RawMessage:
+		return string(t)
+	case uint64:
+		if t > math.MaxInt64 {
+			return float64(t)
+		}
+		return int64(t)
+	case float64:
+		return sqlFloat(&t)
+	}
+	return v
+}
+
+// sqlFloat returns nil for null and NaN values, which SQLite stores as NULL.
+func sqlFloat(f *float64) interface{} {
+	if f == nil || math.IsNaN(*f) {
+		return nil
+	}
+	return *f
+}
+
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlRowsToFrame reads all rows into a frame with one field per column. Numbers are read as nullable
+// float64 values, text as nullable strings, and booleans and times as nullable booleans and times.
+func sqlRowsToFrame(name string, rows *sql.Rows) (*data.Frame, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([][]interface{}, len(columnTypes))
+	row := make([]interface{}, len(columnTypes))
+	dest := make([]interface{}, len(columnTypes))
+	for i := range row {
+		dest[i] = &row[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			columns[i] = append(columns[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame(name)
+	for i, ct := range columnTypes {
+		frame.Fields = append(frame.Fields, sqlColumnToField(ct.Name(), ct.DatabaseTypeName(), columns[i]))
+	}
+	return frame, nil
+}
+
+func sqlColumnToField(name, declaredType string, values []interface{}) *data.Field {
+	switch sqlColumnKind(declaredType, values) {
+	case data.FieldTypeTime:
+		times := make([]*time.Time, len(values))
+		hasNull := false
+		for i, v := range values {
+			times[i] = sqlTime(v)
+			hasNull = hasNull || times[i] == nil
+		}
+		if hasNull {
+			return data.NewField(name, nil, times)
+		}
+		// Time series need a non nullable time field.
+		nonNullTimes := make([]time.Time, len(times))
+		for i, t := range times {
+			nonNullTimes[i] = *t
+		}
+		return data.NewField(name, nil, nonNullTimes)
+	case data.FieldTypeString:
+		strs := make([]*string, len(values))
+		for i, v := range values {
+			if v != nil {
+				s := fmt.Sprint(v)
+				strs[i] = &s
+			}
+		}
+		return data.NewField(name, nil, strs)
+	case data.FieldTypeBool:
+		bools := make([]*bool, len(values))
+		for i, v := range values {
+			switch t := v.(type) {
+			case bool:
+				bools[i] = &t
+			case int64:
+				b := t != 0
+				bools[i] = &b
+			}
+		}
+		return data.NewField(name, nil, bools)
+	default:
+		floats := make([]*float64, len(values))
+		for i, v := range values {
+			switch t := v.(type) {
+			case float64:
+				floats[i] = &t
+			case int64:
+				f := float64(t)
+				floats[i] = &f
+			case bool:
+				f := 0.0
+				if t {
+					f = 1
+				}
+				floats[i] = &f
+			case string:
+				if f, err := strconv.ParseFloat(t, 64); err == nil {
+					floats[i] = &f
+				}
+			}
+		}
+		return data.NewField(name, nil, floats)
+	}
+}
+
+// sqlColumnKind returns the field type of a result column, based on the values of the column, or on the
+// declared type of the column if all values are null. Columns computed from time columns, such as max(time),
+// have no declared type and are returned as text, so text that only holds SQLite timestamps is read as time.
+func sqlColumnKind(declaredType string, values []interface{}) data.FieldType {
+	var kind data.FieldType
+	found := false
+	allTimestamps := true
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		var k data.FieldType
+		switch t := v.(type) {
+		case time.Time:
+			k = data.FieldTypeTime
+		case bool:
+			k = data.FieldTypeBool
+		case int64, float64:
+			k = data.FieldTypeFloat64
+		case string:
+			k = data.FieldTypeString
+			allTimestamps = allTimestamps && parseSQLiteTimestamp(t) != nil
+		default:
+			k = data.FieldTypeString
+		}
+		if !found {
+			kind, found = k, true
+			continue
+		}
+		if kind != k {
+			// Mixed columns are read as numbers when they only hold numbers and booleans, and as text otherwise.
+			if (kind == data.FieldTypeFloat64 || kind == data.FieldTypeBool) && (k == data.FieldTypeFloat64 || k == data.FieldTypeBool) {
+				kind = data.FieldTypeFloat64
+			} else {
+				kind = data.FieldTypeString
+			}
+		}
+	}
+
+	if found {
+		if kind == data.FieldTypeString && declaredType == "" && allTimestamps {
+			return data.FieldTypeTime
+		}
+		return kind
+	}
+
+	switch strings.ToUpper(declaredType) {
+	case "DATETIME", "TIMESTAMP", "DATE":
+		return data.FieldTypeTime
+	case "BOOLEAN":
+		return data.FieldTypeBool
+	case "TEXT":
+		return data.FieldTypeString
+	default:
+		return data.FieldTypeFloat64
+	}
+}
+
+func sqlTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return &t
+	case string:
+		return parseSQLiteTimestamp(t)
+	}
+	return nil
+}
+
+func parseSQLiteTimestamp(s string) *time.Time {
+	s = strings.TrimSuffix(s, "Z")
+	for _, format := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+type sqlTokenKind int
+
+const (
+	sqlTokenIdentifier sqlTokenKind = iota
+	sqlTokenQuotedIdentifier
+	sqlTokenLiteral
+	sqlTokenPunctuation
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+}
+
+// tokenizeSQL splits a SQL statement into tokens. It knows just enough of the SQLite syntax
+// to find the tables the statement reads from and to tell statements apart.
+func tokenizeSQL(s string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, errors.New("unterminated comment in sql expression")
+			}
+			i = j + 2
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == closing {
+					// quotes are escaped by doubling them
+					if closing != ']' && j+1 < len(runes) && runes[j+1] == closing {
+						text.WriteRune(closing)
+						j++
+						continue
+					}
+					break
+				}
+				text.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated %c in sql expression", r)
+			}
+			kind := sqlTokenQuotedIdentifier
+			if r == '\'' {
+				kind = sqlTokenLiteral
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: text.String()})
+			i = j + 1
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '$' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenIdentifier, text: string(runes[i:j])})
+			i = j
+		case unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '.' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenLiteral, text: string(runes[i:j])})
+			i = j
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlTokenPunctuation, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// validateSQL makes sure that the tokens form a single SELECT statement.
+func validateSQL(tokens []sqlToken) error {
+	if len(tokens) == 0 {
+		return errors.New("sql expression is empty")
+	}
+	if !isSQLKeyword(tokens[0], "SELECT") && !isSQLKeyword(tokens[0], "WITH") {
+		return errors.New("only SELECT statements are supported in sql expressions")
+	}
+	for i, tok := range tokens {
+		if isSQLPunctuation(tok, ";") && i != len(tokens)-1 {
+			return errors.New("only a single statement is supported in sql expressions")
+		}
+	}
+	return nil
+}
+
+// sqlReservedWords are the keywords that can follow a table name, and can therefore not be a table alias.
+var sqlReservedWords = map[string]bool{
+	"AS": true, "CROSS": true, "EXCEPT": true, "FROM": true, "FULL": true, "GROUP": true, "HAVING": true,
+	"INNER": true, "INTERSECT": true, "JOIN": true, "LEFT": true, "LIMIT": true, "NATURAL": true, "OFFSET": true,
+	"ON": true, "ORDER": true, "OUTER": true, "RIGHT": true, "SELECT": true, "UNION": true, "USING": true,
+	"WHERE": true, "WINDOW": true,
+}
+
+// tablesInSQL returns the names of the tables that the statement reads from, in order of appearance.
+// Names of common table expressions are left out.
+func tablesInSQL(tokens []sqlToken) []string {
+	ctes := map[string]bool{}
+	for i, tok := range tokens {
+		if !isSQLName(tok) {
+			continue
+		}
+		next := i + 1
+		if next < len(tokens) && isSQLPunctuation(tokens[next], "(") {
+			// WITH name(column, ...) AS (...)
+			next = skipSQLParens(tokens, next)
+		}
+		if next+1 < len(tokens) && isSQLKeyword(tokens[next], "AS") && isSQLPunctuation(tokens[next+1], "(") {
+			ctes[strings.ToLower(tok.text)] = true
+		}
+	}
+
+	var tables []string
+	seen := map[string]bool{}
+	for i, tok := range tokens {
+		isFrom := isSQLKeyword(tok, "FROM")
+		if !isFrom && !isSQLKeyword(tok, "JOIN") {
+			continue
+		}
+		for j := i + 1; j < len(tokens); j++ {
+			switch {
+			case isSQLPunctuation(tokens[j], "("):
+				// sub query, its tables are found by the outer loop
+				j = skipSQLParens(tokens, j)
+			case isSQLName(tokens[j]):
+				if j+1 < len(tokens) && isSQLPunctuation(tokens[j+1], ".") {
+					// schema qualified name, the table name follows the dot
+					j++
+					continue
+				}
+				if j+1 < len(tokens) && isSQLPunctuation(tokens[j+1], "(") {
+					// table valued function
+					j = skipSQLParens(tokens, j+1)
+					break
+				}
+				name := tokens[j].text
+				if !ctes[strings.ToLower(name)] && !seen[name] {
+					seen[name] = true
+					tables = append(tables, name)
+				}
+				j++
+			default:
+				j = len(tokens)
+			}
+			if j < len(tokens) && isSQLKeyword(tokens[j], "AS") {
+				j++
+			}
+			if j < len(tokens) && isSQLName(tokens[j]) {
+				j++
+			}
+			if !isFrom || j >= len(tokens) || !isSQLPunctuation(tokens[j], ",") {
+				break
+			}
+		}
+	}
+	return tables
+}
+
+// skipSQLParens returns the index of the token after the parenthesis that closes the one at idx.
+func skipSQLParens(tokens []sqlToken, idx int) int {
+	depth := 0
+	for i := idx; i < len(tokens); i++ {
+		switch {
+		case isSQLPunctuation(tokens[i], "("):
+			depth++
+		case isSQLPunctuation(tokens[i], ")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(tokens)
+}
+
+func isSQLKeyword(tok sqlToken, keyword string) bool {
+	return tok.kind == sqlTokenIdentifier && strings.EqualFold(tok.text, keyword)
+}
+
+func isSQLPunctuation(tok sqlToken, p string) bool {
+	return tok.kind == sqlTokenPunctuation && tok.text == p
+}
+
+// isSQLName returns true if the token can be the name of a table or an alias.
+func isSQLName(tok sqlToken) bool {
+	switch tok.kind {
+	case sqlTokenQuotedIdentifier:
+		return true
+	case sqlTokenIdentifier:
+		return !sqlReservedWords[strings.ToUpper(tok.text)]
+	default:
+		return false
+	}
+}
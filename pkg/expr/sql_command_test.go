@@ -0,0 +1,291 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewSQLCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		needsVars []string
+		errorIs   require.ErrorAssertionFunc
+	}{
+		{
+			name:      "single table",
+			sql:       "SELECT * FROM A",
+			needsVars: []string{"A"},
+			errorIs:   require.NoError,
+		},
+		{
+			name:      "join with aliases",
+			sql:       "SELECT a.host, a.value / b.value FROM A AS a INNER JOIN B b ON a.host = b.host WHERE a.value > 0",
+			needsVars: []string{"A", "B"},
+			errorIs:   require.NoError,
+		},
+		{
+			name:      "comma separated tables and quoted names",
+			sql:       `SELECT * FROM "A" x, [B], ` + "`C`" + ` WHERE x.value = 1;`,
+			needsVars: []string{"A", "B", "C"},
+			errorIs:   require.NoError,
+		},
+		{
+			name:      "sub query and common table expression",
+			sql:       "WITH totals(host, total) AS (SELECT host, sum(value) FROM A GROUP BY host) SELECT * FROM totals JOIN (SELECT host FROM B) b USING (host)",
+			needsVars: []string{"A", "B"},
+			errorIs:   require.NoError,
+		},
+		{
+			name:      "table names in strings and comments are ignored",
+			sql:       "SELECT 'FROM C' AS s -- FROM D\n FROM A /* JOIN E */",
+			needsVars: []string{"A"},
+			errorIs:   require.NoError,
+		},
+		{
+			name:    "statements other than select are rejected",
+			sql:     "DELETE FROM A",
+			errorIs: require.Error,
+		},
+		{
+			name:    "multiple statements are rejected",
+			sql:     "SELECT * FROM A; ATTACH DATABASE 'file.db' AS f",
+			errorIs: require.Error,
+		},
+		{
+			name:    "unterminated strings are rejected",
+			sql:     "SELECT 'abc FROM A",
+			errorIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewSQLCommand("X", tt.sql)
+			tt.errorIs(t, err)
+			if err == nil {
+				require.Equal(t, tt.needsVars, cmd.NeedsVars())
+			}
+		})
+	}
+}
+
+func TestSQLCommandExecute(t *testing.T) {
+	cpu := mathexp.Results{Values: mathexp.Values{
+		makeSQLTestNumber("cpu", data.Labels{"host": "a", "dc": "east"}, 10),
+		makeSQLTestNumber("cpu", data.Labels{"host": "b", "dc": "east"}, 30),
+		makeSQLTestNumber("cpu", data.Labels{"host": "c", "dc": "west"}, 50),
+	}}
+	limits := mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b", "c"}),
+		data.NewField("limit", nil, []float64{20, 20, 100}),
+	)}}}
+	series := mathexp.Results{Values: mathexp.Values{
+		makeSQLTestSeries(data.Labels{"host": "a"}, 1, 2),
+		makeSQLTestSeries(data.Labels{"host": "b"}, 3, 4),
+	}}
+	vars := mathexp.Vars{"A": cpu, "B": limits, "C": series, "D": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}}
+
+	t.Run("join and filter returns numbers with labels", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT A.host, A.value / B.\"limit\" AS usage FROM A JOIN B ON A.host = B.host WHERE A.value > B.\"limit\" ORDER BY A.host")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+
+		require.Len(t, res.Values, 1)
+		require.Equal(t, data.Labels{"host": "b"}, res.Values[0].GetLabels())
+		require.Equal(t, 1.5, *res.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("group by returns numbers with labels", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT dc, sum(value) AS total FROM A GROUP BY dc ORDER BY dc")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"dc": "east"}, res.Values[0].GetLabels())
+		require.Equal(t, 40.0, *res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, data.Labels{"dc": "west"}, res.Values[1].GetLabels())
+		require.Equal(t, 50.0, *res.Values[1].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("time series in the long format are returned as series", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT time, host, value * 2 AS doubled FROM C ORDER BY time, host")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+
+		require.Len(t, res.Values, 2)
+		for i, host := range []string{"a", "b"} {
+			s, ok := res.Values[i].(mathexp.Series)
+			require.True(t, ok)
+			require.Equal(t, data.Labels{"host": host}, s.GetLabels())
+			require.Equal(t, 2, s.Len())
+			require.True(t, s.GetTime(0).Equal(time.Unix(60, 0)))
+		}
+		require.Equal(t, 8.0, *res.Values[1].(mathexp.Series).GetValue(1))
+	})
+
+	t.Run("other results are returned as table data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT host, \"limit\", \"limit\" * 2 AS double FROM B ORDER BY host")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+
+		require.Len(t, res.Values, 1)
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+		require.Equal(t, 3, table.Frame.Rows())
+		require.Len(t, table.Frame.Fields, 3)
+	})
+
+	t.Run("no rows returns no data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT value FROM D")
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+
+		require.Len(t, res.Values, 1)
+		require.Equal(t, parse.TypeNoData, res.Values[0].Type())
+	})
+
+	t.Run("invalid statement returns an error", func(t *testing.T) {
+		cmd, err := NewSQLCommand("X", "SELECT nope FROM A")
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), vars)
+		require.Error(t, err)
+	})
+}
+
+func TestSQLCommandInPipeline(t *testing.T) {
+	// A table that can not be converted to numbers or series.
+	dsDF := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b"}),
+		data.NewField("used", nil, []float64{3, 9}),
+		data.NewField("total", nil, []float64{10, 10}))
+
+	s := Service{
+		cfg:               setting.NewCfg(),
+		dataService:       &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		dataSourceService: &datafakes.FakeDataSourceService{},
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "B",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT host, used / total AS ratio FROM A" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+
+	frames := res.Responses["B"].Frames
+	require.Len(t, frames, 2)
+	require.Equal(t, data.Labels{"host": "a"}, frames[0].Fields[0].Labels)
+	require.Equal(t, 0.3, *frames[0].Fields[0].At(0).(*float64))
+	require.Equal(t, data.Labels{"host": "b"}, frames[1].Fields[0].Labels)
+	require.Equal(t, 0.9, *frames[1].Fields[0].At(0).(*float64))
+}
+
+func TestSQLCommandInPipelineWithOtherConsumers(t *testing.T) {
+	dsDF := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{time.Unix(60, 0), time.Unix(120, 0)}),
+		data.NewField("cpu", data.Labels{"host": "a"}, []*float64{fp(2), fp(4)}))
+
+	s := Service{
+		cfg:               setting.NewCfg(),
+		dataService:       &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		dataSourceService: &datafakes.FakeDataSourceService{},
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "B",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "sql", "expression": "SELECT max(cpu) AS cpu FROM A" }`),
+		},
+		{
+			RefID:      "C",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "reducer": "mean", "expression": "A" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+
+	// the sql expression reads the frame as it is, with the original field names
+	frames := res.Responses["B"].Frames
+	require.Len(t, frames, 1)
+	require.Equal(t, 4.0, *frames[0].Fields[0].At(0).(*float64))
+
+	// reduce gets the frame read as a series
+	frames = res.Responses["C"].Frames
+	require.Len(t, frames, 1)
+	require.Equal(t, data.Labels{"host": "a"}, frames[0].Fields[0].Labels)
+	require.Equal(t, 3.0, *frames[0].Fields[0].At(0).(*float64))
+
+	// so does the caller, e.g. when the query is the condition of an alert rule
+	frames = res.Responses["A"].Frames
+	require.Len(t, frames, 1)
+	require.Equal(t, data.TimeSeriesTypeWide, frames[0].TimeSeriesSchema().Type)
+}
+
+func makeSQLTestNumber(name string, labels data.Labels, f float64) mathexp.Number {
+	n := mathexp.NewNumber(name, labels)
+	n.SetValue(&f)
+	return n
+}
+
+func makeSQLTestSeries(labels data.Labels, values ...float64) mathexp.Series {
+	s := mathexp.NewSeries("", labels, len(values))
+	for i := range values {
+		s.SetPoint(i, time.Unix(int64(60*(i+1)), 0), &values[i])
+	}
+	return s
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
@@ -15,6 +17,31 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc string
 	Conditions    []float64
+	// Evaluators are the ordered conditions of the command. The first evaluator always
+	// has the same function and params as ThresholdFunc and Conditions.
+	Evaluators []ThresholdEvaluator
+	// LoadedDimensions are the labels of the instances that are currently firing.
+	// Evaluators with a recovery condition use it for these instances.
+	LoadedDimensions []data.Labels
+}
+
+// ThresholdEvaluator is a single condition of a threshold expression.
+type ThresholdEvaluator struct {
+	// Operator combines the condition with the result of the conditions before it.
+	// It is either "and" or "or", and is ignored for the first condition.
+	Operator      string
+	ThresholdFunc string
+	Params        []float64
+	// UnloadThresholdFunc and UnloadParams are the recovery condition of the evaluator, if any.
+	// An instance that is firing keeps matching the evaluator until the recovery condition is met,
+	// which prevents alerts from flapping when the value hovers around the threshold.
+	UnloadThresholdFunc string
+	UnloadParams        []float64
+}
+
+// HasRecovery returns true if the evaluator has a recovery condition.
+func (e ThresholdEvaluator) HasRecovery() bool {
+	return e.UnloadThresholdFunc != ""
 }
 
 const (
@@ -24,21 +51,42 @@ const (
 	ThresholdIsOutsideRange = "outside_range"
 )
 
+const (
+	ThresholdOperatorAnd = "and"
+	ThresholdOperatorOr  = "or"
+)
+
 var (
 	supportedThresholdFuncs = []string{ThresholdIsAbove, ThresholdIsBelow, ThresholdIsWithinRange, ThresholdIsOutsideRange}
 )
 
 func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions []float64) (*ThresholdCommand, error) {
+	return NewThresholdCommandWithEvaluators(refID, referenceVar, []ThresholdEvaluator{{
+		ThresholdFunc: thresholdFunc,
+		Params:        conditions,
+	}})
+}
+
+// NewThresholdCommandWithEvaluators creates a ThresholdCommand that combines the evaluators
+// from left to right, so "A or B and C" is evaluated as "(A or B) and C".
+func NewThresholdCommandWithEvaluators(refID, referenceVar string, evaluators []ThresholdEvaluator) (*ThresholdCommand, error) {
+	if len(evaluators) == 0 {
+		return nil, fmt.Errorf("threshold expression requires at least one condition")
+	}
 	return &ThresholdCommand{
 		RefID:         refID,
 		ReferenceVar:  referenceVar,
-		ThresholdFunc: thresholdFunc,
-		Conditions:    conditions,
+		ThresholdFunc: evaluators[0].ThresholdFunc,
+		Conditions:    evaluators[0].Params,
+		Evaluators:    evaluators,
 	}, nil
 }
 
 type ThresholdConditionJSON struct {
 	Evaluator ConditionEvalJSON `json:"evaluator"`
+	// UnloadEvaluator is the recovery condition for instances that are firing.
+	UnloadEvaluator *ConditionEvalJSON     `json:"unloadEvaluator,omitempty"`
+	Operator        *ThresholdOperatorJSON `json:"operator,omitempty"`
 }
 
 type ConditionEvalJSON struct {
@@ -46,6 +94,10 @@ type ConditionEvalJSON struct {
 	Type   string    `json:"type"` // e.g. "gt"
 }
 
+type ThresholdOperatorJSON struct {
+	Type string `json:"type"` // "and" or "or"
+}
+
 // UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
 func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 	rawQuery := rn.Query
@@ -68,19 +120,68 @@ func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 		return nil, fmt.Errorf("failed to unmarshal remarshaled threshold expression body: %w", err)
 	}
 
-	for _, condition := range conditions {
-		if !IsSupportedThresholdFunc(condition.Evaluator.Type) {
-			return nil, fmt.Errorf("expected threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), condition.Evaluator.Type)
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("threshold expression requires at least one condition")
+	}
+
+	evaluators := make([]ThresholdEvaluator, 0, len(conditions))
+	for i, condition := range conditions {
+		if err := validateThresholdEvaluator(condition.Evaluator); err != nil {
+			return nil, fmt.Errorf("invalid condition %d: %w", i+1, err)
 		}
+		evaluator := ThresholdEvaluator{
+			Operator:      ThresholdOperatorAnd,
+			ThresholdFunc: condition.Evaluator.Type,
+			Params:        condition.Evaluator.Params,
+		}
+		if condition.Operator != nil && condition.Operator.Type != "" {
+			switch condition.Operator.Type {
+			case ThresholdOperatorAnd, ThresholdOperatorOr:
+				evaluator.Operator = condition.Operator.Type
+			default:
+				return nil, fmt.Errorf("invalid condition %d: expected operator to be one of %s, %s, got %s", i+1, ThresholdOperatorAnd, ThresholdOperatorOr, condition.Operator.Type)
+			}
+		}
+		if condition.UnloadEvaluator != nil {
+			if err := validateThresholdEvaluator(*condition.UnloadEvaluator); err != nil {
+				return nil, fmt.Errorf("invalid recovery condition %d: %w", i+1, err)
+			}
+			evaluator.UnloadThresholdFunc = condition.UnloadEvaluator.Type
+			evaluator.UnloadParams = condition.UnloadEvaluator.Params
+		}
+		evaluators = append(evaluators, evaluator)
 	}
 
-	// we only support one condition for now, we might want to turn this in to "OR" expressions later
-	if len(conditions) != 1 {
-		return nil, fmt.Errorf("threshold expression requires exactly one condition")
+	cmd, err := NewThresholdCommandWithEvaluators(rn.RefID, referenceVar, evaluators)
+	if err != nil {
+		return nil, err
 	}
-	firstCondition := conditions[0]
 
-	return NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	if rawLoaded, ok := rawQuery[thresholdLoadedDimensionsKey]; ok {
+		jsonFromM, err := json.Marshal(rawLoaded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remarshal loaded dimensions of threshold expression: %w", err)
+		}
+		if err = json.Unmarshal(jsonFromM, &cmd.LoadedDimensions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal loaded dimensions of threshold expression: %w", err)
+		}
+	}
+
+	return cmd, nil
+}
+
+func validateThresholdEvaluator(evaluator ConditionEvalJSON) error {
+	if !IsSupportedThresholdFunc(evaluator.Type) {
+		return fmt.Errorf("expected threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), evaluator.Type)
+	}
+	required := 1
+	if evaluator.Type == ThresholdIsWithinRange || evaluator.Type == ThresholdIsOutsideRange {
+		required = 2
+	}
+	if len(evaluator.Params) < required {
+		return fmt.Errorf("threshold function %s requires %d params, got %d", evaluator.Type, required, len(evaluator.Params))
+	}
+	return nil
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -90,12 +191,69 @@ func (tc *ThresholdCommand) NeedsVars() []string {
 }
 
 func (tc *ThresholdCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars) (mathexp.Results, error) {
-	mathExpression, err := createMathExpression(tc.ReferenceVar, tc.ThresholdFunc, tc.Conditions)
+	mathExpression, err := createThresholdExpression(tc.ReferenceVar, tc.Evaluators, false)
 	if err != nil {
 		return mathexp.Results{}, err
 	}
 
-	mathCommand, err := NewMathCommand(tc.ReferenceVar, mathExpression)
+	if !tc.hasRecovery() || len(tc.LoadedDimensions) == 0 {
+		return executeThresholdExpression(ctx, now, tc.ReferenceVar, mathExpression, vars)
+	}
+
+	// Instances that are firing are evaluated with the recovery conditions, all others with the firing conditions.
+	recoveryExpression, err := createThresholdExpression(tc.ReferenceVar, tc.Evaluators, true)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	loaded := make(map[string]struct{}, len(tc.LoadedDimensions))
+	for _, lbls := range tc.LoadedDimensions {
+		loaded[lbls.String()] = struct{}{}
+	}
+	var firingVals, otherVals mathexp.Values
+	for _, val := range vars[tc.ReferenceVar].Values {
+		if _, ok := loaded[val.GetLabels().String()]; ok {
+			firingVals = append(firingVals, val)
+		} else {
+			otherVals = append(otherVals, val)
+		}
+	}
+
+	results := mathexp.Results{}
+	for _, part := range []struct {
+		expression string
+		values     mathexp.Values
+	}{
+		{expression: mathExpression, values: otherVals},
+		{expression: recoveryExpression, values: firingVals},
+	} {
+		if len(part.values) == 0 {
+			continue
+		}
+		partVars := make(mathexp.Vars, len(vars))
+		for k, v := range vars {
+			partVars[k] = v
+		}
+		partVars[tc.ReferenceVar] = mathexp.Results{Values: part.values}
+		res, err := executeThresholdExpression(ctx, now, tc.ReferenceVar, part.expression, partVars)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		results.Values = append(results.Values, res.Values...)
+	}
+	return results, nil
+}
+
+func (tc *ThresholdCommand) hasRecovery() bool {
+	for _, e := range tc.Evaluators {
+		if e.HasRecovery() {
+			return true
+		}
+	}
+	return false
+}
+
+func executeThresholdExpression(ctx context.Context, now time.Time, referenceVar, mathExpression string, vars mathexp.Vars) (mathexp.Results, error) {
+	mathCommand, err := NewMathCommand(referenceVar, mathExpression)
 	if err != nil {
 		return mathexp.Results{}, err
 	}
@@ -103,6 +261,38 @@ func (tc *ThresholdCommand) Execute(ctx context.Context, now time.Time, vars mat
 	return mathCommand.Execute(ctx, now, vars)
 }
 
+// createThresholdExpression combines the math expressions of all evaluators from left to right.
+// If recovering is true, evaluators with a recovery condition match until the recovery condition is met.
+func createThresholdExpression(referenceVar string, evaluators []ThresholdEvaluator, recovering bool) (string, error) {
+	var combined string
+	for i, evaluator := range evaluators {
+		var expression string
+		var err error
+		if recovering && evaluator.HasRecovery() {
+			expression, err = createMathExpression(referenceVar, evaluator.UnloadThresholdFunc, evaluator.UnloadParams)
+			expression = "!(" + expression + ")"
+		} else {
+			expression, err = createMathExpression(referenceVar, evaluator.ThresholdFunc, evaluator.Params)
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(evaluators) == 1 {
+			return expression, nil
+		}
+		if i == 0 {
+			combined = "(" + expression + ")"
+			continue
+		}
+		operator := "&&"
+		if evaluator.Operator == ThresholdOperatorOr {
+			operator = "||"
+		}
+		combined = fmt.Sprintf("(%s %s (%s))", combined, operator, expression)
+	}
+	return combined, nil
+}
+
 // createMathExpression converts all the info we have about a "threshold" expression in to a Math expression
 func createMathExpression(referenceVar string, thresholdFunc string, args []float64) (string, error) {
 	switch thresholdFunc {
@@ -130,3 +320,34 @@ func IsSupportedThresholdFunc(name string) bool {
 
 	return isSupported
 }
+
+// thresholdLoadedDimensionsKey is the key of the query model that holds the labels of the instances that are firing.
+const thresholdLoadedDimensionsKey = "loadedDimensions"
+
+// IsHysteresisExpression returns true if the query model is a threshold expression
+// with at least one recovery condition.
+func IsHysteresisExpression(query map[string]interface{}) bool {
+	if query["type"] != "threshold" {
+		return false
+	}
+	conditions, ok := query["conditions"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if unload, ok := condition["unloadEvaluator"]; ok && unload != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLoadedDimensionsToHysteresisCommand sets the labels of the instances that are firing
+// to the query model of a threshold expression.
+func SetLoadedDimensionsToHysteresisCommand(query map[string]interface{}, loaded []data.Labels) {
+	query[thresholdLoadedDimensionsKey] = loaded
+}
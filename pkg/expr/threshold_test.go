@@ -1,10 +1,15 @@
 package expr
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
 func TestNewThresholdCommand(t *testing.T) {
@@ -44,7 +49,7 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				"conditions": []
 			}`,
 			shouldError:   true,
-			expectedError: "requires exactly one condition",
+			expectedError: "requires at least one condition",
 		},
 		{
 			description: "unmarshal with missing conditions should error",
@@ -54,7 +59,7 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				"conditions": []
 			}`,
 			shouldError:   true,
-			expectedError: "requires exactly one condition",
+			expectedError: "requires at least one condition",
 		},
 		{
 			description: "unmarshal with unsupported threshold function",
@@ -71,6 +76,104 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "expected threshold function to be one of",
 		},
+		{
+			description: "unmarshal multiple conditions with operators",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [20]
+					}
+				}, {
+					"evaluator": {
+						"type": "lt",
+						"params": [80]
+					},
+					"operator": {
+						"type": "or"
+					}
+				}]
+			}`,
+			shouldError: false,
+		},
+		{
+			description: "unmarshal with unsupported operator",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [20]
+					}
+				}, {
+					"evaluator": {
+						"type": "lt",
+						"params": [80]
+					},
+					"operator": {
+						"type": "xor"
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "expected operator to be one of",
+		},
+		{
+			description: "unmarshal with missing params",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "within_range",
+						"params": [20]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "requires 2 params",
+		},
+		{
+			description: "unmarshal with recovery condition and loaded dimensions",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "lt",
+						"params": [70]
+					}
+				}],
+				"loadedDimensions": [{"host": "a"}]
+			}`,
+			shouldError: false,
+		},
+		{
+			description: "unmarshal with unsupported recovery function",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "foo",
+						"params": [70]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "invalid recovery condition",
+		},
 		{
 			description: "unmarshal with bad expression",
 			query: `{
@@ -209,3 +312,87 @@ func TestIsSupportedThresholdFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdCommandExecute(t *testing.T) {
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			makeThresholdTestNumber(data.Labels{"host": "a"}, 75),
+			makeThresholdTestNumber(data.Labels{"host": "b"}, 85),
+			makeThresholdTestNumber(data.Labels{"host": "c"}, 65),
+		}},
+	}
+	resultsByHost := func(t *testing.T, res mathexp.Results) map[string]float64 {
+		t.Helper()
+		byHost := map[string]float64{}
+		for _, v := range res.Values {
+			n, ok := v.(mathexp.Number)
+			require.True(t, ok)
+			byHost[n.GetLabels()["host"]] = *n.GetFloat64Value()
+		}
+		return byHost
+	}
+
+	t.Run("combines conditions from left to right", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithEvaluators("B", "A", []ThresholdEvaluator{
+			{ThresholdFunc: ThresholdIsBelow, Params: []float64{70}},
+			{Operator: ThresholdOperatorOr, ThresholdFunc: ThresholdIsAbove, Params: []float64{80}},
+			{Operator: ThresholdOperatorAnd, ThresholdFunc: ThresholdIsAbove, Params: []float64{70}},
+		})
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"a": 0, "b": 1, "c": 0}, resultsByHost(t, res))
+	})
+
+	t.Run("recovery condition only applies to instances that are firing", func(t *testing.T) {
+		cmd, err := NewThresholdCommandWithEvaluators("B", "A", []ThresholdEvaluator{
+			{ThresholdFunc: ThresholdIsAbove, Params: []float64{80}, UnloadThresholdFunc: ThresholdIsBelow, UnloadParams: []float64{70}},
+		})
+		require.NoError(t, err)
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"a": 0, "b": 1, "c": 0}, resultsByHost(t, res))
+
+		cmd.LoadedDimensions = []data.Labels{{"host": "a"}, {"host": "c"}}
+		res, err = cmd.Execute(context.Background(), time.Now(), vars)
+		require.NoError(t, err)
+		// a is firing and has not dropped below the recovery threshold, c has.
+		require.Equal(t, map[string]float64{"a": 1, "b": 1, "c": 0}, resultsByHost(t, res))
+	})
+}
+
+func TestIsHysteresisExpression(t *testing.T) {
+	var query map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "threshold",
+		"conditions": [{"evaluator": {"type": "gt", "params": [80]}, "unloadEvaluator": {"type": "lt", "params": [70]}}]
+	}`), &query))
+	require.True(t, IsHysteresisExpression(query))
+
+	SetLoadedDimensionsToHysteresisCommand(query, []data.Labels{{"host": "a"}})
+	raw, err := json.Marshal(query)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &query))
+	cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: map[string]interface{}{
+		"expression":       "A",
+		"conditions":       query["conditions"],
+		"loadedDimensions": query["loadedDimensions"],
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []data.Labels{{"host": "a"}}, cmd.LoadedDimensions)
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "threshold",
+		"conditions": [{"evaluator": {"type": "gt", "params": [80]}}]
+	}`), &query))
+	require.False(t, IsHysteresisExpression(query))
+	require.False(t, IsHysteresisExpression(map[string]interface{}{"type": "math"}))
+}
+
+func makeThresholdTestNumber(labels data.Labels, f float64) mathexp.Number {
+	n := mathexp.NewNumber("", labels)
+	n.SetValue(&f)
+	return n
+}
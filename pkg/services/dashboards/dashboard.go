@@ -14,6 +14,10 @@ import (
 type DashboardService interface {
 	BuildSaveDashboardCommand(ctx context.Context, dto *SaveDashboardDTO, shouldValidateAlerts bool, validateProvisionedDashboard bool) (*SaveDashboardCommand, error)
 	DeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error
+	// DeleteDashboardFromTrash permanently deletes a dashboard or folder which is in the trash.
+	DeleteDashboardFromTrash(ctx context.Context, cmd *DeleteDashboardFromTrashCommand) error
+	// DeleteExpiredDashboards permanently deletes dashboards and folders which were in the trash for too long.
+	DeleteExpiredDashboards(ctx context.Context, cmd *DeleteExpiredDashboardsCommand) error
 	FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error)
 	GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error)
 	GetDashboardACLInfoList(ctx context.Context, query *GetDashboardACLInfoListQuery) ([]*DashboardACLInfoDTO, error)
@@ -24,8 +28,11 @@ type DashboardService interface {
 	HasEditPermissionInFolders(ctx context.Context, query *folder.HasEditPermissionInFoldersQuery) (bool, error)
 	ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error)
 	MakeUserAdmin(ctx context.Context, orgID int64, userID, dashboardID int64, setViewAndEditPermissions bool) error
+	RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error)
 	SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error)
 	SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) error
+	// SoftDeleteDashboard moves a dashboard or folder to the trash.
+	SoftDeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error
 	DeleteACLByUser(ctx context.Context, userID int64) error
 	CountDashboardsInFolder(ctx context.Context, query *CountDashboardsInFolderQuery) (int64, error)
@@ -55,6 +62,8 @@ type DashboardProvisioningService interface {
 //go:generate mockery --name Store --structname FakeDashboardStore --inpackage --filename store_mock.go
 type Store interface {
 	DeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error
+	DeleteDashboardFromTrash(ctx context.Context, cmd *DeleteDashboardFromTrashCommand) error
+	DeleteExpiredDashboards(ctx context.Context, cmd *DeleteExpiredDashboardsCommand) error
 	DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *DeleteOrphanedProvisionedDashboardsCommand) error
 	FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error)
 	GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error)
@@ -69,10 +78,15 @@ type Store interface {
 	GetProvisionedDataByDashboardUID(ctx context.Context, orgID int64, dashboardUID string) (*DashboardProvisioning, error)
 	HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *folder.HasAdminPermissionInDashboardsOrFoldersQuery) (bool, error)
 	HasEditPermissionInFolders(ctx context.Context, query *folder.HasEditPermissionInFoldersQuery) (bool, error)
+	// RestoreDeletedDashboard moves a dashboard out of the trash, returning it together with the dashboards
+	// restored with a folder.
+	RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) ([]*Dashboard, error)
 	// SaveAlerts saves dashboard alerts.
 	SaveAlerts(ctx context.Context, dashID int64, alerts []*alertmodels.Alert) error
 	SaveDashboard(ctx context.Context, cmd SaveDashboardCommand) (*Dashboard, error)
 	SaveProvisionedDashboard(ctx context.Context, cmd SaveDashboardCommand, provisioning *DashboardProvisioning) (*Dashboard, error)
+	// SoftDeleteDashboard moves a dashboard to the trash, keeping the data needed to restore it.
+	SoftDeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error
 	UnprovisionDashboard(ctx context.Context, id int64) error
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error
 	// ValidateDashboardBeforeSave validates a dashboard before save.
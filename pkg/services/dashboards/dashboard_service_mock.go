@@ -86,6 +86,34 @@ func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId
 	return r0
 }
 
+// DeleteDashboardFromTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) DeleteDashboardFromTrash(ctx context.Context, cmd *DeleteDashboardFromTrashCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardFromTrashCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) DeleteExpiredDashboards(ctx context.Context, cmd *DeleteExpiredDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -303,6 +331,29 @@ func (_m *FakeDashboardService) MakeUserAdmin(ctx context.Context, orgID int64,
 	return r0
 }
 
+// RestoreDeletedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
@@ -340,6 +391,20 @@ func (_m *FakeDashboardService) SearchDashboards(ctx context.Context, query *Fin
 	return r0
 }
 
+// SoftDeleteDashboard provides a mock function with given fields: ctx, dashboardId, orgId
+func (_m *FakeDashboardService) SoftDeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
+	ret := _m.Called(ctx, dashboardId, orgId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, dashboardId, orgId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateDashboardACL provides a mock function with given fields: ctx, uid, items
 func (_m *FakeDashboardService) UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error {
 	ret := _m.Called(ctx, uid, items)
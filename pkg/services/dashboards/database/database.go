@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	libmodel "github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
@@ -314,6 +315,10 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 			return false, dashboards.ErrDashboardNotFound
 		}
 
+		if !existingById.Deleted.IsZero() {
+			return false, dashboards.ErrDashboardDeleted
+		}
+
 		if dash.UID == "" {
 			dash.SetUID(existingById.UID)
 		}
@@ -328,11 +333,15 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 		if err != nil {
 			return false, fmt.Errorf("SQL query for existing dashboard by UID failed: %w", err)
 		}
+
+		if dashWithUidExists && !existingByUid.Deleted.IsZero() {
+			return false, dashboards.ErrDashboardDeleted
+		}
 	}
 
 	if dash.FolderID > 0 {
 		var existingFolder dashboards.Dashboard
-		folderExists, err := sess.Where("org_id=? AND id=? AND is_folder=?", dash.OrgID, dash.FolderID,
+		folderExists, err := sess.Where("org_id=? AND id=? AND is_folder=? AND deleted IS NULL", dash.OrgID, dash.FolderID,
 			dialect.BooleanStr(true)).Get(&existingFolder)
 		if err != nil {
 			return false, fmt.Errorf("SQL query for folder failed: %w", err)
@@ -388,12 +397,25 @@ func getExistingDashboardByIDOrUIDForUpdate(sess *db.Session, dash *dashboards.D
 func getExistingDashboardByTitleAndFolder(sess *db.Session, dash *dashboards.Dashboard, dialect migrator.Dialect, overwrite,
 	isParentFolderChanged bool) (bool, error) {
 	var existing dashboards.Dashboard
-	exists, err := sess.Where("org_id=? AND slug=? AND (is_folder=? OR folder_id=?)", dash.OrgID, dash.Slug,
+	exists, err := sess.Where("org_id=? AND slug=? AND (is_folder=? OR folder_id=?) AND deleted IS NULL", dash.OrgID, dash.Slug,
 		dialect.BooleanStr(true), dash.FolderID).Get(&existing)
 	if err != nil {
 		return isParentFolderChanged, fmt.Errorf("SQL query for existing dashboard by org ID or folder ID failed: %w", err)
 	}
 
+	if !exists {
+		// Dashboards in the trash keep their title, which is unique within a folder.
+		var deleted dashboards.Dashboard
+		deletedExists, err := sess.Where("org_id=? AND folder_id=? AND title=? AND deleted IS NOT NULL", dash.OrgID, dash.FolderID,
+			dash.Title).Get(&deleted)
+		if err != nil {
+			return isParentFolderChanged, fmt.Errorf("SQL query for deleted dashboard by title failed: %w", err)
+		}
+		if deletedExists && dash.ID != deleted.ID {
+			return isParentFolderChanged, dashboards.ErrDashboardWithSameNameInTrash
+		}
+	}
+
 	if exists && dash.ID != existing.ID {
 		if existing.IsFolder && !dash.IsFolder {
 			return isParentFolderChanged, dashboards.ErrDashboardWithSameNameAsFolder
@@ -673,7 +695,7 @@ func (d *dashboardStore) deleteAlertByIdInternal(alertId int64, reason string, s
 func (d *dashboardStore) GetDashboardsByPluginID(ctx context.Context, query *dashboards.GetDashboardsByPluginIDQuery) ([]*dashboards.Dashboard, error) {
 	var dashboards = make([]*dashboards.Dashboard, 0)
 	err := d.store.WithDbSession(ctx, func(dbSession *db.Session) error {
-		whereExpr := "org_id=? AND plugin_id=? AND is_folder=" + d.store.GetDialect().BooleanStr(false)
+		whereExpr := "org_id=? AND plugin_id=? AND deleted IS NULL AND is_folder=" + d.store.GetDialect().BooleanStr(false)
 
 		err := dbSession.Where(whereExpr, query.OrgID, query.PluginID).Find(&dashboards)
 		return err
@@ -809,6 +831,231 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 	return nil
 }
 
+// SoftDeleteDashboard moves a dashboard to the trash, dashboards of a folder are moved together with it.
+// Versions, permissions and provisioning data are kept, so that the dashboard can be restored.
+func (d *dashboardStore) SoftDeleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		dashboard := dashboards.Dashboard{ID: cmd.ID, OrgID: cmd.OrgID}
+		has, err := sess.Where("deleted IS NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		deleted := []*dashboards.Dashboard{&dashboard}
+		if dashboard.IsFolder {
+			var existingRuleID int64
+			exists, err := sess.Table("alert_rule").Where("namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)", dashboard.ID).Cols("id").Get(&existingRuleID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if !cmd.ForceDeleteFolderRules {
+					return fmt.Errorf("folder cannot be deleted: %w", dashboards.ErrFolderContainsAlertRules)
+				}
+
+				// Alert rules cannot be restored, delete all rules under this folder.
+				deleteNGAlertsByFolder := []string{
+					"DELETE FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+					"DELETE FROM alert_rule_version WHERE rule_namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+				}
+				for _, sql := range deleteNGAlertsByFolder {
+					if _, err := sess.Exec(sql, dashboard.ID); err != nil {
+						return err
+					}
+				}
+			}
+
+			// Library elements stay in the folder while it is in the trash, they are deleted together with it
+			// when the trash is emptied. Elements in use cannot be deleted, so neither can the folder.
+			connected, err := sess.SQL("SELECT COUNT(*) FROM library_element AS le INNER JOIN "+libmodel.LibraryElementConnectionTableName+" AS lec ON le.id = lec.element_id WHERE le.org_id = ? AND le.folder_id = ?",
+				dashboard.OrgID, dashboard.ID).Count()
+			if err != nil {
+				return err
+			}
+			if connected > 0 {
+				return libmodel.ErrFolderHasConnectedLibraryElements
+			}
+
+			var children []*dashboards.Dashboard
+			if err := sess.Where("org_id = ? AND folder_id = ? AND deleted IS NULL", dashboard.OrgID, dashboard.ID).Find(&children); err != nil {
+				return err
+			}
+			deleted = append(deleted, children...)
+		}
+
+		// Dashboards deleted together share the timestamp, it is truncated to seconds
+		// to survive the round trip through the database unchanged.
+		now := time.Now().Truncate(time.Second)
+		for _, dash := range deleted {
+			// Legacy alerts are extracted from the dashboard again when it is restored.
+			if err := d.deleteAlertDefinition(dash.ID, sess); err != nil {
+				return err
+			}
+
+			if _, err := sess.ID(dash.ID).Cols("deleted").Update(&dashboards.Dashboard{Deleted: now}); err != nil {
+				return err
+			}
+
+			if d.emitEntityEvent() {
+				if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeDelete)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreDeletedDashboard moves a dashboard out of the trash back to its folder. A restored folder brings back
+// the dashboards which were deleted together with it. The restored dashboard is returned first, followed by
+// the dashboards of the folder.
+func (d *dashboardStore) RestoreDeletedDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) ([]*dashboards.Dashboard, error) {
+	var restored []*dashboards.Dashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		dashboard := dashboards.Dashboard{OrgID: cmd.OrgID, UID: cmd.UID}
+		has, err := sess.Where("deleted IS NOT NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+
+		if dashboard.FolderID > 0 {
+			var folder dashboards.Dashboard
+			folderExists, err := sess.Where("org_id=? AND id=? AND is_folder=?", dashboard.OrgID, dashboard.FolderID,
+				d.store.GetDialect().BooleanStr(true)).Get(&folder)
+			if err != nil {
+				return fmt.Errorf("SQL query for folder failed: %w", err)
+			}
+			if !folderExists {
+				return dashboards.ErrDashboardFolderNotFound
+			}
+			if !folder.Deleted.IsZero() {
+				return dashboards.ErrDashboardFolderDeleted
+			}
+		}
+
+		// Another dashboard could have taken the name while this one was in the trash.
+		var existing dashboards.Dashboard
+		exists, err := sess.Where("org_id=? AND slug=? AND (is_folder=? OR folder_id=?) AND deleted IS NULL", dashboard.OrgID, dashboard.Slug,
+			d.store.GetDialect().BooleanStr(true), dashboard.FolderID).Get(&existing)
+		if err != nil {
+			return fmt.Errorf("SQL query for existing dashboard by org ID or folder ID failed: %w", err)
+		}
+		if exists {
+			switch {
+			case existing.IsFolder && !dashboard.IsFolder:
+				return dashboards.ErrDashboardWithSameNameAsFolder
+			case !existing.IsFolder && dashboard.IsFolder:
+				return dashboards.ErrDashboardFolderWithSameNameAsDashboard
+			case existing.IsFolder && dashboard.IsFolder:
+				return dashboards.ErrDashboardFolderNameExists
+			default:
+				return dashboards.ErrDashboardWithSameNameInFolderExists
+			}
+		}
+
+		restored = append(restored, &dashboard)
+		if dashboard.IsFolder {
+			var children []*dashboards.Dashboard
+			if err := sess.Where("org_id = ? AND folder_id = ? AND deleted IS NOT NULL", dashboard.OrgID, dashboard.ID).Find(&children); err != nil {
+				return err
+			}
+			// Dashboards deleted before the folder stay in the trash.
+			for _, child := range children {
+				if child.Deleted.Equal(dashboard.Deleted) {
+					restored = append(restored, child)
+				}
+			}
+		}
+
+		for _, dash := range restored {
+			if _, err := sess.Exec("UPDATE dashboard SET deleted = NULL WHERE id = ?", dash.ID); err != nil {
+				return err
+			}
+			dash.Deleted = time.Time{}
+			dash.SetID(dash.ID)
+			dash.SetUID(dash.UID)
+
+			if d.emitEntityEvent() {
+				if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeCreate)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// DeleteExpiredDashboards permanently deletes dashboards and folders moved to the trash before cmd.OlderThan.
+func (d *dashboardStore) DeleteExpiredDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredDashboardsCommand) error {
+	var expired []*dashboards.Dashboard
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("deleted IS NOT NULL AND deleted < ?", cmd.OlderThan).
+			Cols("id", "org_id", "is_folder").OrderBy("is_folder DESC").Find(&expired)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dash := range expired {
+		err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			return d.purgeDashboard(dash, sess)
+		})
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			// Already deleted together with its folder.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		cmd.DeletedRows++
+	}
+	return nil
+}
+
+// DeleteDashboardFromTrash permanently deletes a dashboard or folder in the trash, together with the
+// dashboards deleted with the folder.
+func (d *dashboardStore) DeleteDashboardFromTrash(ctx context.Context, cmd *dashboards.DeleteDashboardFromTrashCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		dashboard := dashboards.Dashboard{OrgID: cmd.OrgID, UID: cmd.UID}
+		has, err := sess.Where("deleted IS NOT NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+		return d.purgeDashboard(&dashboard, sess)
+	})
+}
+
+// purgeDashboard deletes a dashboard in the trash with all the data kept to restore it.
+func (d *dashboardStore) purgeDashboard(dash *dashboards.Dashboard, sess *db.Session) error {
+	// Public dashboards are kept while the dashboard is in the trash, so they are removed here.
+	if _, err := sess.Exec("DELETE FROM dashboard_public WHERE org_id = ? AND dashboard_uid IN (SELECT uid FROM dashboard WHERE org_id = ? AND (id = ? OR folder_id = ?))",
+		dash.OrgID, dash.OrgID, dash.ID, dash.ID); err != nil {
+		return err
+	}
+	if dash.IsFolder {
+		deletes := []string{
+			"DELETE FROM " + libmodel.LibraryElementConnectionTableName + " WHERE element_id IN (SELECT id FROM library_element WHERE org_id = ? AND folder_id = ?)",
+			"DELETE FROM library_element WHERE org_id = ? AND folder_id = ?",
+		}
+		for _, sql := range deletes {
+			if _, err := sess.Exec(sql, dash.OrgID, dash.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return d.deleteDashboard(&dashboards.DeleteDashboardCommand{ID: dash.ID, OrgID: dash.OrgID, ForceDeleteFolderRules: true}, sess, false)
+}
+
 func createEntityEvent(dashboard *dashboards.Dashboard, eventType store.EntityEventType) *store.EntityEvent {
 	var entityEvent *store.EntityEvent
 	if dashboard.IsFolder {
@@ -852,7 +1099,7 @@ func (d *dashboardStore) GetDashboard(ctx context.Context, query *dashboards.Get
 		}
 
 		dashboard := dashboards.Dashboard{Slug: query.Slug, OrgID: query.OrgID, ID: query.ID, UID: query.UID}
-		has, err := sess.Get(&dashboard)
+		has, err := sess.Where("deleted IS NULL").Get(&dashboard)
 
 		if err != nil {
 			return err
@@ -902,6 +1149,7 @@ func (d *dashboardStore) GetDashboards(ctx context.Context, query *dashboards.Ge
 		if query.OrgID > 0 {
 			session = sess.Where("org_id = ?", query.OrgID)
 		}
+		session = session.Where("deleted IS NULL")
 
 		err := session.Find(&dashboards)
 		return err
@@ -935,6 +1183,7 @@ func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.F
 	}
 
 	filters = append(filters, query.Filters...)
+	filters = append(filters, searchstore.DeletedFilter{Deleted: query.Deleted})
 
 	if query.OrgId != 0 {
 		filters = append(filters, searchstore.OrgFilter{OrgId: query.OrgId})
@@ -998,7 +1247,7 @@ func (d *dashboardStore) GetDashboardTags(ctx context.Context, query *dashboards
 						term
 					FROM dashboard
 					INNER JOIN dashboard_tag on dashboard_tag.dashboard_id = dashboard.id
-					WHERE dashboard.org_id=?
+					WHERE dashboard.org_id=? AND dashboard.deleted IS NULL
 					GROUP BY term
 					ORDER BY term`
 
@@ -1023,7 +1272,7 @@ func (d *dashboardStore) CountDashboardsInFolder(
 	var err error
 	err = d.store.WithDbSession(ctx, func(sess *db.Session) error {
 		session := sess.In("folder_id", req.FolderID).In("org_id", req.OrgID).
-			In("is_folder", d.store.GetDialect().BooleanStr(false))
+			In("is_folder", d.store.GetDialect().BooleanStr(false)).Where("deleted IS NULL")
 		count, err = session.Count(&dashboards.Dashboard{})
 		return err
 	})
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	libmodel "github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search/model"
@@ -23,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestIntegrationDashboardDataAccess(t *testing.T) {
@@ -595,6 +597,225 @@ func TestIntegrationDashboard_Filter(t *testing.T) {
 	assert.Equal(t, dashB.ID, results[0].ID)
 }
 
+func TestIntegrationDashboard_Trash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var sqlStore *sqlstore.SQLStore
+	var savedFolder, savedDash, savedDash2 *dashboards.Dashboard
+	var dashboardStore dashboards.Store
+
+	setup := func() {
+		var cfg *setting.Cfg
+		sqlStore, cfg = db.InitTestDBwithCfg(t)
+		quotaService := quotatest.New(false, nil)
+		var err error
+		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, cfg), quotaService)
+		require.NoError(t, err)
+		savedFolder = insertTestDashboard(t, dashboardStore, "trash folder", 1, 0, true)
+		savedDash = insertTestDashboard(t, dashboardStore, "trash dash", 1, savedFolder.ID, false, "prod", "webapp")
+		savedDash2 = insertTestDashboard(t, dashboardStore, "trash dash 2", 1, 0, false)
+	}
+
+	searchTrash := func(t *testing.T) []dashboards.DashboardSearchProjection {
+		t.Helper()
+		results, err := dashboardStore.FindDashboards(context.Background(), &dashboards.FindPersistedDashboardsQuery{
+			OrgId:   1,
+			Deleted: true,
+			SignedInUser: &user.SignedInUser{
+				OrgID:   1,
+				OrgRole: org.RoleEditor,
+				Permissions: map[int64]map[string][]string{1: {
+					dashboards.ActionDashboardsRead: []string{dashboards.ScopeDashboardsAll},
+					dashboards.ActionFoldersRead:    []string{dashboards.ScopeFoldersAll},
+				}},
+			},
+		})
+		require.NoError(t, err)
+		return results
+	}
+
+	t.Run("Should move a dashboard to the trash", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDash2.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: savedDash2.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		results := searchTrash(t)
+		require.Len(t, results, 1)
+		require.Equal(t, savedDash2.UID, results[0].UID)
+		require.False(t, results[0].Deleted.IsZero())
+	})
+
+	t.Run("Should not save a dashboard in the trash", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDash2.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		savedDash2.Data.Set("title", "changed")
+		cmd := dashboards.SaveDashboardCommand{OrgID: 1, Dashboard: savedDash2.Data, Overwrite: true}
+		_, err = dashboardStore.ValidateDashboardBeforeSave(context.Background(), cmd.GetDashboardModel(), true)
+		require.ErrorIs(t, err, dashboards.ErrDashboardDeleted)
+	})
+
+	t.Run("Should move a folder to the trash together with its dashboards and restore them", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		// dashboards deleted with their folder are restored with the folder
+		results := searchTrash(t)
+		require.Len(t, results, 1)
+		require.Equal(t, savedFolder.UID, results[0].UID)
+
+		restored, err := dashboardStore.RestoreDeletedDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: savedFolder.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, restored, 2)
+		require.Equal(t, savedFolder.UID, restored[0].UID)
+
+		dash, err := dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: savedDash.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, savedFolder.ID, dash.FolderID)
+		require.Empty(t, searchTrash(t))
+	})
+
+	t.Run("Should not restore a dashboard if its folder is in the trash", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDash.ID, OrgID: 1})
+		require.NoError(t, err)
+		err = dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardFolderDeleted)
+	})
+
+	t.Run("Should not create a dashboard with the same name as a dashboard in the trash", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDash2.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		cmd := dashboards.SaveDashboardCommand{
+			OrgID:     1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": savedDash2.Title}),
+			Overwrite: true,
+		}
+		_, err = dashboardStore.ValidateDashboardBeforeSave(context.Background(), cmd.GetDashboardModel(), true)
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameNameInTrash)
+	})
+
+	t.Run("Should keep library elements of a folder in the trash until it is deleted", func(t *testing.T) {
+		setup()
+		insertTestLibraryElement(t, sqlStore, savedFolder)
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), countLibraryElements(t, sqlStore, savedFolder.ID))
+
+		err = dashboardStore.DeleteDashboardFromTrash(context.Background(), &dashboards.DeleteDashboardFromTrashCommand{UID: savedFolder.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Zero(t, countLibraryElements(t, sqlStore, savedFolder.ID))
+	})
+
+	t.Run("Should not move a folder with library elements in use to the trash", func(t *testing.T) {
+		setup()
+		elementID := insertTestLibraryElement(t, sqlStore, savedFolder)
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("INSERT INTO library_element_connection (element_id, kind, connection_id, created, created_by) VALUES (?, 1, ?, ?, 1)",
+				elementID, savedDash2.ID, time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.ErrorIs(t, err, libmodel.ErrFolderHasConnectedLibraryElements)
+		require.Empty(t, searchTrash(t))
+	})
+
+	t.Run("Should free the name and uid of a dashboard deleted from the trash", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDash2.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboardFromTrash(context.Background(), &dashboards.DeleteDashboardFromTrashCommand{UID: savedDash2.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, searchTrash(t))
+
+		cmd := dashboards.SaveDashboardCommand{
+			OrgID:     1,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"uid": savedDash2.UID, "title": savedDash2.Title}),
+		}
+		_, err = dashboardStore.ValidateDashboardBeforeSave(context.Background(), cmd.GetDashboardModel(), false)
+		require.NoError(t, err)
+	})
+
+	t.Run("Should only delete dashboards in the trash permanently", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboardFromTrash(context.Background(), &dashboards.DeleteDashboardFromTrashCommand{UID: savedDash2.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		_, err = dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: savedDash2.UID, OrgID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should delete dashboards which are in the trash for longer than retention", func(t *testing.T) {
+		setup()
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		cmd := &dashboards.DeleteExpiredDashboardsCommand{OlderThan: time.Now().Add(-time.Hour)}
+		err = dashboardStore.DeleteExpiredDashboards(context.Background(), cmd)
+		require.NoError(t, err)
+		require.Zero(t, cmd.DeletedRows)
+		require.Len(t, searchTrash(t), 1)
+
+		cmd = &dashboards.DeleteExpiredDashboardsCommand{OlderThan: time.Now().Add(time.Hour)}
+		err = dashboardStore.DeleteExpiredDashboards(context.Background(), cmd)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), cmd.DeletedRows)
+		require.Empty(t, searchTrash(t))
+
+		_, err = dashboardStore.RestoreDeletedDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: savedDash.UID, OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		dash, err := dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{UID: savedDash2.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, savedDash2.ID, dash.ID)
+	})
+}
+
+func insertTestLibraryElement(t *testing.T, sqlStore db.DB, folder *dashboards.Dashboard) int64 {
+	t.Helper()
+	var id int64
+	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO library_element (org_id, folder_id, uid, name, kind, type, description, model, created, created_by, updated, updated_by, version) VALUES (?, ?, ?, 'panel', 1, 'text', '', '{}', ?, 1, ?, 1, 1)",
+			folder.OrgID, folder.ID, util.GenerateShortUID(), time.Now(), time.Now())
+		if err != nil {
+			return err
+		}
+		_, err = sess.SQL("SELECT id FROM library_element WHERE org_id = ? AND folder_id = ?", folder.OrgID, folder.ID).Get(&id)
+		return err
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func countLibraryElements(t *testing.T, sqlStore db.DB, folderID int64) int64 {
+	t.Helper()
+	var count int64
+	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		var err error
+		count, err = sess.SQL("SELECT COUNT(*) FROM library_element WHERE folder_id = ?", folderID).Count()
+		return err
+	})
+	require.NoError(t, err)
+	return count
+}
+
 func insertTestRule(t *testing.T, sqlStore db.DB, foderOrgID int64, folderUID string) {
 	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
 		type alertQuery struct {
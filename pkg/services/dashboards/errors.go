@@ -121,6 +121,21 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrDashboardDeleted = DashboardErr{
+		Reason:     "Dashboard is in the trash, restore or permanently delete it before saving",
+		StatusCode: 412,
+		Status:     "deleted",
+	}
+	ErrDashboardFolderDeleted = DashboardErr{
+		Reason:     "Folder of the dashboard is in the trash, restore the folder first",
+		StatusCode: 412,
+		Status:     "folder-deleted",
+	}
+	ErrDashboardWithSameNameInTrash = DashboardErr{
+		Reason:     "A dashboard with the same name in the folder is in the trash, restore or permanently delete it first",
+		StatusCode: 412,
+		Status:     "name-exists",
+	}
 
 	ErrFolderNotFound           = errors.New("folder not found")
 	ErrFolderVersionMismatch    = errors.New("the folder has been changed by someone else")
@@ -34,6 +34,8 @@ type Dashboard struct {
 
 	Created time.Time
 	Updated time.Time
+	// Deleted is set when the dashboard is moved to the trash.
+	Deleted time.Time
 
 	UpdatedBy int64
 	CreatedBy int64
@@ -187,16 +189,18 @@ type ValidateDashboardBeforeSaveResult struct {
 //
 
 type SaveDashboardCommand struct {
-	Dashboard    *simplejson.Json `json:"dashboard" binding:"Required"`
-	UserID       int64            `json:"userId" xorm:"user_id"`
-	Overwrite    bool             `json:"overwrite"`
-	Message      string           `json:"message"`
-	OrgID        int64            `json:"-" xorm:"org_id"`
-	RestoredFrom int              `json:"-"`
-	PluginID     string           `json:"-" xorm:"plugin_id"`
-	FolderID     int64            `json:"folderId" xorm:"folder_id"`
-	FolderUID    string           `json:"folderUid" xorm:"folder_uid"`
-	IsFolder     bool             `json:"isFolder"`
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+	UserID    int64            `json:"userId" xorm:"user_id"`
+	Overwrite bool             `json:"overwrite"`
+	// Merge makes the save of an outdated version merge its changes with the changes saved since then.
+	Merge        bool   `json:"merge"`
+	Message      string `json:"message"`
+	OrgID        int64  `json:"-" xorm:"org_id"`
+	RestoredFrom int    `json:"-"`
+	PluginID     string `json:"-" xorm:"plugin_id"`
+	FolderID     int64  `json:"folderId" xorm:"folder_id"`
+	FolderUID    string `json:"folderUid" xorm:"folder_uid"`
+	IsFolder     bool   `json:"isFolder"`
 
 	UpdatedAt time.Time
 }
@@ -229,6 +233,22 @@ type DeleteOrphanedProvisionedDashboardsCommand struct {
 	ReaderNames []string
 }
 
+type RestoreDeletedDashboardCommand struct {
+	UID   string
+	OrgID int64
+	User  *user.SignedInUser
+}
+
+type DeleteDashboardFromTrashCommand struct {
+	UID   string
+	OrgID int64
+}
+
+type DeleteExpiredDashboardsCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
+
 //
 // QUERIES
 //
@@ -290,6 +310,7 @@ type DashboardSearchProjection struct {
 	FolderSlug  string
 	FolderTitle string
 	SortMeta    int64
+	Deleted     time.Time
 }
 
 const (
@@ -414,6 +435,8 @@ type FindPersistedDashboardsQuery struct {
 	Page          int64
 	Permission    PermissionType
 	Sort          model.SortOption
+	// Deleted makes the query return dashboards and folders in the trash instead.
+	Deleted bool
 
 	Filters []interface{}
 
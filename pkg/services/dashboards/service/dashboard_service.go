@@ -393,6 +393,63 @@ func (dr *DashboardServiceImpl) DeleteDashboard(ctx context.Context, dashboardId
 	return dr.deleteDashboard(ctx, dashboardId, orgId, true)
 }
 
+// SoftDeleteDashboard moves a dashboard or folder to the trash. Errors out if the dashboard was provisioned.
+func (dr *DashboardServiceImpl) SoftDeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
+	provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(ctx, dashboardId)
+	if err != nil {
+		return fmt.Errorf("%v: %w", "failed to check if dashboard is provisioned", err)
+	}
+
+	if provisionedData != nil {
+		return dashboards.ErrDashboardCannotDeleteProvisionedDashboard
+	}
+
+	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}
+	return dr.dashboardStore.SoftDeleteDashboard(ctx, cmd)
+}
+
+// RestoreDeletedDashboard moves a dashboard or folder out of the trash. Legacy alerts, which are removed
+// when a dashboard is deleted, are extracted from the restored dashboards again.
+func (dr *DashboardServiceImpl) RestoreDeletedDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) (*dashboards.Dashboard, error) {
+	restored, err := dr.dashboardStore.RestoreDeletedDashboard(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if setting.IsLegacyAlertingEnabled() {
+		for _, dash := range restored {
+			if dash.IsFolder {
+				continue
+			}
+
+			dashAlertInfo := alerting.DashAlertInfo{
+				User:  cmd.User,
+				Dash:  dash,
+				OrgID: cmd.OrgID,
+			}
+			alerts, err := dr.dashAlertExtractor.GetAlerts(ctx, dashAlertInfo)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := dr.dashboardStore.SaveAlerts(ctx, dash.ID, alerts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return restored[0], nil
+}
+
+// DeleteDashboardFromTrash permanently deletes a dashboard or folder which is in the trash.
+func (dr *DashboardServiceImpl) DeleteDashboardFromTrash(ctx context.Context, cmd *dashboards.DeleteDashboardFromTrashCommand) error {
+	return dr.dashboardStore.DeleteDashboardFromTrash(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) DeleteExpiredDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredDashboardsCommand) error {
+	return dr.dashboardStore.DeleteExpiredDashboards(ctx, cmd)
+}
+
 func (dr *DashboardServiceImpl) GetDashboardByPublicUid(ctx context.Context, dashboardPublicUid string) (*dashboards.Dashboard, error) {
 	return nil, nil
 }
@@ -90,6 +90,34 @@ func (_m *FakeDashboardStore) DeleteDashboard(ctx context.Context, cmd *DeleteDa
 	return r0
 }
 
+// DeleteDashboardFromTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) DeleteDashboardFromTrash(ctx context.Context, cmd *DeleteDashboardFromTrashCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardFromTrashCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) DeleteExpiredDashboards(ctx context.Context, cmd *DeleteExpiredDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -376,6 +404,29 @@ func (_m *FakeDashboardStore) HasEditPermissionInFolders(ctx context.Context, qu
 	return r0, r1
 }
 
+// RestoreDeletedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) ([]*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 []*Dashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) []*Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Dashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -436,6 +487,20 @@ func (_m *FakeDashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd
 	return r0, r1
 }
 
+// SoftDeleteDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) SoftDeleteDashboard(ctx context.Context, cmd *DeleteDashboardCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, id
 func (_m *FakeDashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -480,7 +480,16 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder) error {
 	deleteCmd := dashboards.DeleteDashboardCommand{OrgID: cmd.OrgID, ID: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules}
 
-	if err := s.dashboardStore.DeleteDashboard(ctx, &deleteCmd); err != nil {
+	// Nested folders are removed from the folder table right away, so they cannot be
+	// moved to the trash and restored later.
+	if s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
+		if err := s.dashboardStore.DeleteDashboard(ctx, &deleteCmd); err != nil {
+			return toFolderError(err)
+		}
+		return nil
+	}
+
+	if err := s.dashboardStore.SoftDeleteDashboard(ctx, &deleteCmd); err != nil {
 		return toFolderError(err)
 	}
 	return nil
@@ -6,6 +6,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/middleware"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -23,6 +25,7 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 		entities.Get("/:uid/connections/", middleware.ReqSignedIn, routing.Wrap(l.getConnectionsHandler))
 		entities.Get("/name/:name", middleware.ReqSignedIn, routing.Wrap(l.getByNameHandler))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(l.patchHandler))
+		entities.Post("/:uid/diff", middleware.ReqSignedIn, routing.Wrap(l.diffHandler))
 	})
 }
 
@@ -202,6 +205,54 @@ func (l *LibraryElementService) patchHandler(c *contextmodel.ReqContext) respons
 	return response.JSON(http.StatusOK, model.LibraryElementResponse{Result: element})
 }
 
+// swagger:route POST /library-elements/{library_element_uid}/diff library_elements diffLibraryElement
+//
+// Compare library element with a changed model.
+//
+// Returns the changes of a library panel or variable between the stored model and the model
+// in the request, e.g. to review them before updating the library element.
+//
+// Responses:
+// 200: diffLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) diffHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := model.DiffLibraryElementCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	newModel, err := simplejson.NewJson(cmd.Model)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "invalid model", err)
+	}
+
+	element, err := l.getLibraryElementByUid(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element")
+	}
+	baseModel, err := simplejson.NewJson(element.Model)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to read library element model", err)
+	}
+
+	var changes []dashdiffs.Change
+	if element.Kind == int64(model.VariableElement) {
+		changes, err = dashdiffs.DiffVariables(baseModel, newModel)
+	} else {
+		changes, err = dashdiffs.DiffPanels(baseModel, newModel)
+	}
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to compare library element", err)
+	}
+	if changes == nil {
+		changes = []dashdiffs.Change{}
+	}
+
+	return response.JSON(http.StatusOK, changes)
+}
+
 // swagger:route GET /library-elements/{library_element_uid}/connections/ library_elements getLibraryElementConnections
 //
 // Get library element connections.
@@ -383,6 +434,22 @@ type GetLibraryElementResponse struct {
 	Body model.LibraryElementResponse `json:"body"`
 }
 
+// swagger:parameters diffLibraryElement
+type DiffLibraryElementParams struct {
+	// in:body
+	// required:true
+	Body model.DiffLibraryElementCommand `json:"body"`
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+}
+
+// swagger:response diffLibraryElementResponse
+type DiffLibraryElementResponse struct {
+	// in: body
+	Body []dashdiffs.Change `json:"body"`
+}
+
 // swagger:response getLibraryElementConnectionsResponse
 type GetLibraryElementConnectionsResponse struct {
 	// in: body
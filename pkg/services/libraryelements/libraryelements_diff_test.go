@@ -0,0 +1,60 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestDiffLibraryElement(t *testing.T) {
+	scenarioWithPanel(t, "When an admin tries to diff a library panel that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			cmd := model.DiffLibraryElementCommand{Model: []byte(`{"type": "text"}`)}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown"})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin tries to diff a library panel with a changed model, it should return the changes",
+		func(t *testing.T, sc scenarioContext) {
+			cmd := model.DiffLibraryElementCommand{Model: []byte(`
+				{
+				  "datasource": "${DS_GDEV-TESTDATA}",
+				  "id": 1,
+				  "title": "Text - Library Panel",
+				  "type": "stat",
+				  "description": "A description",
+				  "targets": [{"refId": "A", "expr": "up"}]
+				}
+			`)}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var changes []dashdiffs.Change
+			err := json.Unmarshal(resp.Body(), &changes)
+			require.NoError(t, err)
+			require.Len(t, changes, 2)
+			require.Equal(t, `panel "Text - Library Panel" query A added`, changes[0].Summary)
+			require.Equal(t, `panel "Text - Library Panel" visualization changed`, changes[1].Summary)
+		})
+
+	scenarioWithPanel(t, "When an admin tries to diff a library panel with the same model, it should return no changes",
+		func(t *testing.T, sc scenarioContext) {
+			unchanged, err := json.Marshal(sc.initialResult.Result.Model)
+			require.NoError(t, err)
+			cmd := model.DiffLibraryElementCommand{Model: unchanged}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.JSONEq(t, `[]`, string(resp.Body()))
+		})
+}
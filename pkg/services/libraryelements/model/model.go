@@ -200,6 +200,12 @@ type PatchLibraryElementCommand struct {
 	UID string `json:"uid"`
 }
 
+// DiffLibraryElementCommand is the command for comparing a LibraryElement with a changed model
+type DiffLibraryElementCommand struct {
+	// The changed JSON model of the library element.
+	Model json.RawMessage `json:"model" binding:"Required"`
+}
+
 // SearchLibraryElementsQuery is the query used for searching for Elements
 type SearchLibraryElementsQuery struct {
 	PerPage          int
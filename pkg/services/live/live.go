@@ -158,6 +158,10 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	channelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, nil)
 
+	history := managedstream.HistoryConfig{
+		MaxFrames: g.Cfg.LiveManagedStreamHistorySize,
+		MaxAge:    g.Cfg.LiveManagedStreamHistoryDuration,
+	}
 	var managedStreamRunner *managedstream.Runner
 	if g.IsHA() {
 		redisClient := redis.NewClient(&redis.Options{
@@ -170,13 +174,13 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewRedisFrameCache(redisClient),
+			managedstream.NewRedisFrameCache(redisClient, history),
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewMemoryFrameCache(),
+			managedstream.NewMemoryFrameCache(history),
 		)
 	}
 
@@ -195,6 +199,8 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		DashboardService: dashboardService,
 	}
 	g.storage = database.NewStorage(g.SQLStore, g.CacheService)
+	// Channel rules and write configs are kept in the database, so that they are shared by all instances in HA setups.
+	g.pipelineStorage = pipeline.NewSQLStorage(g.SQLStore, g.SecretsService)
 	g.GrafanaScope.Dashboards = dash
 	g.GrafanaScope.Features["dashboard"] = dash
 	g.GrafanaScope.Features["broadcast"] = features.NewBroadcastRunner(g.storage)
@@ -1108,6 +1114,7 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 		Node:                 g.node,
 		ManagedStream:        g.ManagedStreamRunner,
 		FrameStorage:         pipeline.NewFrameStorage(),
+		WindowStorage:        pipeline.NewWindowStorage(),
 		Storage:              storage,
 		ChannelHandlerGetter: g,
 	}
@@ -4,20 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 // MemoryFrameCache ...
 type MemoryFrameCache struct {
-	mu     sync.RWMutex
-	frames map[int64]map[string]data.FrameJSONCache
+	mu      sync.RWMutex
+	frames  map[int64]map[string]data.FrameJSONCache
+	history HistoryConfig
+	buffers map[int64]map[string]*historyBuffer
 }
 
 // NewMemoryFrameCache ...
-func NewMemoryFrameCache() *MemoryFrameCache {
+func NewMemoryFrameCache(history HistoryConfig) *MemoryFrameCache {
 	return &MemoryFrameCache{
-		frames: map[int64]map[string]data.FrameJSONCache{},
+		frames:  map[int64]map[string]data.FrameJSONCache{},
+		history: history,
+		buffers: map[int64]map[string]*historyBuffer{},
 	}
 }
 
@@ -42,6 +47,17 @@ func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel st
 	return cachedFrame.Bytes(data.IncludeAll), ok, nil
 }
 
+func (c *MemoryFrameCache) GetHistory(_ context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
+	c.mu.RLock()
+	buffer, ok := c.buffers[orgID][channel]
+	var frames []json.RawMessage
+	if ok {
+		frames = buffer.frames(c.history.minTime(time.Now()))
+	}
+	c.mu.RUnlock()
+	return mergeHistory(frames)
+}
+
 func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -51,5 +67,16 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	cachedJsonFrame, exists := c.frames[orgID][channel]
 	schemaUpdated := !exists || !cachedJsonFrame.SameSchema(&jsonFrame)
 	c.frames[orgID][channel] = jsonFrame
+	if c.history.enabled() {
+		if _, ok := c.buffers[orgID]; !ok {
+			c.buffers[orgID] = map[string]*historyBuffer{}
+		}
+		buffer, ok := c.buffers[orgID][channel]
+		if !ok {
+			buffer = newHistoryBuffer(c.history.MaxFrames)
+			c.buffers[orgID][channel] = buffer
+		}
+		buffer.push(historyEntry{Time: time.Now().UnixMilli(), Frame: jsonFrame.Bytes(data.IncludeAll)})
+	}
 	return schemaUpdated, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -62,8 +63,77 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NotEqual(t, string(channels["test"]), string(schema))
 }
 
+func testFrameCacheHistory(t *testing.T, c FrameCache) {
+	// History is kept for 3 frames in tests.
+	push := func(frame *data.Frame) {
+		frameJsonCache, err := data.FrameToJSONCache(frame)
+		require.NoError(t, err)
+		_, err = c.Update(context.Background(), 1, "history", frameJsonCache)
+		require.NoError(t, err)
+	}
+
+	_, ok, err := c.GetHistory(context.Background(), 1, "history")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// The first frame has another schema, so it's never merged with the others.
+	push(data.NewFrame("history", data.NewField("value", nil, []string{"a"})))
+	for i := 1; i <= 4; i++ {
+		push(data.NewFrame("history", data.NewField("value", nil, []int64{int64(i)})))
+	}
+
+	frameJSON, ok, err := c.GetHistory(context.Background(), 1, "history")
+	require.NoError(t, err)
+	require.True(t, ok)
+	var f data.Frame
+	err = json.Unmarshal(frameJSON, &f)
+	require.NoError(t, err)
+	require.Equal(t, 3, f.Rows())
+	require.Equal(t, []interface{}{int64(2), int64(3), int64(4)}, []interface{}{f.Fields[0].At(0), f.Fields[0].At(1), f.Fields[0].At(2)})
+
+	push(data.NewFrame("history", data.NewField("value", nil, []string{"b"})))
+	frameJSON, ok, err = c.GetHistory(context.Background(), 1, "history")
+	require.NoError(t, err)
+	require.True(t, ok)
+	var changed data.Frame
+	err = json.Unmarshal(frameJSON, &changed)
+	require.NoError(t, err)
+	require.Equal(t, 1, changed.Rows())
+	require.Equal(t, "b", changed.Fields[0].At(0))
+
+	// History is isolated by organization.
+	_, ok, err = c.GetHistory(context.Background(), 2, "history")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func TestMemoryFrameCache(t *testing.T) {
-	c := NewMemoryFrameCache()
+	c := NewMemoryFrameCache(HistoryConfig{MaxFrames: 3})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
+	testFrameCacheHistory(t, c)
+}
+
+func TestMemoryFrameCache_HistoryDisabled(t *testing.T) {
+	c := NewMemoryFrameCache(HistoryConfig{})
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello"))
+	require.NoError(t, err)
+	_, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	require.NoError(t, err)
+
+	_, ok, err := c.GetHistory(context.Background(), 1, "test")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestHistoryBuffer_MaxAge(t *testing.T) {
+	now := time.Now()
+	history := HistoryConfig{MaxFrames: 2, MaxAge: time.Minute}
+	b := newHistoryBuffer(history.MaxFrames)
+	b.push(historyEntry{Time: now.Add(-2 * time.Minute).UnixMilli(), Frame: json.RawMessage("1")})
+	b.push(historyEntry{Time: now.Add(-30 * time.Second).UnixMilli(), Frame: json.RawMessage("2")})
+	require.Equal(t, []json.RawMessage{json.RawMessage("2")}, b.frames(history.minTime(now)))
+
+	b.push(historyEntry{Time: now.UnixMilli(), Frame: json.RawMessage("3")})
+	require.Equal(t, []json.RawMessage{json.RawMessage("2"), json.RawMessage("3")}, b.frames(0))
 }
@@ -18,13 +18,15 @@ type RedisFrameCache struct {
 	mu          sync.RWMutex
 	redisClient *redis.Client
 	frames      map[int64]map[string]data.FrameJSONCache
+	history     HistoryConfig
 }
 
 // NewRedisFrameCache ...
-func NewRedisFrameCache(redisClient *redis.Client) *RedisFrameCache {
+func NewRedisFrameCache(redisClient *redis.Client, history HistoryConfig) *RedisFrameCache {
 	return &RedisFrameCache{
 		frames:      map[int64]map[string]data.FrameJSONCache{},
 		redisClient: redisClient,
+		history:     history,
 	}
 }
 
@@ -55,6 +57,29 @@ func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel str
 	return json.RawMessage(result["frame"]), true, nil
 }
 
+func (c *RedisFrameCache) GetHistory(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
+	if !c.history.enabled() {
+		return nil, false, nil
+	}
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	result, err := c.redisClient.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	minTime := c.history.minTime(time.Now())
+	frames := make([]json.RawMessage, 0, len(result))
+	for _, item := range result {
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			return nil, false, err
+		}
+		if entry.Time >= minTime {
+			frames = append(frames, entry.Frame)
+		}
+	}
+	return mergeHistory(frames)
+}
+
 const (
 	frameCacheTTL = 7 * 24 * time.Hour
 )
@@ -80,6 +105,16 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 		"frame":  string(jsonFrame.Bytes(data.IncludeAll)),
 	})
 	pipe.Expire(ctx, key, frameCacheTTL)
+	if c.history.enabled() {
+		entry, err := json.Marshal(historyEntry{Time: time.Now().UnixMilli(), Frame: jsonFrame.Bytes(data.IncludeAll)})
+		if err != nil {
+			return false, err
+		}
+		historyKey := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+		pipe.RPush(ctx, historyKey, entry)
+		pipe.LTrim(ctx, historyKey, int64(-c.history.MaxFrames), -1)
+		pipe.Expire(ctx, historyKey, frameCacheTTL)
+	}
 
 	replies, err := pipe.Exec(ctx)
 	if err != nil {
@@ -110,3 +145,7 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 func getCacheKey(channelID string) string {
 	return "gf_live.managed_stream." + channelID
 }
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
@@ -14,7 +14,8 @@ func TestRedisCacheStorage(t *testing.T) {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: "localhost:6379",
 	})
-	c := NewRedisFrameCache(redisClient)
+	c := NewRedisFrameCache(redisClient, HistoryConfig{MaxFrames: 3})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
+	testFrameCacheHistory(t, c)
 }
@@ -0,0 +1,112 @@
+package managedstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// HistoryConfig limits the history of frames kept for every managed stream channel.
+type HistoryConfig struct {
+	// MaxFrames is a maximum number of frames kept per channel, 0 disables history.
+	MaxFrames int
+	// MaxAge is a maximum age of frames returned from history, 0 means no limit.
+	MaxAge time.Duration
+}
+
+func (c HistoryConfig) enabled() bool {
+	return c.MaxFrames > 0
+}
+
+// minTime returns the time in Unix milliseconds before which frames are too old to be returned.
+func (c HistoryConfig) minTime(now time.Time) int64 {
+	if c.MaxAge <= 0 {
+		return 0
+	}
+	return now.Add(-c.MaxAge).UnixMilli()
+}
+
+type historyEntry struct {
+	// Time is a Unix time in milliseconds when the frame was pushed.
+	Time  int64           `json:"time"`
+	Frame json.RawMessage `json:"frame"`
+}
+
+// historyBuffer is a fixed size ring buffer of channel frames.
+type historyBuffer struct {
+	entries []historyEntry
+	start   int
+	count   int
+}
+
+func newHistoryBuffer(size int) *historyBuffer {
+	return &historyBuffer{entries: make([]historyEntry, size)}
+}
+
+func (b *historyBuffer) push(entry historyEntry) {
+	b.entries[(b.start+b.count)%len(b.entries)] = entry
+	if b.count < len(b.entries) {
+		b.count++
+		return
+	}
+	b.start = (b.start + 1) % len(b.entries)
+}
+
+// frames returns frames pushed not before minTime, the oldest first.
+func (b *historyBuffer) frames(minTime int64) []json.RawMessage {
+	frames := make([]json.RawMessage, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		entry := b.entries[(b.start+i)%len(b.entries)]
+		if entry.Time >= minTime {
+			frames = append(frames, entry.Frame)
+		}
+	}
+	return frames
+}
+
+// mergeHistory concatenates rows of history frames into one frame. The schema of a channel may
+// change over time, so only the most recent frames with the same schema as the last one are merged.
+func mergeHistory(frames []json.RawMessage) (json.RawMessage, bool, error) {
+	if len(frames) == 0 {
+		return nil, false, nil
+	}
+	decoded := make([]*data.Frame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		var frame data.Frame
+		if err := json.Unmarshal(frames[i], &frame); err != nil {
+			return nil, false, fmt.Errorf("error decoding history frame: %w", err)
+		}
+		if len(decoded) > 0 && !sameSchema(decoded[0], &frame) {
+			break
+		}
+		decoded = append(decoded, &frame)
+	}
+
+	result := decoded[len(decoded)-1]
+	for i := len(decoded) - 2; i >= 0; i-- {
+		for fieldIdx, field := range decoded[i].Fields {
+			for row := 0; row < field.Len(); row++ {
+				result.Fields[fieldIdx].Append(field.At(row))
+			}
+		}
+	}
+	frameJSON, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return nil, false, err
+	}
+	return frameJSON, true, nil
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() || !a.Fields[i].Labels.Equals(b.Fields[i].Labels) {
+			return false
+		}
+	}
+	return true
+}
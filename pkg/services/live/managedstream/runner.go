@@ -241,10 +241,17 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (model.ChannelHandler, err
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u *user.SignedInUser, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := model.SubscribeReply{}
-	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.OrgID, e.Channel)
+	// Prefer recent history, so that subscribers get the last points straight away.
+	frameJSON, ok, err := s.frameCache.GetHistory(ctx, u.OrgID, e.Channel)
 	if err != nil {
 		return reply, 0, err
 	}
+	if !ok {
+		frameJSON, ok, err = s.frameCache.GetFrame(ctx, u.OrgID, e.Channel)
+		if err != nil {
+			return reply, 0, err
+		}
+	}
 	if ok {
 		reply.Data = frameJSON
 	}
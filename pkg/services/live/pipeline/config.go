@@ -40,6 +40,8 @@ type ConverterConfig struct {
 	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
 	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
 	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	PromTextConverterConfig   *PromTextConverterConfig   `json:"promText,omitempty"`
+	OtlpConverterConfig       *OtlpConverterConfig       `json:"otlp,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -50,11 +52,43 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Renames maps current field names to new field names.
+	Renames map[string]string `json:"renames"`
+}
+
+type AddLabelsFrameProcessorConfig struct {
+	// Labels to set on fields. Existing labels with the same name are overwritten.
+	Labels map[string]string `json:"labels"`
+	// FieldNames is an optional list of fields to label, all non-time fields are labeled by default.
+	FieldNames []string `json:"fieldNames,omitempty"`
+}
+
+type ComputeFieldFrameProcessorConfig struct {
+	// FieldName is a name of the computed field.
+	FieldName string `json:"fieldName"`
+	// Expression is a math expression, other fields are referenced as $name or ${name}.
+	Expression string `json:"expression"`
+}
+
+type AggregateFrameProcessorConfig struct {
+	// WindowSeconds is a size of the aggregation window in seconds.
+	WindowSeconds int64 `json:"windowSeconds"`
+	// Reducer applied to numeric fields: min, max or mean.
+	Reducer string `json:"reducer"`
+	// FieldNames is an optional list of numeric fields to aggregate, all numeric fields are aggregated by default.
+	FieldNames []string `json:"fieldNames,omitempty"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                        string                            `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig   *DropFieldsFrameProcessorConfig   `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig   *KeepFieldsFrameProcessorConfig   `json:"keepFields,omitempty"`
+	MultipleProcessorConfig     *MultipleFrameProcessorConfig     `json:"multiple,omitempty"`
+	RenameFieldsProcessorConfig *RenameFieldsFrameProcessorConfig `json:"renameFields,omitempty"`
+	AddLabelsProcessorConfig    *AddLabelsFrameProcessorConfig    `json:"addLabels,omitempty"`
+	ComputeFieldProcessorConfig *ComputeFieldFrameProcessorConfig `json:"computeField,omitempty"`
+	AggregateProcessorConfig    *AggregateFrameProcessorConfig    `json:"aggregate,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -152,4 +186,8 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
+type PromTextConverterConfig struct{}
+
+type OtlpConverterConfig struct{}
+
 type ManagedStreamOutputConfig struct{}
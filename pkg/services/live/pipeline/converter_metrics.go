@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// metricSample is a single value of a labelled time series.
+type metricSample struct {
+	name   string
+	labels data.Labels
+	time   time.Time
+	value  float64
+}
+
+// metricFamilies groups samples by metric family name keeping the order
+// in which families appear in input.
+type metricFamilies struct {
+	order   []string
+	samples map[string][]metricSample
+}
+
+func newMetricFamilies() *metricFamilies {
+	return &metricFamilies{samples: map[string][]metricSample{}}
+}
+
+func (m *metricFamilies) add(family string, sample metricSample) {
+	if _, ok := m.samples[family]; !ok {
+		m.order = append(m.order, family)
+	}
+	m.samples[family] = append(m.samples[family], sample)
+}
+
+// toChannelFrames creates a frame for every metric family, the channel of a frame
+// is constructed from original channel + / + <family_name>. Every frame has a time
+// field and a nullable float64 field per unique sample name and labels combination.
+func (m *metricFamilies) toChannelFrames(channel string) []*ChannelFrame {
+	channelFrames := make([]*ChannelFrame, 0, len(m.order))
+	for _, family := range m.order {
+		samples := m.samples[family]
+
+		var times []time.Time
+		timeIndex := map[time.Time]int{}
+		for _, s := range samples {
+			if _, ok := timeIndex[s.time]; !ok {
+				timeIndex[s.time] = len(times)
+				times = append(times, s.time)
+			}
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		for i, t := range times {
+			timeIndex[t] = i
+		}
+
+		fields := []*data.Field{data.NewField("time", nil, times)}
+		fieldIndex := map[string]int{}
+		for _, s := range samples {
+			key := s.name + s.labels.String()
+			idx, ok := fieldIndex[key]
+			if !ok {
+				fields = append(fields, data.NewField(s.name, s.labels, make([]*float64, len(times))))
+				idx = len(fields) - 1
+				fieldIndex[key] = idx
+			}
+			value := s.value
+			fields[idx].Set(timeIndex[s.time], &value)
+		}
+
+		channelFrames = append(channelFrames, &ChannelFrame{
+			Channel: channel + "/" + family,
+			Frame:   data.NewFrame(family, fields...),
+		})
+	}
+	return channelFrames
+}
@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// OtlpConverter decodes OTLP/HTTP protobuf metrics export requests and transforms them
+// to several ChannelFrame objects where Channel is constructed from original
+// channel + / + <metric_name>. Resource attributes and data point attributes become
+// field labels, histograms and summaries are expanded into _bucket, _sum and _count
+// series like Prometheus does.
+type OtlpConverter struct {
+	config      OtlpConverterConfig
+	nowTimeFunc func() time.Time
+}
+
+func NewOtlpConverter(c OtlpConverterConfig) *OtlpConverter {
+	return &OtlpConverter{config: c, nowTimeFunc: time.Now}
+}
+
+const ConverterTypeOtlp = "otlp"
+
+func (c *OtlpConverter) Type() string {
+	return ConverterTypeOtlp
+}
+
+func (c *OtlpConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	var req collectormetrics.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	now := c.nowTimeFunc()
+	sampleTime := func(unixNano uint64) time.Time {
+		if unixNano == 0 {
+			return now
+		}
+		return time.Unix(0, int64(unixNano))
+	}
+
+	families := newMetricFamilies()
+	for _, rm := range req.GetResourceMetrics() {
+		resourceLabels := otlpLabels(nil, rm.GetResource().GetAttributes())
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				name := m.GetName()
+				add := func(sampleName string, labels data.Labels, ts time.Time, value float64) {
+					families.add(name, metricSample{name: sampleName, labels: labels, time: ts, value: value})
+				}
+				var numberDataPoints []*metricsv1.NumberDataPoint
+				switch {
+				case m.GetGauge() != nil:
+					numberDataPoints = m.GetGauge().GetDataPoints()
+				case m.GetSum() != nil:
+					numberDataPoints = m.GetSum().GetDataPoints()
+				case m.GetHistogram() != nil:
+					for _, dp := range m.GetHistogram().GetDataPoints() {
+						ts := sampleTime(dp.GetTimeUnixNano())
+						var cumulative uint64
+						for i, count := range dp.GetBucketCounts() {
+							cumulative += count
+							labels := otlpLabels(resourceLabels, dp.GetAttributes())
+							if i < len(dp.GetExplicitBounds()) {
+								labels["le"] = formatFloat(dp.GetExplicitBounds()[i])
+							} else {
+								labels["le"] = "+Inf"
+							}
+							add(name+"_bucket", labels, ts, float64(cumulative))
+						}
+						add(name+"_sum", otlpLabels(resourceLabels, dp.GetAttributes()), ts, dp.GetSum())
+						add(name+"_count", otlpLabels(resourceLabels, dp.GetAttributes()), ts, float64(dp.GetCount()))
+					}
+				case m.GetExponentialHistogram() != nil:
+					for _, dp := range m.GetExponentialHistogram().GetDataPoints() {
+						ts := sampleTime(dp.GetTimeUnixNano())
+						add(name+"_sum", otlpLabels(resourceLabels, dp.GetAttributes()), ts, dp.GetSum())
+						add(name+"_count", otlpLabels(resourceLabels, dp.GetAttributes()), ts, float64(dp.GetCount()))
+					}
+				case m.GetSummary() != nil:
+					for _, dp := range m.GetSummary().GetDataPoints() {
+						ts := sampleTime(dp.GetTimeUnixNano())
+						for _, q := range dp.GetQuantileValues() {
+							labels := otlpLabels(resourceLabels, dp.GetAttributes())
+							labels["quantile"] = formatFloat(q.GetQuantile())
+							add(name, labels, ts, q.GetValue())
+						}
+						add(name+"_sum", otlpLabels(resourceLabels, dp.GetAttributes()), ts, dp.GetSum())
+						add(name+"_count", otlpLabels(resourceLabels, dp.GetAttributes()), ts, float64(dp.GetCount()))
+					}
+				}
+				for _, dp := range numberDataPoints {
+					var value float64
+					switch v := dp.GetValue().(type) {
+					case *metricsv1.NumberDataPoint_AsDouble:
+						value = v.AsDouble
+					case *metricsv1.NumberDataPoint_AsInt:
+						value = float64(v.AsInt)
+					default:
+						continue
+					}
+					add(name, otlpLabels(resourceLabels, dp.GetAttributes()), sampleTime(dp.GetTimeUnixNano()), value)
+				}
+			}
+		}
+	}
+	return families.toChannelFrames(vars.Channel), nil
+}
+
+// otlpLabels merges attributes into a copy of base labels. Attributes of complex
+// types (arrays, key-value lists) are skipped.
+func otlpLabels(base data.Labels, attributes []*commonv1.KeyValue) data.Labels {
+	labels := base.Copy()
+	for _, kv := range attributes {
+		switch v := kv.GetValue().GetValue().(type) {
+		case *commonv1.AnyValue_StringValue:
+			labels[kv.GetKey()] = v.StringValue
+		case *commonv1.AnyValue_BoolValue:
+			labels[kv.GetKey()] = strconv.FormatBool(v.BoolValue)
+		case *commonv1.AnyValue_IntValue:
+			labels[kv.GetKey()] = strconv.FormatInt(v.IntValue, 10)
+		case *commonv1.AnyValue_DoubleValue:
+			labels[kv.GetKey()] = formatFloat(v.DoubleValue)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func stringAttribute(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}}}
+}
+
+func TestOtlpConverter_Convert(t *testing.T) {
+	ts := time.Date(2021, 01, 01, 12, 12, 12, 0, time.UTC)
+	req := &collectormetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricsv1.ResourceMetrics{{
+			Resource: &resourcev1.Resource{Attributes: []*commonv1.KeyValue{stringAttribute("service.name", "checkout")}},
+			ScopeMetrics: []*metricsv1.ScopeMetrics{{
+				Metrics: []*metricsv1.Metric{
+					{
+						Name: "queue_size",
+						Data: &metricsv1.Metric_Gauge{Gauge: &metricsv1.Gauge{DataPoints: []*metricsv1.NumberDataPoint{
+							{
+								Attributes:   []*commonv1.KeyValue{stringAttribute("queue", "a")},
+								TimeUnixNano: uint64(ts.UnixNano()),
+								Value:        &metricsv1.NumberDataPoint_AsInt{AsInt: 3},
+							},
+							{
+								Attributes:   []*commonv1.KeyValue{stringAttribute("queue", "b")},
+								TimeUnixNano: uint64(ts.UnixNano()),
+								Value:        &metricsv1.NumberDataPoint_AsDouble{AsDouble: 1.5},
+							},
+						}}},
+					},
+					{
+						Name: "latency",
+						Data: &metricsv1.Metric_Histogram{Histogram: &metricsv1.Histogram{DataPoints: []*metricsv1.HistogramDataPoint{{
+							BucketCounts:   []uint64{1, 2, 3},
+							ExplicitBounds: []float64{0.1, 1},
+							Sum:            proto.Float64(4.2),
+							Count:          6,
+						}}}},
+					},
+				},
+			}},
+		}},
+	}
+	body, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	converter := NewOtlpConverter(OtlpConverterConfig{})
+	converter.nowTimeFunc = func() time.Time {
+		return ts.Add(time.Minute)
+	}
+	channelFrames, err := converter.Convert(context.Background(), Vars{Channel: "stream/test/otlp"}, body)
+	require.NoError(t, err)
+	require.Len(t, channelFrames, 2)
+
+	gauge := channelFrames[0]
+	require.Equal(t, "stream/test/otlp/queue_size", gauge.Channel)
+	require.Equal(t, "queue_size", gauge.Frame.Name)
+	require.Len(t, gauge.Frame.Fields, 3)
+	require.True(t, ts.Equal(gauge.Frame.Fields[0].At(0).(time.Time)))
+	require.Equal(t, data.Labels{"service.name": "checkout", "queue": "a"}, gauge.Frame.Fields[1].Labels)
+	require.Equal(t, 3.0, *gauge.Frame.Fields[1].At(0).(*float64))
+	require.Equal(t, data.Labels{"service.name": "checkout", "queue": "b"}, gauge.Frame.Fields[2].Labels)
+	require.Equal(t, 1.5, *gauge.Frame.Fields[2].At(0).(*float64))
+
+	histogram := channelFrames[1]
+	require.Equal(t, "stream/test/otlp/latency", histogram.Channel)
+	require.True(t, ts.Add(time.Minute).Equal(histogram.Frame.Fields[0].At(0).(time.Time)))
+	var buckets []string
+	values := map[string]float64{}
+	for _, field := range histogram.Frame.Fields[1:] {
+		if field.Name == "latency_bucket" {
+			buckets = append(buckets, field.Labels["le"])
+		}
+		values[field.Name+field.Labels.String()] = *field.At(0).(*float64)
+	}
+	require.Equal(t, []string{"0.1", "1", "+Inf"}, buckets)
+	require.Equal(t, 1.0, values["latency_bucket"+data.Labels{"service.name": "checkout", "le": "0.1"}.String()])
+	require.Equal(t, 3.0, values["latency_bucket"+data.Labels{"service.name": "checkout", "le": "1"}.String()])
+	require.Equal(t, 6.0, values["latency_bucket"+data.Labels{"service.name": "checkout", "le": "+Inf"}.String()])
+	require.Equal(t, 4.2, values["latency_sum"+data.Labels{"service.name": "checkout"}.String()])
+	require.Equal(t, 6.0, values["latency_count"+data.Labels{"service.name": "checkout"}.String()])
+}
+
+func TestOtlpConverter_Convert_Invalid(t *testing.T) {
+	converter := NewOtlpConverter(OtlpConverterConfig{})
+	_, err := converter.Convert(context.Background(), Vars{}, []byte("not a protobuf message"))
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// PromTextConverter decodes Prometheus text exposition format input and transforms it
+// to several ChannelFrame objects where Channel is constructed from original
+// channel + / + <metric_family_name>. Histograms and summaries are expanded into
+// _bucket, _sum and _count series like Prometheus does.
+type PromTextConverter struct {
+	config      PromTextConverterConfig
+	nowTimeFunc func() time.Time
+}
+
+func NewPromTextConverter(c PromTextConverterConfig) *PromTextConverter {
+	return &PromTextConverter{config: c, nowTimeFunc: time.Now}
+}
+
+const ConverterTypePromText = "promText"
+
+func (c *PromTextConverter) Type() string {
+	return ConverterTypePromText
+}
+
+func (c *PromTextConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	var parser expfmt.TextParser
+	metricFamilies, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing metrics: %w", err)
+	}
+
+	names := make([]string, 0, len(metricFamilies))
+	for name := range metricFamilies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := c.nowTimeFunc()
+	families := newMetricFamilies()
+	for _, name := range names {
+		mf := metricFamilies[name]
+		for _, m := range mf.GetMetric() {
+			ts := now
+			if m.TimestampMs != nil {
+				ts = time.UnixMilli(m.GetTimestampMs())
+			}
+			add := func(sampleName string, labels data.Labels, value float64) {
+				families.add(name, metricSample{name: sampleName, labels: labels, time: ts, value: value})
+			}
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, promLabels(m), m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, promLabels(m), m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, promLabels(m), m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				summary := m.GetSummary()
+				for _, q := range summary.GetQuantile() {
+					labels := promLabels(m)
+					labels["quantile"] = formatFloat(q.GetQuantile())
+					add(name, labels, q.GetValue())
+				}
+				add(name+"_sum", promLabels(m), summary.GetSampleSum())
+				add(name+"_count", promLabels(m), float64(summary.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				histogram := m.GetHistogram()
+				hasInf := false
+				for _, b := range histogram.GetBucket() {
+					labels := promLabels(m)
+					labels["le"] = formatFloat(b.GetUpperBound())
+					hasInf = hasInf || math.IsInf(b.GetUpperBound(), 1)
+					add(name+"_bucket", labels, float64(b.GetCumulativeCount()))
+				}
+				if !hasInf {
+					labels := promLabels(m)
+					labels["le"] = "+Inf"
+					add(name+"_bucket", labels, float64(histogram.GetSampleCount()))
+				}
+				add(name+"_sum", promLabels(m), histogram.GetSampleSum())
+				add(name+"_count", promLabels(m), float64(histogram.GetSampleCount()))
+			}
+		}
+	}
+	return families.toChannelFrames(vars.Channel), nil
+}
+
+func promLabels(m *dto.Metric) data.Labels {
+	labels := data.Labels{}
+	for _, l := range m.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
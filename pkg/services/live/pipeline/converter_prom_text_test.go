@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromTextConverter_Convert(t *testing.T) {
+	// Safe to disable, this is a test.
+	// nolint:gosec
+	content, err := os.ReadFile(filepath.Join("testdata", "prom_text.txt"))
+	require.NoError(t, err)
+
+	converter := NewPromTextConverter(PromTextConverterConfig{})
+	converter.nowTimeFunc = func() time.Time {
+		return time.Date(2021, 01, 01, 12, 12, 12, 0, time.UTC)
+	}
+	channelFrames, err := converter.Convert(context.Background(), Vars{Channel: "stream/test/metrics"}, content)
+	require.NoError(t, err)
+
+	channels := make([]string, 0, len(channelFrames))
+	dr := &backend.DataResponse{}
+	for _, cf := range channelFrames {
+		channels = append(channels, cf.Channel)
+		dr.Frames = append(dr.Frames, cf.Frame)
+	}
+	require.Equal(t, []string{
+		"stream/test/metrics/go_goroutines",
+		"stream/test/metrics/http_request_duration_seconds",
+		"stream/test/metrics/http_requests_total",
+		"stream/test/metrics/rpc_duration_seconds",
+	}, channels)
+
+	experimental.CheckGoldenJSONResponse(t, "testdata", "prom_text.golden", dr, *update)
+}
+
+func TestPromTextConverter_Convert_Invalid(t *testing.T) {
+	converter := NewPromTextConverter(PromTextConverterConfig{})
+	_, err := converter.Convert(context.Background(), Vars{}, []byte("http_requests_total{method=\"post\" 1027"))
+	require.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// AddLabelsFrameProcessor can attach static labels to fields of a data.Frame.
+type AddLabelsFrameProcessor struct {
+	config AddLabelsFrameProcessorConfig
+}
+
+func NewAddLabelsFrameProcessor(config AddLabelsFrameProcessorConfig) *AddLabelsFrameProcessor {
+	return &AddLabelsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeAddLabels = "addLabels"
+
+func (p *AddLabelsFrameProcessor) Type() string {
+	return FrameProcessorTypeAddLabels
+}
+
+func (p *AddLabelsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, field := range frame.Fields {
+		if len(p.config.FieldNames) > 0 {
+			if !stringInSlice(field.Name, p.config.FieldNames) {
+				continue
+			}
+		} else if field.Type().Time() {
+			continue
+		}
+		// Labels may be shared between fields, so never modify them in place.
+		labels := field.Labels.Copy()
+		for k, v := range p.config.Labels {
+			labels[k] = v
+		}
+		field.Labels = labels
+	}
+	return frame, nil
+}
@@ -0,0 +1,279 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+const (
+	AggregateReducerMin  = "min"
+	AggregateReducerMax  = "max"
+	AggregateReducerMean = "mean"
+)
+
+const (
+	// maxAggregateWindows limits the number of open windows kept in the storage.
+	// When the limit is reached, the least recently used window is dropped.
+	maxAggregateWindows = 10000
+	// maxAggregateFields limits the number of fields in a window, since every
+	// distinct set of labels of a field is aggregated separately.
+	maxAggregateFields = 1000
+	// aggregateWindowIdleTimeout is how long a window is kept after its end if no frames
+	// arrive for it. It is dropped without being passed further.
+	aggregateWindowIdleTimeout = time.Hour
+)
+
+// AggregateFrameProcessor can downsample frames by aggregating numeric fields over
+// fixed time windows. Frames are accumulated until a point of the next window arrives,
+// then a frame with one row per completed window is passed further and the processing
+// of incoming frames stops until then. Fields that are not aggregated keep their last
+// value in the window.
+type AggregateFrameProcessor struct {
+	config  AggregateFrameProcessorConfig
+	storage *WindowStorage
+	now     func() time.Time
+}
+
+func NewAggregateFrameProcessor(storage *WindowStorage, config AggregateFrameProcessorConfig) (*AggregateFrameProcessor, error) {
+	if config.WindowSeconds <= 0 {
+		return nil, fmt.Errorf("window seconds must be positive")
+	}
+	switch config.Reducer {
+	case AggregateReducerMin, AggregateReducerMax, AggregateReducerMean:
+	default:
+		return nil, fmt.Errorf("unsupported reducer: %s", config.Reducer)
+	}
+	return &AggregateFrameProcessor{config: config, storage: storage, now: time.Now}, nil
+}
+
+const FrameProcessorTypeAggregate = "aggregate"
+
+func (p *AggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeAggregate
+}
+
+func (p *AggregateFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	numRows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	timeFieldIdx := -1
+	for i, field := range frame.Fields {
+		if field.Type().Time() {
+			timeFieldIdx = i
+			break
+		}
+	}
+
+	// Several aggregate processors may be configured for the same channel,
+	// so the key includes the configuration of the processor.
+	key := fmt.Sprintf("%s|%d|%s|%v", orgchannel.PrependOrgID(vars.OrgID, vars.Channel), p.config.WindowSeconds, p.config.Reducer, p.config.FieldNames)
+	window := time.Duration(p.config.WindowSeconds) * time.Second
+	state := p.storage.get(key, p.now(), window+aggregateWindowIdleTimeout)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	var completed []*aggregateWindow
+	for row := 0; row < numRows; row++ {
+		ts := p.now()
+		if timeFieldIdx >= 0 {
+			if v, ok := frame.Fields[timeFieldIdx].ConcreteAt(row); ok {
+				ts = v.(time.Time)
+			}
+		}
+		start := ts.Truncate(window)
+		if state.window != nil && start.After(state.window.start) {
+			completed = append(completed, state.window)
+			state.window = nil
+		}
+		if state.window == nil {
+			state.window = newAggregateWindow(start)
+		}
+		p.add(state.window, frame, timeFieldIdx, row)
+	}
+
+	if len(completed) == 0 {
+		return nil, nil
+	}
+	return p.toFrame(frame, timeFieldIdx, completed), nil
+}
+
+func (p *AggregateFrameProcessor) add(w *aggregateWindow, frame *data.Frame, timeFieldIdx int, row int) {
+	for i, field := range frame.Fields {
+		if i == timeFieldIdx {
+			continue
+		}
+		key := field.Name + field.Labels.String()
+		idx, ok := w.index[key]
+		if !ok {
+			if len(w.fields) >= maxAggregateFields {
+				continue
+			}
+			aggregate := field.Type().Numeric() && (len(p.config.FieldNames) == 0 || stringInSlice(field.Name, p.config.FieldNames))
+			w.fields = append(w.fields, &aggregateField{
+				name:      field.Name,
+				labels:    field.Labels,
+				fieldType: field.Type(),
+				aggregate: aggregate,
+			})
+			idx = len(w.fields) - 1
+			w.index[key] = idx
+		}
+		f := w.fields[idx]
+		if !f.aggregate {
+			if v, ok := field.ConcreteAt(row); ok {
+				f.last = v
+			}
+			continue
+		}
+		v, err := field.NullableFloatAt(row)
+		if err != nil || v == nil || math.IsNaN(*v) {
+			continue
+		}
+		switch {
+		case f.count == 0:
+			f.value = *v
+		case p.config.Reducer == AggregateReducerMin:
+			f.value = math.Min(f.value, *v)
+		case p.config.Reducer == AggregateReducerMax:
+			f.value = math.Max(f.value, *v)
+		default:
+			f.value += *v
+		}
+		f.count++
+	}
+}
+
+func (p *AggregateFrameProcessor) toFrame(frame *data.Frame, timeFieldIdx int, windows []*aggregateWindow) *data.Frame {
+	timeFieldName := "time"
+	if timeFieldIdx >= 0 {
+		timeFieldName = frame.Fields[timeFieldIdx].Name
+	}
+	timeField := data.NewField(timeFieldName, nil, make([]time.Time, len(windows)))
+
+	// Windows may have different fields if the schema of incoming frames changed.
+	var fields []*data.Field
+	fieldIndex := map[string]int{}
+	for i, w := range windows {
+		timeField.Set(i, w.start)
+		for _, f := range w.fields {
+			key := f.name + f.labels.String()
+			idx, ok := fieldIndex[key]
+			if !ok {
+				fieldType := f.fieldType.NullableType()
+				if f.aggregate {
+					fieldType = data.FieldTypeNullableFloat64
+				}
+				fields = append(fields, data.NewFieldFromFieldType(fieldType, len(windows)))
+				idx = len(fields) - 1
+				fields[idx].Name = f.name
+				fields[idx].Labels = f.labels
+				fieldIndex[key] = idx
+			}
+			if f.aggregate {
+				if f.count == 0 {
+					continue
+				}
+				value := f.value
+				if p.config.Reducer == AggregateReducerMean {
+					value /= float64(f.count)
+				}
+				fields[idx].Set(i, &value)
+			} else if f.last != nil {
+				fields[idx].SetConcrete(i, f.last)
+			}
+		}
+	}
+	return data.NewFrame(frame.Name, append([]*data.Field{timeField}, fields...)...)
+}
+
+type aggregateWindow struct {
+	start  time.Time
+	fields []*aggregateField
+	index  map[string]int
+}
+
+func newAggregateWindow(start time.Time) *aggregateWindow {
+	return &aggregateWindow{start: start, index: map[string]int{}}
+}
+
+type aggregateField struct {
+	name      string
+	labels    data.Labels
+	fieldType data.FieldType
+	aggregate bool
+	// count and value of aggregated fields, value holds a sum for mean reducer.
+	count int
+	value float64
+	// last value of fields which are not aggregated.
+	last interface{}
+}
+
+type aggregateState struct {
+	mu     sync.Mutex
+	window *aggregateWindow
+
+	// lastUsed and ttl are guarded by the mutex of the storage.
+	lastUsed time.Time
+	ttl      time.Duration
+}
+
+// WindowStorage keeps aggregation windows in memory, so that they survive channel
+// rules rebuilds. Windows which do not receive frames are dropped after some time,
+// and the number of windows is limited. Not usable in HA setup.
+type WindowStorage struct {
+	mu        sync.Mutex
+	states    map[string]*aggregateState
+	lastSweep time.Time
+}
+
+func NewWindowStorage() *WindowStorage {
+	return &WindowStorage{
+		states: map[string]*aggregateState{},
+	}
+}
+
+// get returns the state for the key. The state is dropped if it is not used for ttl.
+func (s *WindowStorage) get(key string, now time.Time, ttl time.Duration) *aggregateState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) >= time.Minute {
+		s.lastSweep = now
+		for k, state := range s.states {
+			if now.Sub(state.lastUsed) > state.ttl {
+				delete(s.states, k)
+			}
+		}
+	}
+	state, ok := s.states[key]
+	if !ok {
+		if len(s.states) >= maxAggregateWindows {
+			s.evictLeastRecentlyUsed()
+		}
+		state = &aggregateState{}
+		s.states[key] = state
+	}
+	state.lastUsed = now
+	state.ttl = ttl
+	return state
+}
+
+func (s *WindowStorage) evictLeastRecentlyUsed() {
+	var (
+		oldestKey string
+		oldest    *aggregateState
+	)
+	for k, state := range s.states {
+		if oldest == nil || state.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = k, state
+		}
+	}
+	delete(s.states, oldestKey)
+}
@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func aggregateTestFrame(ts time.Time, value float64, host string) *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{ts}),
+		data.NewField("value", nil, []float64{value}),
+		data.NewField("host", nil, []string{host}),
+	)
+}
+
+func TestAggregateFrameProcessor(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	vars := Vars{OrgID: 1, Channel: "stream/test/aggregate"}
+
+	testCases := []struct {
+		reducer  string
+		expected float64
+	}{
+		{reducer: AggregateReducerMin, expected: 1},
+		{reducer: AggregateReducerMax, expected: 5},
+		{reducer: AggregateReducerMean, expected: 3},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.reducer, func(t *testing.T) {
+			processor, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+				WindowSeconds: 10,
+				Reducer:       tc.reducer,
+			})
+			require.NoError(t, err)
+
+			for i, v := range []float64{1, 5, 3} {
+				frame, err := processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start.Add(time.Duration(i)*time.Second), v, "a"))
+				require.NoError(t, err)
+				require.Nil(t, frame)
+			}
+
+			frame, err := processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start.Add(12*time.Second), 100, "b"))
+			require.NoError(t, err)
+			require.NotNil(t, frame)
+			require.Equal(t, "test", frame.Name)
+			require.Len(t, frame.Fields, 3)
+			require.Equal(t, start, frame.Fields[0].At(0))
+			require.Equal(t, "value", frame.Fields[1].Name)
+			require.Equal(t, tc.expected, *frame.Fields[1].At(0).(*float64))
+			host := "a"
+			require.Equal(t, &host, frame.Fields[2].At(0))
+		})
+	}
+
+	t.Run("windows are kept in the storage", func(t *testing.T) {
+		storage := NewWindowStorage()
+		config := AggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: AggregateReducerMax}
+
+		processor, err := NewAggregateFrameProcessor(storage, config)
+		require.NoError(t, err)
+		frame, err := processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start, 7, "a"))
+		require.NoError(t, err)
+		require.Nil(t, frame)
+
+		// Processor is re-created when channel rules are rebuilt.
+		processor, err = NewAggregateFrameProcessor(storage, config)
+		require.NoError(t, err)
+		frame, err = processor.ProcessFrame(context.Background(), Vars{OrgID: 2, Channel: vars.Channel}, aggregateTestFrame(start.Add(20*time.Second), 1, "a"))
+		require.NoError(t, err)
+		require.Nil(t, frame, "windows are isolated by organization")
+
+		frame, err = processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start.Add(20*time.Second), 1, "a"))
+		require.NoError(t, err)
+		require.NotNil(t, frame)
+		require.Equal(t, 7.0, *frame.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("only configured fields are aggregated", func(t *testing.T) {
+		processor, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+			WindowSeconds: 10,
+			Reducer:       AggregateReducerMean,
+			FieldNames:    []string{"other"},
+		})
+		require.NoError(t, err)
+
+		frame := data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{start, start.Add(time.Second), start.Add(10 * time.Second)}),
+			data.NewField("value", nil, []float64{1, 5, 3}),
+			data.NewField("other", nil, []*float64{nil, nil, nil}),
+		)
+		frame, err = processor.ProcessFrame(context.Background(), vars, frame)
+		require.NoError(t, err)
+		require.NotNil(t, frame)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, 5.0, *frame.Fields[1].At(0).(*float64), "last value of not aggregated field")
+		require.Nil(t, frame.Fields[2].At(0), "aggregated field without values")
+	})
+
+	t.Run("frames are held back from the next processors until a window is complete", func(t *testing.T) {
+		aggregate, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{
+			WindowSeconds: 10,
+			Reducer:       AggregateReducerMax,
+		})
+		require.NoError(t, err)
+		processor := NewMultipleFrameProcessor(aggregate, NewDropFieldsFrameProcessor(DropFieldsFrameProcessorConfig{FieldNames: []string{"host"}}))
+
+		for i, v := range []float64{1, 5, 3} {
+			frame, err := processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start.Add(time.Duration(i)*time.Second), v, "a"))
+			require.NoError(t, err)
+			require.Nil(t, frame)
+		}
+
+		frame, err := processor.ProcessFrame(context.Background(), vars, aggregateTestFrame(start.Add(12*time.Second), 100, "b"))
+		require.NoError(t, err)
+		require.NotNil(t, frame)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 5.0, *frame.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("unused windows are dropped from the storage", func(t *testing.T) {
+		storage := NewWindowStorage()
+		processor, err := NewAggregateFrameProcessor(storage, AggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: AggregateReducerMax})
+		require.NoError(t, err)
+		now := start
+		processor.now = func() time.Time { return now }
+
+		_, err = processor.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/a"}, aggregateTestFrame(start, 1, "a"))
+		require.NoError(t, err)
+		now = now.Add(aggregateWindowIdleTimeout + time.Minute)
+		_, err = processor.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/b"}, aggregateTestFrame(start, 1, "a"))
+		require.NoError(t, err)
+		require.Len(t, storage.states, 1)
+
+		for i := 0; i < maxAggregateWindows+10; i++ {
+			storage.get(fmt.Sprint(i), now, time.Hour)
+		}
+		require.Len(t, storage.states, maxAggregateWindows)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{Reducer: AggregateReducerMin})
+		require.Error(t, err)
+		_, err = NewAggregateFrameProcessor(NewWindowStorage(), AggregateFrameProcessorConfig{WindowSeconds: 1, Reducer: "median"})
+		require.EqualError(t, err, "unsupported reducer: median")
+	})
+}
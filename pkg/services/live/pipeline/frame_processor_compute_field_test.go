@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFieldFrameProcessor(t *testing.T) {
+	processor, err := NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+		FieldName:  "temperature_f",
+		Expression: "${temperature} * 1.8 + 32",
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test",
+		data.NewField("temperature", nil, []*float64{float64Ptr(100), nil}),
+	)
+	frame, err = processor.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, frame.Fields, 2)
+	require.Equal(t, "temperature_f", frame.Fields[1].Name)
+	require.Equal(t, 212.0, *frame.Fields[1].At(0).(*float64))
+	require.Nil(t, frame.Fields[1].At(1))
+
+	t.Run("existing field is replaced", func(t *testing.T) {
+		processor, err := NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{
+			FieldName:  "value",
+			Expression: "abs($value)",
+		})
+		require.NoError(t, err)
+		frame := data.NewFrame("test", data.NewField("value", nil, []int64{-2}))
+		frame, err = processor.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, 2.0, *frame.Fields[0].At(0).(*float64))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{FieldName: "value", Expression: "$value *"})
+		require.Error(t, err)
+		_, err = NewComputeFieldFrameProcessor(ComputeFieldFrameProcessorConfig{Expression: "$value"})
+		require.EqualError(t, err, "field name required")
+	})
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
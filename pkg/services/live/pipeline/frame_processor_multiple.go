@@ -26,6 +26,10 @@ func (p *MultipleFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, fr
 			logger.Error("Error processing frame", "error", err)
 			return nil, err
 		}
+		if frame == nil {
+			// A processor may hold the frame back, e.g. aggregate until its window is complete.
+			return nil, nil
+		}
 	}
 	return frame, nil
 }
@@ -66,6 +66,14 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePromText,
+		Description: "accept Prometheus text exposition format",
+	},
+	{
+		Type:        ConverterTypeOtlp,
+		Description: "accept OTLP/HTTP protobuf metrics",
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -79,6 +87,36 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"temp": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeAddLabels,
+		Description: "attach static labels to fields",
+		Example: AddLabelsFrameProcessorConfig{
+			Labels: map[string]string{"site": "factory-1"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeComputeField,
+		Description: "add a field computed from other fields with a math expression",
+		Example: ComputeFieldFrameProcessorConfig{
+			FieldName:  "temperature_f",
+			Expression: "${temperature} * 1.8 + 32",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeAggregate,
+		Description: "aggregate numeric fields over time windows (min, max or mean)",
+		Example: AggregateFrameProcessorConfig{
+			WindowSeconds: 10,
+			Reducer:       AggregateReducerMean,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
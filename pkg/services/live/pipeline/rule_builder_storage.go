@@ -14,6 +14,7 @@ type StorageRuleBuilder struct {
 	Node                 *centrifuge.Node
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
+	WindowStorage        *WindowStorage
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
@@ -69,6 +70,16 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePromText:
+		if config.PromTextConverterConfig == nil {
+			config.PromTextConverterConfig = &PromTextConverterConfig{}
+		}
+		return NewPromTextConverter(*config.PromTextConverterConfig), nil
+	case ConverterTypeOtlp:
+		if config.OtlpConverterConfig == nil {
+			config.OtlpConverterConfig = &OtlpConverterConfig{}
+		}
+		return NewOtlpConverter(*config.OtlpConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
@@ -104,6 +115,30 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			processors = append(processors, proc)
 		}
 		return NewMultipleFrameProcessor(processors...), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeAddLabels:
+		if config.AddLabelsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewAddLabelsFrameProcessor(*config.AddLabelsProcessorConfig), nil
+	case FrameProcessorTypeComputeField:
+		if config.ComputeFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewComputeFieldFrameProcessor(*config.ComputeFieldProcessorConfig)
+	case FrameProcessorTypeAggregate:
+		if config.AggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		windowStorage := f.WindowStorage
+		if windowStorage == nil {
+			windowStorage = NewWindowStorage()
+		}
+		return NewAggregateFrameProcessor(windowStorage, *config.AggregateProcessorConfig)
 	default:
 		return nil, fmt.Errorf("unknown processor type: %s", config.Type)
 	}
@@ -0,0 +1,336 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules and write configs in the Grafana database,
+// so that they are shared by all instances of Grafana in HA setups.
+type SQLStorage struct {
+	store          db.DB
+	secretsService secrets.Service
+}
+
+func NewSQLStorage(store db.DB, secretsService secrets.Service) *SQLStorage {
+	return &SQLStorage{store: store, secretsService: secretsService}
+}
+
+// liveChannelRule is a row of the live_channel_rule table.
+type liveChannelRule struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	OrgID    int64     `xorm:"org_id"`
+	Pattern  string    `xorm:"pattern"`
+	Settings string    `xorm:"settings"`
+	Created  time.Time `xorm:"created"`
+	Updated  time.Time `xorm:"updated"`
+}
+
+func (liveChannelRule) TableName() string {
+	return "live_channel_rule"
+}
+
+func (r liveChannelRule) toChannelRule() (ChannelRule, error) {
+	rule := ChannelRule{OrgId: r.OrgID, Pattern: r.Pattern}
+	if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+		return ChannelRule{}, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", r.Pattern, err)
+	}
+	return rule, nil
+}
+
+// liveWriteConfig is a row of the live_write_config table.
+// The secure settings are stored in the secure_json_data column, like for data sources,
+// so that they are re-encrypted by the secrets migrator.
+type liveWriteConfig struct {
+	ID             int64             `xorm:"pk autoincr 'id'"`
+	OrgID          int64             `xorm:"org_id"`
+	UID            string            `xorm:"uid"`
+	Settings       string            `xorm:"settings"`
+	SecureJsonData map[string][]byte `xorm:"secure_json_data"`
+	Created        time.Time         `xorm:"created"`
+	Updated        time.Time         `xorm:"updated"`
+}
+
+func (liveWriteConfig) TableName() string {
+	return "live_write_config"
+}
+
+func (c liveWriteConfig) toWriteConfig() (WriteConfig, error) {
+	config := WriteConfig{OrgId: c.OrgID, UID: c.UID, SecureSettings: c.SecureJsonData}
+	if err := json.Unmarshal([]byte(c.Settings), &config.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", c.UID, err)
+	}
+	return config, nil
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var rows []liveWriteConfig
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	result := make([]WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		config, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, config)
+	}
+	return result, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var row liveWriteConfig
+	var exists bool
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !exists {
+		return WriteConfig{}, false, nil
+	}
+	config, err := row.toWriteConfig()
+	if err != nil {
+		return WriteConfig{}, false, err
+	}
+	return config, true, nil
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	backend, row, err := s.newWriteConfigRow(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, backend.UID).Exist(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("backend already exists in org: %s", backend.UID)
+		}
+		_, err = sess.Insert(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return backend, nil
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	backend, row, err := s.newWriteConfigRow(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveWriteConfig
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, backend.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			_, err = sess.Insert(&row)
+			return err
+		}
+		row.ID = existing.ID
+		row.Created = existing.Created
+		_, err = sess.ID(existing.ID).AllCols().Update(&row)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return backend, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("write config not found")
+		}
+		return nil
+	})
+}
+
+// newWriteConfigRow encrypts the secure settings and validates the write config.
+// The encryption is done outside of the transaction because the secrets service might write data keys to the database.
+func (s *SQLStorage) newWriteConfigRow(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (WriteConfig, liveWriteConfig, error) {
+	encrypted, err := s.secretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	backend := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encrypted,
+	}
+	ok, reason := backend.Valid()
+	if !ok {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+	settingsJSON, err := json.Marshal(backend.Settings)
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("can't marshal write config settings: %w", err)
+	}
+	now := time.Now()
+	return backend, liveWriteConfig{
+		OrgID:          orgID,
+		UID:            uid,
+		Settings:       string(settingsJSON),
+		SecureJsonData: encrypted,
+		Created:        now,
+		Updated:        now,
+	}, nil
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rules []ChannelRule
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		rules, err = listChannelRules(sess, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	row, err := newChannelRuleRow(rule)
+	if err != nil {
+		return rule, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		for _, existingRule := range existing {
+			if existingRule.Pattern == rule.Pattern {
+				return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+			}
+		}
+		if ok, reason := checkRulesValid(orgID, append(existing, rule)); !ok {
+			return errors.New(reason)
+		}
+		_, err = sess.Insert(&row)
+		return err
+	})
+	return rule, err
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	row, err := newChannelRuleRow(rule)
+	if err != nil {
+		return rule, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveChannelRule
+		exists, err := sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if exists {
+			row.ID = existing.ID
+			row.Created = existing.Created
+			_, err = sess.ID(existing.ID).AllCols().Update(&row)
+			return err
+		}
+		rules, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		if ok, reason := checkRulesValid(orgID, append(rules, rule)); !ok {
+			return errors.New(reason)
+		}
+		_, err = sess.Insert(&row)
+		return err
+	})
+	return rule, err
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&liveChannelRule{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errors.New("rule not found")
+		}
+		return nil
+	})
+}
+
+func listChannelRules(sess *db.Session, orgID int64) ([]ChannelRule, error) {
+	var rows []liveChannelRule
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows); err != nil {
+		return nil, err
+	}
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := row.toChannelRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func newChannelRuleRow(rule ChannelRule) (liveChannelRule, error) {
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return liveChannelRule{}, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+	now := time.Now()
+	return liveChannelRule{
+		OrgID:    rule.OrgId,
+		Pattern:  rule.Pattern,
+		Settings: string(settings),
+		Created:  now,
+		Updated:  now,
+	}, nil
+}
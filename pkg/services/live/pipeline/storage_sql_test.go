@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestIntegrationSQLStorage_ChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := NewSQLStorage(db.InitTestDB(t), fakes.NewFakeSecretsService())
+
+	settings := ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeJsonAuto, AutoJsonConverterConfig: &AutoJsonConverterConfig{}},
+	}
+
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/json", Settings: settings})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.OrgId)
+
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/json", Settings: settings})
+	require.EqualError(t, err, "pattern already exists in org: stream/test/json")
+
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "invalid pattern"})
+	require.Error(t, err)
+
+	t.Run("rules are isolated by organization", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/test/json", Settings: settings})
+		require.NoError(t, err)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []ChannelRule{rule}, rules)
+
+		require.NoError(t, storage.DeleteChannelRule(ctx, 2, ChannelRuleDeleteCmd{Pattern: "stream/test/json"}))
+		rules, err = storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+	})
+
+	t.Run("update replaces the settings or creates the rule", func(t *testing.T) {
+		updated := ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: ConverterTypeInfluxAuto, AutoInfluxConverterConfig: &AutoInfluxConverterConfig{FrameFormat: "labels_column"}},
+		}
+		_, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/json", Settings: updated})
+		require.NoError(t, err)
+		_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/influx", Settings: updated})
+		require.NoError(t, err)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []ChannelRule{
+			{OrgId: 1, Pattern: "stream/test/influx", Settings: updated},
+			{OrgId: 1, Pattern: "stream/test/json", Settings: updated},
+		}, rules)
+	})
+
+	t.Run("delete fails if the rule does not exist", func(t *testing.T) {
+		require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/influx"}))
+		require.EqualError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/influx"}), "rule not found")
+	})
+}
+
+func TestIntegrationSQLStorage_WriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := NewSQLStorage(db.InitTestDB(t), fakes.NewFakeSecretsService())
+
+	settings := WriteSettings{Endpoint: "http://localhost:9090/api/v1/write", BasicAuth: &BasicAuth{User: "admin"}}
+
+	created, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		Settings:       settings,
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	require.Equal(t, []byte("secret"), created.SecureSettings["basicAuthPassword"])
+
+	_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: created.UID, Settings: settings})
+	require.EqualError(t, err, "backend already exists in org: "+created.UID)
+
+	_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "no-endpoint"})
+	require.EqualError(t, err, "invalid write config: endpoint required")
+
+	t.Run("write configs are isolated by organization", func(t *testing.T) {
+		_, ok, err := storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: created.UID})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		config, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: created.UID})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, created, config)
+
+		configs, err := storage.ListWriteConfigs(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, configs)
+	})
+
+	t.Run("update replaces the settings and the secure settings", func(t *testing.T) {
+		updatedSettings := WriteSettings{Endpoint: "http://remote:9090/api/v1/write"}
+		_, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{UID: created.UID, Settings: updatedSettings})
+		require.NoError(t, err)
+
+		configs, err := storage.ListWriteConfigs(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		require.Equal(t, updatedSettings, configs[0].Settings)
+		require.Empty(t, configs[0].SecureSettings)
+	})
+
+	t.Run("delete fails if the write config does not exist", func(t *testing.T) {
+		require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: created.UID}))
+		require.EqualError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: created.UID}), "write config not found")
+	})
+}
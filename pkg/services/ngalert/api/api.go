@@ -83,6 +83,7 @@ type API struct {
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
 	Historian            Historian
+	StateArchive         StateArchiver
 
 	AppUrl *url.URL
 }
@@ -116,6 +117,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			QuotaService:       api.QuotaService,
 			store:              api.RuleStore,
 			provenanceStore:    api.ProvenanceStore,
+			amConfigStore:      api.AlertingStore,
+			alertmanagers:      api.MultiOrgAlertmanager,
 			xactManager:        api.TransactionManager,
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
@@ -132,6 +135,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			cfg:             &api.Cfg.UnifiedAlerting,
 			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory),
 			featureManager:  api.FeatureManager,
+			mam:             api.MultiOrgAlertmanager,
+			stateManager:    api.StateManager,
+			ruleStore:       api.RuleStore,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -139,11 +145,16 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			store:                api.AdminConfigStore,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
+			stateArchive:         api.StateArchive,
+			alertmanagers:        api.MultiOrgAlertmanager,
+			stateManager:         api.StateManager,
+			ruleStore:            api.RuleStore,
 		},
 	), m)
 
 	api.RegisterProvisioningApiEndpoints(NewProvisioningApi(&ProvisioningSrv{
 		log:                 logger,
+		ac:                  api.AccessControl,
 		policies:            api.Policies,
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
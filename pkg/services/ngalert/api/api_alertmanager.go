@@ -389,7 +389,12 @@ func statusForTestReceivers(v []notifier.TestReceiverResult) int {
 }
 
 func (srv AlertmanagerSrv) AlertmanagerFor(orgID int64) (Alertmanager, *response.NormalResponse) {
-	am, err := srv.mam.AlertmanagerFor(orgID)
+	return alertmanagerFor(srv.mam, srv.log, orgID)
+}
+
+// alertmanagerFor returns the Alertmanager of the org, or the response to return if it is not available.
+func alertmanagerFor(mam *notifier.MultiOrgAlertmanager, logger log.Logger, orgID int64) (Alertmanager, *response.NormalResponse) {
+	am, err := mam.AlertmanagerFor(orgID)
 	if err == nil {
 		return am, nil
 	}
@@ -401,6 +406,6 @@ func (srv AlertmanagerSrv) AlertmanagerFor(orgID int64) (Alertmanager, *response
 		return am, response.Error(http.StatusConflict, err.Error(), err)
 	}
 
-	srv.log.Error("unable to obtain the org's Alertmanager", "error", err)
+	logger.Error("unable to obtain the org's Alertmanager", "error", err)
 	return nil, response.Error(http.StatusInternalServerError, "unable to obtain org's Alertmanager", err)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -22,6 +23,7 @@ const disableProvenanceHeaderName = "X-Disable-Provenance"
 
 type ProvisioningSrv struct {
 	log                 log.Logger
+	ac                  accesscontrol.AccessControl
 	policies            NotificationPolicyService
 	contactPointService ContactPointService
 	templates           TemplateService
@@ -400,6 +402,125 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 	return exportResponse(c, e)
 }
 
+// RouteGetContactPointsExport retrieves all contact points in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqContext) response.Response {
+	decrypt := c.QueryBoolWithDefault("decrypt", false)
+	if decrypt && !srv.canReadSecrets(c) {
+		return ErrResp(http.StatusForbidden, errors.New("user is not authorized to read the secure settings of contact points"), "")
+	}
+
+	contactPoints, err := srv.exportContactPoints(c, c.Query("name"), decrypt)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get contact points")
+	}
+
+	return exportResponse(c, file.AlertingFileExport{APIVersion: 1, ContactPoints: contactPoints})
+}
+
+// RouteGetPolicyTreeExport retrieves the notification policy tree in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *contextmodel.ReqContext) response.Response {
+	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgID)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification policy tree")
+	}
+
+	return exportResponse(c, file.AlertingFileExport{
+		APIVersion: 1,
+		Policies:   []file.NotificationPolicyExport{NotificationPolicyExportFromRoute(c.OrgID, policies)},
+	})
+}
+
+// RouteGetMuteTimingsExport retrieves all mute timings in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get mute timings")
+	}
+
+	return exportResponse(c, file.AlertingFileExport{
+		APIVersion: 1,
+		MuteTimes:  MuteTimingsExportFromMuteTimeIntervals(c.OrgID, timings),
+	})
+}
+
+// RouteGetTemplatesExport retrieves all notification templates in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetTemplatesExport(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get templates")
+	}
+
+	return exportResponse(c, file.AlertingFileExport{
+		APIVersion: 1,
+		Templates:  TemplatesExportFromTemplates(c.OrgID, templates),
+	})
+}
+
+// RouteGetAlertingConfigExport retrieves alert rules, contact points, notification policies, mute timings and templates
+// of the organization in a single file compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetAlertingConfigExport(c *contextmodel.ReqContext) response.Response {
+	decrypt := c.QueryBoolWithDefault("decrypt", false)
+	if decrypt && !srv.canReadSecrets(c) {
+		return ErrResp(http.StatusForbidden, errors.New("user is not authorized to read the secure settings of contact points"), "")
+	}
+
+	groupsWithTitle, err := srv.alertRules.GetAlertGroupsWithFolderTitle(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	e, err := file.NewAlertingFileExport(groupsWithTitle)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+
+	e.ContactPoints, err = srv.exportContactPoints(c, "", decrypt)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get contact points")
+	}
+
+	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgID)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification policy tree")
+	}
+	if err == nil {
+		e.Policies = []file.NotificationPolicyExport{NotificationPolicyExportFromRoute(c.OrgID, policies)}
+	}
+
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get mute timings")
+	}
+	e.MuteTimes = MuteTimingsExportFromMuteTimeIntervals(c.OrgID, timings)
+
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get templates")
+	}
+	e.Templates = TemplatesExportFromTemplates(c.OrgID, templates)
+
+	return exportResponse(c, e)
+}
+
+func (srv *ProvisioningSrv) exportContactPoints(c *contextmodel.ReqContext, name string, decrypt bool) ([]file.ContactPointExport, error) {
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), provisioning.ContactPointQuery{
+		Name:    name,
+		OrgID:   c.OrgID,
+		Decrypt: decrypt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ContactPointsExportFromEmbeddedContactPoints(c.OrgID, cps)
+}
+
+// canReadSecrets returns true if the user is allowed to export the secure settings of contact points in plain text.
+func (srv *ProvisioningSrv) canReadSecrets(c *contextmodel.ReqContext) bool {
+	return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqOrgAdmin, accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets))
+}
+
 func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
 	ag.FolderUID = folderUID
 	ag.Title = group
@@ -427,7 +548,7 @@ func determineProvenance(ctx *contextmodel.ReqContext) alerting_models.Provenanc
 	return alerting_models.ProvenanceAPI
 }
 
-func exportResponse(c *contextmodel.ReqContext, body any) response.Response {
+func exportResponse(c *contextmodel.ReqContext, body file.AlertingFileExport) response.Response {
 	var format = "yaml"
 
 	acceptHeader := c.Req.Header.Get("Accept")
@@ -439,12 +560,19 @@ func exportResponse(c *contextmodel.ReqContext, body any) response.Response {
 		format = "json"
 	}
 
+	if strings.Contains(acceptHeader, "hcl") {
+		format = "hcl"
+	}
+
 	queryFormat := c.Query("format")
-	if queryFormat == "yaml" || queryFormat == "json" {
+	if queryFormat == "yaml" || queryFormat == "json" || queryFormat == "hcl" {
 		format = queryFormat
 	}
 
 	download := c.QueryBoolWithDefault("download", false)
+	if format == "hcl" {
+		return hclExportResponse(body, download)
+	}
 	if download {
 		r := response.JSONDownload
 		if format == "yaml" {
@@ -459,3 +587,17 @@ func exportResponse(c *contextmodel.ReqContext, body any) response.Response {
 	}
 	return r(http.StatusOK, body)
 }
+
+// hclExportResponse responds with the export as Terraform resources.
+func hclExportResponse(body file.AlertingFileExport, download bool) response.Response {
+	b, err := AlertingFileExportToHCL(body)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to convert export to HCL")
+	}
+	r := response.Respond(http.StatusOK, b).
+		SetHeader("Content-Type", "text/hcl")
+	if download {
+		r = r.SetHeader("Content-Disposition", `attachment;filename="export.tf"`)
+	}
+	return r
+}
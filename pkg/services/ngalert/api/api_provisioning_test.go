@@ -11,20 +11,25 @@ import (
 	"time"
 
 	prometheus "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -438,6 +443,33 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, "text/yaml", rc.Context.Resp.Header().Get("Content-Type"))
 			})
 
+			t.Run("query format contains hcl, GET returns text hcl", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("format", "hcl")
+
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, "text/hcl", rc.Context.Resp.Header().Get("Content-Type"))
+			})
+
+			t.Run("accept header contains hcl, GET returns text hcl", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Header.Add("Accept", "text/hcl")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, "text/hcl", rc.Context.Resp.Header().Get("Content-Type"))
+			})
+
 			t.Run("accept header contains json, GET returns json", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rc := createTestRequestCtx()
@@ -502,6 +534,20 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, "", rc.Context.Resp.Header().Get("Content-Disposition"))
 			})
 
+			t.Run("query param download=true and format hcl, GET returns terraform file attachment", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("download", "true")
+				rc.Context.Req.Form.Set("format", "hcl")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, `attachment;filename="export.tf"`, rc.Context.Resp.Header().Get("Content-Disposition"))
+			})
+
 			t.Run("yaml body content is the default", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rc := createTestRequestCtx()
@@ -550,6 +596,72 @@ func TestProvisioningApi(t *testing.T) {
 
 				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
 
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := `resource "grafana_rule_group" "rule_group_folder_title_my_cool_group" {
+  name             = "my-cool-group"
+  folder_uid       = "folder-uid"
+  interval_seconds = 60
+
+  rule {
+    name           = "rule1"
+    condition      = "A"
+    no_data_state  = "OK"
+    exec_err_state = "OK"
+    for            = "0s"
+    is_paused      = false
+
+    data {
+      ref_id         = "A"
+      datasource_uid = ""
+      model          = jsonencode({
+        conditions    = [
+          {
+            evaluator = {
+              params = [3]
+              type   = "gt"
+            }
+            operator  = {
+              type = "and"
+            }
+            query     = {
+              params = ["A"]
+            }
+            reducer   = {
+              type = "last"
+            }
+            type      = "query"
+          },
+        ]
+        datasource    = {
+          type = "__expr__"
+          uid  = "__expr__"
+        }
+        expression    = "1==0"
+        intervalMs    = 1000
+        maxDataPoints = 43200
+        refId         = "A"
+        type          = "math"
+      })
+
+      relative_time_range {
+        from = 0
+        to   = 0
+      }
+    }
+  }
+}
+`
+
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
 				require.Equal(t, 200, response.Status())
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
@@ -802,6 +914,254 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
 		})
+
+		t.Run("contact points", func(t *testing.T) {
+			t.Run("json body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				expectedResponse := `{"apiVersion":1,"contactPoints":[{"orgId":1,"name":"email receiver","receivers":[{"uid":"email-uid","type":"email","settings":{"addresses":"\u003cexample@email.com\u003e"},"disableResolveMessage":false}]}]}`
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("yaml body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/yaml")
+				expectedResponse := "apiVersion: 1\ncontactPoints:\n    - orgId: 1\n      name: email receiver\n      receivers:\n        - uid: email-uid\n          type: email\n          settings:\n            addresses: <example@email.com>\n          disableResolveMessage: false\n"
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := `resource "grafana_contact_point" "contact_point_email_receiver" {
+  name = "email receiver"
+
+  email {
+    addresses               = ["<example@email.com>"]
+    uid                     = "email-uid"
+    disable_resolve_message = false
+  }
+}
+`
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("filtered by name that does not exist, GET returns empty export", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				rc.Context.Req.Form.Set("name", "does not exist")
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, `{"apiVersion":1}`, string(response.Body()))
+			})
+
+			t.Run("query param decrypt=true without permission, GET returns 403", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("decrypt", "true")
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 403, response.Status())
+			})
+
+			t.Run("query param decrypt=true with permission, GET returns 200", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.ac = acMock.New().WithPermissions([]accesscontrol.Permission{
+					{Action: accesscontrol.ActionAlertingProvisioningReadSecrets},
+				})
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("decrypt", "true")
+
+				response := sut.RouteGetContactPointsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+			})
+		})
+
+		t.Run("notification policies", func(t *testing.T) {
+			t.Run("org has no AM config, GET returns 404", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.SignedInUser.OrgID = 2
+
+				response := sut.RouteGetPolicyTreeExport(&rc)
+
+				require.Equal(t, 404, response.Status())
+			})
+
+			t.Run("json body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyServiceWithNestedTree(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				expectedResponse := `{"apiVersion":1,"policies":[{"orgId":1,"receiver":"default-receiver","group_by":["g1","g2"],"routes":[{"receiver":"nested-receiver","group_by":["g3"],"object_matchers":[["foo","=","bar"],["team","=~","a.*"]],"mute_time_intervals":["interval"],"continue":true,"group_wait":"5m","repeat_interval":"1h"}],"group_wait":"30s"}]}`
+
+				response := sut.RouteGetPolicyTreeExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("yaml body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyServiceWithNestedTree(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/yaml")
+				expectedResponse := "apiVersion: 1\npolicies:\n    - orgId: 1\n      receiver: default-receiver\n      group_by:\n        - g1\n        - g2\n      routes:\n        - receiver: nested-receiver\n          group_by:\n            - g3\n          object_matchers: [[foo, =, bar], [team, =~, a.*]]\n          mute_time_intervals:\n            - interval\n          continue: true\n          group_wait: 5m\n          repeat_interval: 1h\n      group_wait: 30s\n"
+
+				response := sut.RouteGetPolicyTreeExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyServiceWithNestedTree(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := `resource "grafana_notification_policy" "notification_policy" {
+  contact_point = "default-receiver"
+  group_by      = ["g1", "g2"]
+  group_wait    = "30s"
+
+  policy {
+    contact_point   = "nested-receiver"
+    group_by        = ["g3"]
+    continue        = true
+    mute_timings    = ["interval"]
+    group_wait      = "5m"
+    repeat_interval = "1h"
+
+    matcher {
+      label = "foo"
+      match = "="
+      value = "bar"
+    }
+
+    matcher {
+      label = "team"
+      match = "=~"
+      value = "a.*"
+    }
+  }
+}
+`
+
+				response := sut.RouteGetPolicyTreeExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+		})
+
+		t.Run("mute timings", func(t *testing.T) {
+			t.Run("json body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				expectedResponse := `{"apiVersion":1,"muteTimes":[{"orgId":1,"name":"interval","time_intervals":[]}]}`
+
+				response := sut.RouteGetMuteTimingsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+		})
+
+		t.Run("templates", func(t *testing.T) {
+			t.Run("json body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				expectedResponse := `{"apiVersion":1,"templates":[{"orgId":1,"name":"a","template":"template"}]}`
+
+				response := sut.RouteGetTemplatesExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+		})
+
+		t.Run("entire alerting config", func(t *testing.T) {
+			t.Run("query param decrypt=true without permission, GET returns 403", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("decrypt", "true")
+
+				response := sut.RouteGetAlertingConfigExport(&rc)
+
+				require.Equal(t, 403, response.Status())
+			})
+
+			t.Run("yaml body can be loaded by file provisioning", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyServiceWithNestedTree(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRuleWithFolderAndGroup("rule1", 1, "folder-uid", "groupa"))
+
+				rc.Context.Req.Header.Add("Accept", "application/yaml")
+				response := sut.RouteGetAlertingConfigExport(&rc)
+				require.Equal(t, 200, response.Status())
+
+				var fileV1 alerting.AlertingFileV1
+				require.NoError(t, yaml.Unmarshal(response.Body(), &fileV1))
+				f, err := fileV1.MapToModel()
+				require.NoError(t, err)
+
+				require.Len(t, f.Groups, 1)
+				require.Equal(t, "groupa", f.Groups[0].Title)
+				require.Equal(t, "rule1", f.Groups[0].Rules[0].UID)
+
+				require.Len(t, f.ContactPoints, 1)
+				require.Len(t, f.ContactPoints[0].ContactPoints, 1)
+				require.Equal(t, "email-uid", f.ContactPoints[0].ContactPoints[0].UID)
+				require.Equal(t, "email receiver", f.ContactPoints[0].ContactPoints[0].Name)
+
+				require.Len(t, f.Policies, 1)
+				tree, err := sut.policies.GetPolicyTree(context.Background(), 1)
+				require.NoError(t, err)
+				tree.Provenance = ""
+				require.Equal(t, tree, f.Policies[0].Policy)
+
+				require.Len(t, f.MuteTimes, 1)
+				require.Equal(t, "interval", f.MuteTimes[0].MuteTime.Name)
+
+				require.Len(t, f.Templates, 1)
+				require.Equal(t, "a", f.Templates[0].Data.Name)
+				require.Equal(t, "template", f.Templates[0].Data.Template)
+			})
+		})
 	})
 }
 
@@ -840,6 +1200,7 @@ func createTestEnv(t *testing.T) testEnvironment {
 	prov := &provisioning.MockProvisioningStore{}
 	prov.EXPECT().SaveSucceeds()
 	prov.EXPECT().GetReturns(models.ProvenanceNone)
+	prov.EXPECT().GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(map[string]models.Provenance{}, nil)
 
 	dashboardService := dashboards.NewFakeDashboardService(t)
 	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(&dashboards.Dashboard{
@@ -879,11 +1240,12 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 
 	return ProvisioningSrv{
 		log:                 env.log,
+		ac:                  acMock.New(),
 		policies:            newFakeNotificationPolicyService(),
 		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, env.log),
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
+		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.configs, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
 	}
 }
 
@@ -916,6 +1278,35 @@ func newFakeNotificationPolicyService() *fakeNotificationPolicyService {
 	}
 }
 
+func createFakeNotificationPolicyServiceWithNestedTree(t *testing.T) *fakeNotificationPolicyService {
+	t.Helper()
+
+	groupWait := model.Duration(30 * time.Second)
+	nestedGroupWait := model.Duration(5 * time.Minute)
+	repeatInterval := model.Duration(time.Hour)
+	matcher := func(typ labels.MatchType, name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(typ, name, value)
+		require.NoError(t, err)
+		return m
+	}
+	svc := newFakeNotificationPolicyService()
+	svc.tree = definitions.Route{
+		Receiver:   "default-receiver",
+		GroupByStr: []string{"g1", "g2"},
+		GroupWait:  &groupWait,
+		Routes: []*definitions.Route{{
+			Receiver:          "nested-receiver",
+			GroupByStr:        []string{"g3"},
+			ObjectMatchers:    definitions.ObjectMatchers{matcher(labels.MatchEqual, "foo", "bar"), matcher(labels.MatchRegexp, "team", "a.*")},
+			MuteTimeIntervals: []string{"interval"},
+			Continue:          true,
+			GroupWait:         &nestedGroupWait,
+			RepeatInterval:    &repeatInterval,
+		}},
+	}
+	return svc
+}
+
 func (f *fakeNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	if orgID != 1 {
 		return definitions.Route{}, store.ErrNoAlertmanagerConfiguration
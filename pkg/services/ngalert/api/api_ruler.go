@@ -31,9 +31,16 @@ type ConditionValidator interface {
 	Validate(ctx eval.EvaluationContext, condition ngmodels.Condition) error
 }
 
+// NotificationSettingsSyncer updates the routes generated from the notification settings of alert rules.
+type NotificationSettingsSyncer interface {
+	SyncNotificationSettings(ctx context.Context, orgID int64) error
+}
+
 type RulerSrv struct {
 	xactManager        provisioning.TransactionManager
 	provenanceStore    provisioning.ProvisioningStore
+	amConfigStore      provisioning.AMConfigReader
+	alertmanagers      NotificationSettingsSyncer
 	store              RuleStore
 	QuotaService       quota.Service
 	log                log.Logger
@@ -346,6 +353,10 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return err
 		}
 
+		if err := provisioning.ValidateNotificationSettings(tranCtx, srv.amConfigStore, c.OrgID, changedNotificationSettings(groupChanges)...); err != nil {
+			return err
+		}
+
 		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
 		logger.Debug("updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
@@ -417,6 +428,13 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
 	}
 
+	// Otherwise, the routes of the notification settings are updated by the next periodic sync of the Alertmanager.
+	if srv.alertmanagers != nil && notificationSettingsChanged(finalChanges) {
+		if err := srv.alertmanagers.SyncNotificationSettings(c.Req.Context(), c.OrgID); err != nil {
+			srv.log.Error("failed to apply the notification settings of the alert rules", "org_id", c.OrgID, "error", err)
+		}
+	}
+
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
 }
 
@@ -437,6 +455,42 @@ func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, name
 	}
 }
 
+// changedNotificationSettings returns the notification settings of the new rules and of the updated rules whose settings are changed.
+// Settings of other rules are not returned, so the rules can be updated even if the contact point they use was deleted meanwhile.
+func changedNotificationSettings(changes *store.GroupDelta) []ngmodels.NotificationSettings {
+	var result []ngmodels.NotificationSettings
+	for _, rule := range changes.New {
+		result = append(result, rule.NotificationSettings...)
+	}
+	for _, update := range changes.Update {
+		if len(update.Diff.GetDiffsForField("NotificationSettings")) > 0 {
+			result = append(result, update.New.NotificationSettings...)
+		}
+	}
+	return result
+}
+
+// notificationSettingsChanged returns whether rules with notification settings are added or deleted, or the
+// notification settings of updated rules are changed.
+func notificationSettingsChanged(changes *store.GroupDelta) bool {
+	for _, rule := range changes.New {
+		if len(rule.NotificationSettings) > 0 {
+			return true
+		}
+	}
+	for _, rule := range changes.Delete {
+		if len(rule.NotificationSettings) > 0 {
+			return true
+		}
+	}
+	for _, update := range changes.Update {
+		if len(update.Diff.GetDiffsForField("NotificationSettings")) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provenanceRecords map[string]ngmodels.Provenance) apimodels.GettableExtendedRuleNode {
 	provenance := ngmodels.ProvenanceNone
 	if prov, exists := provenanceRecords[r.ResourceID()]; exists {
@@ -444,22 +498,24 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 	}
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
-			ID:              r.ID,
-			OrgID:           r.OrgID,
-			Title:           r.Title,
-			Condition:       r.Condition,
-			Data:            r.Data,
-			Updated:         r.Updated,
-			IntervalSeconds: r.IntervalSeconds,
-			Version:         r.Version,
-			UID:             r.UID,
-			NamespaceUID:    r.NamespaceUID,
-			NamespaceID:     namespaceID,
-			RuleGroup:       r.RuleGroup,
-			NoDataState:     apimodels.NoDataState(r.NoDataState),
-			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
-			Provenance:      apimodels.Provenance(provenance),
-			IsPaused:        r.IsPaused,
+			ID:                   r.ID,
+			OrgID:                r.OrgID,
+			Title:                r.Title,
+			Condition:            r.Condition,
+			Data:                 r.Data,
+			Updated:              r.Updated,
+			IntervalSeconds:      r.IntervalSeconds,
+			Version:              r.Version,
+			UID:                  r.UID,
+			NamespaceUID:         r.NamespaceUID,
+			NamespaceID:          namespaceID,
+			RuleGroup:            r.RuleGroup,
+			NoDataState:          apimodels.NoDataState(r.NoDataState),
+			ExecErrState:         apimodels.ExecutionErrorState(r.ExecErrState),
+			Provenance:           apimodels.Provenance(provenance),
+			IsPaused:             r.IsPaused,
+			NotificationSettings: NotificationSettingsToAPI(r.NotificationSettings),
+			DependsOn:            r.DependsOn,
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -468,6 +524,10 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
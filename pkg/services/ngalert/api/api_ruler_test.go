@@ -589,6 +589,48 @@ func TestVerifyProvisionedRulesNotAffected(t *testing.T) {
 	})
 }
 
+func TestNotificationSettingsChanged(t *testing.T) {
+	withSettings := models.AlertRuleGen(models.WithNotificationSettings(models.NotificationSettings{Receiver: "receiver"}))()
+	withoutSettings := models.AlertRuleGen()()
+	withoutSettings.NotificationSettings = nil
+
+	changedSettings := models.CopyRule(withSettings)
+	changedSettings.NotificationSettings = []models.NotificationSettings{{Receiver: "other"}}
+	changedTitle := models.CopyRule(withSettings)
+	changedTitle.Title = withSettings.Title + "-changed"
+
+	testCases := map[string]struct {
+		delta    *store.GroupDelta
+		expected bool
+	}{
+		"new rule without settings": {
+			delta:    &store.GroupDelta{New: []*models.AlertRule{withoutSettings}},
+			expected: false,
+		},
+		"new rule with settings": {
+			delta:    &store.GroupDelta{New: []*models.AlertRule{withSettings}},
+			expected: true,
+		},
+		"deleted rule with settings": {
+			delta:    &store.GroupDelta{Delete: []*models.AlertRule{withSettings}},
+			expected: true,
+		},
+		"updated settings": {
+			delta:    &store.GroupDelta{Update: []store.RuleDelta{{Existing: withSettings, New: changedSettings, Diff: withSettings.Diff(changedSettings)}}},
+			expected: true,
+		},
+		"updated rule with the same settings": {
+			delta:    &store.GroupDelta{Update: []store.RuleDelta{{Existing: withSettings, New: changedTitle, Diff: withSettings.Diff(changedTitle)}}},
+			expected: false,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, notificationSettingsChanged(tc.delta))
+		})
+	}
+}
+
 func createServiceWithProvenanceStore(ac *acMock.Mock, store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(ac, store)
 	svc.provenanceStore = provenanceStore
@@ -104,6 +104,11 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringFor(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -113,6 +118,14 @@ func validateRuleNode(
 			return nil, err
 		}
 	}
+
+	newAlertRule.NotificationSettings = NotificationSettingsFromAPI(ruleNode.GrafanaManagedAlert.NotificationSettings)
+	for _, settings := range newAlertRule.NotificationSettings {
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
+	}
+	newAlertRule.DependsOn = ruleNode.GrafanaManagedAlert.DependsOn
 	return &newAlertRule, nil
 }
 
@@ -147,6 +160,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringFor validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringFor(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
@@ -208,5 +236,13 @@ func validateRuleGroup(
 
 		result = append(result, &ruleWithOptionals)
 	}
+
+	rules := make([]*ngmodels.AlertRule, 0, len(result))
+	for _, rule := range result {
+		rules = append(rules, &rule.AlertRule)
+	}
+	if err := ngmodels.ValidateRuleGroupDependencies(rules); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
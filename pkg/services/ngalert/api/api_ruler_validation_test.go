@@ -133,6 +133,21 @@ func TestValidateRuleGroup(t *testing.T) {
 		}
 	})
 
+	t.Run("should accept rules that depend on other rules of the group", func(t *testing.T) {
+		r1 := validRule()
+		r2 := validRule()
+		r1.GrafanaManagedAlert.UID = util.GenerateShortUID()
+		r2.GrafanaManagedAlert.UID = util.GenerateShortUID()
+		r2.GrafanaManagedAlert.DependsOn = []string{r1.GrafanaManagedAlert.UID}
+		g := validGroup(cfg, r1, r2)
+		alerts, err := validateRuleGroup(&g, orgId, folder, func(condition models.Condition) error {
+			return nil
+		}, cfg)
+		require.NoError(t, err)
+		require.Empty(t, alerts[0].DependsOn)
+		require.Equal(t, []string{r1.GrafanaManagedAlert.UID}, alerts[1].DependsOn)
+	})
+
 	t.Run("should show the payload has isPaused field", func(t *testing.T) {
 		for _, rule := range rules {
 			isPaused := true
@@ -209,6 +224,37 @@ func TestValidateRuleGroupFailures(t *testing.T) {
 				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.UID)
 			},
 		},
+		{
+			name: "fail if rule depends on rule that is not in the group",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r1 := validRule()
+				r1.GrafanaManagedAlert.UID = util.GenerateShortUID()
+				r1.GrafanaManagedAlert.DependsOn = []string{util.GenerateShortUID()}
+				g := validGroup(cfg, r1)
+				return &g
+			},
+			assert: func(t *testing.T, apiModel *apimodels.PostableRuleGroupConfig, err error) {
+				require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.DependsOn[0])
+			},
+		},
+		{
+			name: "fail if rules depend on each other",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r1 := validRule()
+				r2 := validRule()
+				r1.GrafanaManagedAlert.UID = util.GenerateShortUID()
+				r2.GrafanaManagedAlert.UID = util.GenerateShortUID()
+				r1.GrafanaManagedAlert.DependsOn = []string{r2.GrafanaManagedAlert.UID}
+				r2.GrafanaManagedAlert.DependsOn = []string{r1.GrafanaManagedAlert.UID}
+				g := validGroup(cfg, r1, r2)
+				return &g
+			},
+			assert: func(t *testing.T, apiModel *apimodels.PostableRuleGroupConfig, err error) {
+				require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+				require.Contains(t, err.Error(), "cycle")
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -273,10 +319,45 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 			},
 			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
 				require.Equal(t, time.Duration(0), alert.For)
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
 				require.Nil(t, alert.Annotations)
 				require.Nil(t, alert.Labels)
 			},
 		},
+		{
+			name: "coverts keep firing for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(5 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "coverts notification settings",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				groupWait := model.Duration(30 * time.Second)
+				r.GrafanaManagedAlert.NotificationSettings = &apimodels.AlertRuleNotificationSettings{
+					Receiver:          "team",
+					GroupBy:           []string{"alertname"},
+					GroupWait:         &groupWait,
+					MuteTimeIntervals: []string{"weekends"},
+				}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, []models.NotificationSettings{{
+					Receiver:          "team",
+					GroupBy:           []string{"alertname"},
+					GroupWait:         api.GrafanaManagedAlert.NotificationSettings.GroupWait,
+					MuteTimeIntervals: []string{"weekends"},
+				}}, alert.NotificationSettings)
+			},
+		},
 		{
 			name: "defaults to NoData if NoDataState is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -373,6 +454,25 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if KeepFiringFor is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if notification settings have no receiver",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.NotificationSettings = &apimodels.AlertRuleNotificationSettings{
+					GroupBy: []string{"alertname"},
+				}
+				return &r
+			},
+		},
 		{
 			name: "fail if title is too long",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -528,6 +628,17 @@ func TestValidateRuleNode_UID(t *testing.T) {
 				require.Equal(t, models.ExecutionErrorState(""), alert.ExecErrState)
 			},
 		},
+		{
+			name: "use -1 KeepFiringFor if it is not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(-1), alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "use empty Condition and Data if they are empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RouteGetRuleVersions returns all versions of the rule, latest first.
+func (srv RulerSrv) RouteGetRuleVersions(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	rule, errResp := srv.getAuthorizedRule(c, ruleUID, false)
+	if errResp != nil {
+		return errResp
+	}
+
+	q := ngmodels.ListAlertRuleVersionsQuery{OrgID: c.SignedInUser.OrgID, RuleUID: rule.UID}
+	if err := srv.store.ListAlertRuleVersions(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get versions of the rule")
+	}
+
+	result := make(apimodels.RuleVersionsResponse, 0, len(q.Result))
+	for _, v := range q.Result {
+		result = append(result, toGettableRuleVersion(v))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersionsDiff returns the changes between the versions of the rule that are specified by the query parameters "from" and "to".
+// If "to" is not specified, the version is compared with the current version of the rule.
+//
+// The semantic dashboard diff (dashdiffs.DiffDashboards) is not used here: a rule version holds no dashboard or
+// panel model, the rule is only linked to a panel by the __dashboardUid__ and __panelId__ annotations, which are
+// reported as annotation changes. Queries are matched by refID, the same way the dashboard diff matches panel queries.
+func (srv RulerSrv) RouteGetRuleVersionsDiff(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid from version")
+	}
+
+	rule, errResp := srv.getAuthorizedRule(c, ruleUID, false)
+	if errResp != nil {
+		return errResp
+	}
+
+	to := rule.Version
+	if s := c.Query("to"); s != "" {
+		to, err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid to version")
+		}
+	}
+
+	fromVersion, errResp := srv.getRuleVersion(c, rule.UID, from)
+	if errResp != nil {
+		return errResp
+	}
+	toVersion, errResp := srv.getRuleVersion(c, rule.UID, to)
+	if errResp != nil {
+		return errResp
+	}
+
+	result := apimodels.RuleVersionDiff{
+		From:    from,
+		To:      to,
+		Changes: []apimodels.RuleVersionChange{},
+	}
+	for _, change := range fromVersion.Diff(toVersion) {
+		result.Changes = append(result.Changes, apimodels.RuleVersionChange{
+			Field: change.Field,
+			Old:   change.Old,
+			New:   change.New,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteRestoreRuleVersion updates the rule with the definition stored in the specified version.
+// The payload must contain the current version of the rule, otherwise returns status 409 Conflict.
+// Returns status 400 Bad Request if the rule was provisioned.
+func (srv RulerSrv) RouteRestoreRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64, payload apimodels.RestoreRuleVersionPayload) response.Response {
+	rule, errResp := srv.getAuthorizedRule(c, ruleUID, true)
+	if errResp != nil {
+		return errResp
+	}
+
+	restored, errResp := srv.getRuleVersion(c, rule.UID, version)
+	if errResp != nil {
+		return errResp
+	}
+	updated := restored.Restore(*rule)
+	if !authorizeDatasourceAccessForRule(&updated, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqOrgAdminOrEditor, evaluator)
+	}) {
+		return ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to restore the version because the user does not have read permissions for one or many data sources the version uses", ErrAuthorization), "")
+	}
+
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(ctx context.Context) error {
+		provenance, err := srv.provenanceStore.GetProvenance(ctx, rule, c.SignedInUser.OrgID)
+		if err != nil {
+			return err
+		}
+		if provenance != ngmodels.ProvenanceNone {
+			return fmt.Errorf("%w: alert rule %s", errProvisionedResource, rule.UID)
+		}
+		if payload.Version != rule.Version {
+			return fmt.Errorf("%w: the rule was changed to version %d", store.ErrOptimisticLock, rule.Version)
+		}
+		if len(updated.DependsOn) > 0 {
+			// the rules that the version depends on might have been deleted or moved to other groups since then
+			q := ngmodels.GetAlertRulesGroupByRuleUIDQuery{OrgID: rule.OrgID, UID: rule.UID}
+			if err := srv.store.GetAlertRulesGroupByRuleUID(ctx, &q); err != nil {
+				return err
+			}
+			group := make([]*ngmodels.AlertRule, 0, len(q.Result))
+			for _, r := range q.Result {
+				if r.UID == updated.UID {
+					r = &updated
+				}
+				group = append(group, r)
+			}
+			if err := ngmodels.ValidateRuleGroupDependencies(group); err != nil {
+				return err
+			}
+		}
+		return srv.store.UpdateAlertRules(ctx, []ngmodels.UpdateRule{{
+			Existing:     rule,
+			New:          updated,
+			RestoredFrom: version,
+		}})
+	})
+	if err != nil {
+		if errors.Is(err, errProvisionedResource) {
+			return ErrResp(http.StatusBadRequest, err, "failed to restore rule version")
+		} else if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "failed to restore rule version")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to restore rule version")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": fmt.Sprintf("rule restored from version %d", version)})
+}
+
+func (srv RulerSrv) getAuthorizedRule(c *contextmodel.ReqContext, ruleUID string, edit bool) (*ngmodels.AlertRule, response.Response) {
+	return getAuthorizedRule(c, srv.store, srv.ac, ruleUID, edit)
+}
+
+// getAuthorizedRule returns the rule if the user can access its folder and all data sources it uses.
+// If edit is true, the user must also be able to update rules in the folder.
+func getAuthorizedRule(c *contextmodel.ReqContext, ruleStore RuleStore, ac accesscontrol.AccessControl, ruleUID string, edit bool) (*ngmodels.AlertRule, response.Response) {
+	q := ngmodels.GetAlertRuleByUIDQuery{OrgID: c.SignedInUser.OrgID, UID: ruleUID}
+	if err := ruleStore.GetAlertRuleByUID(c.Req.Context(), &q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return nil, ErrResp(http.StatusNotFound, err, "")
+		}
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	rule := q.Result
+
+	// if RBAC is disabled, access to the rule is limited by the permissions of the folder
+	namespaces, err := ruleStore.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	namespace, ok := namespaces[rule.NamespaceUID]
+	if !ok {
+		return nil, ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+	if edit {
+		if _, err := ruleStore.GetNamespaceByTitle(c.Req.Context(), namespace.Title, c.SignedInUser.OrgID, c.SignedInUser, true); err != nil {
+			return nil, toNamespaceErrorResponse(err)
+		}
+	}
+
+	role, action := accesscontrol.ReqViewer, accesscontrol.ActionAlertingRuleRead
+	if edit {
+		role, action = accesscontrol.ReqOrgAdminOrEditor, accesscontrol.ActionAlertingRuleUpdate
+	}
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(ac, c)(role, evaluator)
+	}
+	if !hasAccess(accesscontrol.EvalPermission(action, dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID))) {
+		return nil, ErrResp(http.StatusForbidden, fmt.Errorf("%w to access rules in folder %s", ErrAuthorization, rule.NamespaceUID), "")
+	}
+	if !authorizeDatasourceAccessForRule(rule, hasAccess) {
+		return nil, ErrResp(http.StatusUnauthorized, fmt.Errorf("%w to access the rule because it does not have access to one or many data sources the rule uses", ErrAuthorization), "")
+	}
+	return rule, nil
+}
+
+func (srv RulerSrv) getRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64) (*ngmodels.AlertRuleVersion, response.Response) {
+	q := ngmodels.GetAlertRuleVersionQuery{OrgID: c.SignedInUser.OrgID, RuleUID: ruleUID, Version: version}
+	if err := srv.store.GetAlertRuleVersion(c.Req.Context(), &q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+			return nil, ErrResp(http.StatusNotFound, err, "version %d", version)
+		}
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get version of the rule")
+	}
+	return q.Result, nil
+}
+
+func toGettableRuleVersion(v *ngmodels.AlertRuleVersion) apimodels.GettableRuleVersion {
+	return apimodels.GettableRuleVersion{
+		Version:              v.Version,
+		ParentVersion:        v.ParentVersion,
+		RestoredFrom:         v.RestoredFrom,
+		Created:              v.Created,
+		CreatedBy:            v.CreatedBy,
+		Title:                v.Title,
+		Condition:            v.Condition,
+		Data:                 v.Data,
+		For:                  model.Duration(v.For),
+		KeepFiringFor:        model.Duration(v.KeepFiringFor),
+		IntervalSeconds:      v.IntervalSeconds,
+		NamespaceUID:         v.RuleNamespaceUID,
+		RuleGroup:            v.RuleGroup,
+		NoDataState:          apimodels.NoDataState(v.NoDataState),
+		ExecErrState:         apimodels.ExecutionErrorState(v.ExecErrState),
+		IsPaused:             v.IsPaused,
+		Labels:               v.Labels,
+		Annotations:          v.Annotations,
+		NotificationSettings: NotificationSettingsToAPI(v.NotificationSettings),
+		DependsOn:            v.DependsOn,
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestRouteGetRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	rule.Version = 2
+	ruleStore.PutRule(context.Background(), rule)
+	first, second := ruleVersionsOf(rule)
+	first.CreatedBy = "alice"
+	second.CreatedBy = "bob"
+	ruleStore.PutRuleVersion(first, second)
+
+	t.Run("should return versions latest first", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore).RouteGetRuleVersions(createRequestContext(orgID, org.RoleViewer, nil), rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionsResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 2)
+		require.Equal(t, int64(2), result[0].Version)
+		require.Equal(t, "bob", result[0].CreatedBy)
+		require.Equal(t, int64(1), result[1].Version)
+		require.Equal(t, "alice", result[1].CreatedBy)
+	})
+
+	t.Run("should return 404 if rule does not exist", func(t *testing.T) {
+		response := createService(acMock.New().WithDisabled(), ruleStore).RouteGetRuleVersions(createRequestContext(orgID, org.RoleViewer, nil), "unknown")
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 401 if user does not have access to data sources of the rule", func(t *testing.T) {
+		ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: accesscontrol.ActionAlertingRuleRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)},
+		})
+		response := createService(ac, ruleStore).RouteGetRuleVersions(createRequestContext(orgID, "", nil), rule.UID)
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+}
+
+func TestRouteGetRuleVersionsDiff(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	rule.Version = 2
+	ruleStore.PutRule(context.Background(), rule)
+	first, second := ruleVersionsOf(rule)
+	first.For = second.For + time.Minute
+	first.Labels = map[string]string{"team": "a"}
+	second.Labels = map[string]string{"team": "b"}
+	ruleStore.PutRuleVersion(first, second)
+	svc := createService(acMock.New().WithDisabled(), ruleStore)
+
+	t.Run("should compare with the current version by default", func(t *testing.T) {
+		request := createRequestContext(orgID, org.RoleViewer, nil)
+		request.Req.URL.RawQuery = "from=1"
+		response := svc.RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result apimodels.RuleVersionDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, int64(1), result.From)
+		require.Equal(t, int64(2), result.To)
+		require.Equal(t, []apimodels.RuleVersionChange{
+			{Field: "for", Old: first.For.String(), New: second.For.String()},
+			{Field: "labels.team", Old: "a", New: "b"},
+		}, result.Changes)
+	})
+
+	t.Run("should return 400 if from is missing", func(t *testing.T) {
+		response := svc.RouteGetRuleVersionsDiff(createRequestContext(orgID, org.RoleViewer, nil), rule.UID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if version does not exist", func(t *testing.T) {
+		request := createRequestContext(orgID, org.RoleViewer, nil)
+		request.Req.URL.RawQuery = "from=1&to=5"
+		response := svc.RouteGetRuleVersionsDiff(request, rule.UID)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
+
+func TestRouteRestoreRuleVersion(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	rule.Version = 2
+	ruleStore.PutRule(context.Background(), rule)
+	first, second := ruleVersionsOf(rule)
+	first.Title = "previous title"
+	ruleStore.PutRuleVersion(first, second)
+
+	t.Run("should update the rule with the definition of the version", func(t *testing.T) {
+		ruleStore.RecordedOps = nil
+		svc := createService(acMock.New().WithDisabled(), ruleStore)
+		response := svc.RouteRestoreRuleVersion(createRequestContext(orgID, org.RoleEditor, nil), rule.UID, 1, apimodels.RestoreRuleVersionPayload{Version: 2})
+		require.Equal(t, http.StatusAccepted, response.Status())
+
+		var updates []models.UpdateRule
+		for _, op := range ruleStore.RecordedOps {
+			if u, ok := op.([]models.UpdateRule); ok {
+				updates = append(updates, u...)
+			}
+		}
+		require.Len(t, updates, 1)
+		require.Equal(t, int64(1), updates[0].RestoredFrom)
+		require.Equal(t, rule.UID, updates[0].Existing.UID)
+		require.Equal(t, "previous title", updates[0].New.Title)
+	})
+
+	t.Run("should return 409 if the rule was changed", func(t *testing.T) {
+		svc := createService(acMock.New().WithDisabled(), ruleStore)
+		response := svc.RouteRestoreRuleVersion(createRequestContext(orgID, org.RoleEditor, nil), rule.UID, 1, apimodels.RestoreRuleVersionPayload{Version: 1})
+		require.Equal(t, http.StatusConflict, response.Status())
+	})
+
+	t.Run("should return 400 if the rule is provisioned", func(t *testing.T) {
+		provenanceStore := provisioning.NewFakeProvisioningStore()
+		require.NoError(t, provenanceStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+		svc := createServiceWithProvenanceStore(acMock.New().WithDisabled(), ruleStore, provenanceStore)
+		response := svc.RouteRestoreRuleVersion(createRequestContext(orgID, org.RoleEditor, nil), rule.UID, 1, apimodels.RestoreRuleVersionPayload{Version: 2})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 403 if user cannot update rules", func(t *testing.T) {
+		svc := createService(acMock.New().WithDisabled(), ruleStore)
+		response := svc.RouteRestoreRuleVersion(createRequestContext(orgID, org.RoleViewer, nil), rule.UID, 1, apimodels.RestoreRuleVersionPayload{Version: 2})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
+// ruleVersionsOf returns the first and the second version of the rule.
+func ruleVersionsOf(rule *models.AlertRule) (*models.AlertRuleVersion, *models.AlertRuleVersion) {
+	gen := func(version int64) *models.AlertRuleVersion {
+		return &models.AlertRuleVersion{
+			RuleOrgID:        rule.OrgID,
+			RuleUID:          rule.UID,
+			RuleNamespaceUID: rule.NamespaceUID,
+			RuleGroup:        rule.RuleGroup,
+			ParentVersion:    version - 1,
+			Version:          version,
+			Created:          rule.Updated,
+			Title:            rule.Title,
+			Condition:        rule.Condition,
+			Data:             rule.Data,
+			IntervalSeconds:  rule.IntervalSeconds,
+			NoDataState:      rule.NoDataState,
+			ExecErrState:     rule.ExecErrState,
+			For:              rule.For,
+			KeepFiringFor:    rule.KeepFiringFor,
+			Annotations:      rule.Annotations,
+			Labels:           rule.Labels,
+		}
+	}
+	return gen(1), gen(2)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// StateArchiver exports the runtime state of Grafana Alerting to an archive and imports it back.
+type StateArchiver interface {
+	Export(ctx context.Context) (*apimodels.StateArchive, error)
+	Validate(ctx context.Context, archive *apimodels.StateArchive) error
+	Import(ctx context.Context, archive *apimodels.StateArchive) error
+}
+
+// AlertmanagerStateReplacer persists the state of the running Alertmanagers, and stops the Alertmanagers of
+// the organizations while their persisted state is replaced.
+type AlertmanagerStateReplacer interface {
+	PersistState(ctx context.Context) error
+	ReplaceState(ctx context.Context, orgIDs []int64, replace func(ctx context.Context) error) error
+}
+
+// StateReplacer stops processing evaluation results while the persisted alert instances are replaced, and then loads them.
+type StateReplacer interface {
+	Replace(ctx context.Context, rulesReader state.RuleReader, replace func(ctx context.Context) error) error
+}
+
+func (srv ConfigSrv) RouteGetStateArchive(c *contextmodel.ReqContext) response.Response {
+	// The Alertmanagers persist silences and the notification log only periodically.
+	if err := srv.alertmanagers.PersistState(c.Req.Context()); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to persist the state of the Alertmanagers")
+	}
+	archive, err := srv.stateArchive.Export(c.Req.Context())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to export the alerting state")
+	}
+	return response.JSON(http.StatusOK, archive)
+}
+
+func (srv ConfigSrv) RoutePostStateArchive(c *contextmodel.ReqContext, body apimodels.StateArchive) response.Response {
+	ctx := c.Req.Context()
+	if err := srv.stateArchive.Validate(ctx, &body); err != nil {
+		if errors.Is(err, statearchive.ErrInvalidArchive) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to validate the alerting state archive")
+	}
+
+	orgIDs := make([]int64, 0, len(body.Orgs))
+	for _, orgState := range body.Orgs {
+		orgIDs = append(orgIDs, orgState.OrgID)
+	}
+	err := srv.alertmanagers.ReplaceState(ctx, orgIDs, func(ctx context.Context) error {
+		// The scheduler must not persist alert instances during the import, and its cache must be reloaded afterwards.
+		return srv.stateManager.Replace(ctx, srv.ruleStore, func(ctx context.Context) error {
+			return srv.stateArchive.Import(ctx, &body)
+		})
+	})
+	if err != nil {
+		srv.log.Error("failed to import the alerting state", "error", err)
+		return ErrResp(http.StatusInternalServerError, err, "failed to import the alerting state")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "alerting state imported"})
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/statearchive"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestRouteGetStateArchive(t *testing.T) {
+	archive := &apimodels.StateArchive{Version: statearchive.Version, Orgs: []apimodels.OrgStateArchive{{OrgID: 1}}}
+	archiver := &fakeStateArchiver{archive: archive}
+	alertmanagers := &fakeAlertmanagerStateReplacer{}
+	srv := createStateArchiveSut(archiver, alertmanagers, &fakeStateReplacer{})
+
+	response := srv.RouteGetStateArchive(createRequestContext(1, org.RoleAdmin, nil))
+	require.Equal(t, http.StatusOK, response.Status())
+	require.Equal(t, 1, alertmanagers.persisted)
+	var result apimodels.StateArchive
+	require.NoError(t, json.Unmarshal(response.Body(), &result))
+	require.Equal(t, *archive, result)
+}
+
+func TestRoutePostStateArchive(t *testing.T) {
+	archive := apimodels.StateArchive{
+		Version: statearchive.Version,
+		Orgs:    []apimodels.OrgStateArchive{{OrgID: 1}, {OrgID: 3}},
+	}
+
+	t.Run("should import the archive while the Alertmanagers and the state manager are stopped", func(t *testing.T) {
+		archiver := &fakeStateArchiver{}
+		alertmanagers := &fakeAlertmanagerStateReplacer{}
+		replacer := &fakeStateReplacer{}
+		srv := createStateArchiveSut(archiver, alertmanagers, replacer)
+
+		response := srv.RoutePostStateArchive(createRequestContext(1, org.RoleAdmin, nil), archive)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Equal(t, []int64{1, 3}, alertmanagers.stoppedOrgs)
+		require.Equal(t, 1, archiver.imported)
+		require.Equal(t, 1, replacer.calls)
+	})
+
+	t.Run("should return 400 if the archive is invalid", func(t *testing.T) {
+		archiver := &fakeStateArchiver{validateErr: fmt.Errorf("%w: bad", statearchive.ErrInvalidArchive)}
+		alertmanagers := &fakeAlertmanagerStateReplacer{}
+		replacer := &fakeStateReplacer{}
+		srv := createStateArchiveSut(archiver, alertmanagers, replacer)
+
+		response := srv.RoutePostStateArchive(createRequestContext(1, org.RoleAdmin, nil), archive)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Nil(t, alertmanagers.stoppedOrgs)
+		require.Zero(t, replacer.calls)
+	})
+
+	t.Run("should reload the state cache if the import fails", func(t *testing.T) {
+		archiver := &fakeStateArchiver{importErr: errors.New("test")}
+		replacer := &fakeStateReplacer{}
+		srv := createStateArchiveSut(archiver, &fakeAlertmanagerStateReplacer{}, replacer)
+
+		response := srv.RoutePostStateArchive(createRequestContext(1, org.RoleAdmin, nil), archive)
+		require.Equal(t, http.StatusInternalServerError, response.Status())
+		require.Equal(t, 1, replacer.calls)
+	})
+}
+
+func createStateArchiveSut(archiver StateArchiver, alertmanagers AlertmanagerStateReplacer, replacer StateReplacer) ConfigSrv {
+	return ConfigSrv{
+		log:           log.NewNopLogger(),
+		stateArchive:  archiver,
+		alertmanagers: alertmanagers,
+		stateManager:  replacer,
+	}
+}
+
+type fakeStateArchiver struct {
+	archive     *apimodels.StateArchive
+	validateErr error
+	importErr   error
+
+	imported int
+}
+
+func (f *fakeStateArchiver) Export(context.Context) (*apimodels.StateArchive, error) {
+	return f.archive, nil
+}
+
+func (f *fakeStateArchiver) Validate(context.Context, *apimodels.StateArchive) error {
+	return f.validateErr
+}
+
+func (f *fakeStateArchiver) Import(context.Context, *apimodels.StateArchive) error {
+	f.imported++
+	return f.importErr
+}
+
+type fakeAlertmanagerStateReplacer struct {
+	stoppedOrgs []int64
+	persisted   int
+}
+
+func (f *fakeAlertmanagerStateReplacer) PersistState(context.Context) error {
+	f.persisted++
+	return nil
+}
+
+func (f *fakeAlertmanagerStateReplacer) ReplaceState(ctx context.Context, orgIDs []int64, replace func(ctx context.Context) error) error {
+	f.stoppedOrgs = orgIDs
+	return replace(ctx)
+}
+
+type fakeStateReplacer struct {
+	calls int
+}
+
+func (f *fakeStateReplacer) Replace(ctx context.Context, _ state.RuleReader, replace func(ctx context.Context) error) error {
+	f.calls++
+	return replace(ctx)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -20,6 +21,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -33,6 +37,9 @@ type TestingApiSrv struct {
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
 	featureManager  featuremgmt.FeatureToggles
+	mam             *notifier.MultiOrgAlertmanager
+	stateManager    state.AlertInstanceManager
+	ruleStore       RuleStore
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -154,28 +161,97 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(400, nil, "From cannot be greater than To")
 	}
 
+	rule, errResp := srv.backtestRule(c, cmd.BacktestRule)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		return backtestErrorToResponse(err)
+	}
+
+	body, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return ErrResp(500, err, "Failed to convert frame to JSON")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+func (srv TestingApiSrv) BacktestAlertRuleTimeline(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+	}
+
+	if cmd.From.After(cmd.To) {
+		return ErrResp(400, nil, "From cannot be greater than To")
+	}
+
+	rule, errResp := srv.backtestRule(c, cmd.BacktestRule)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Run(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		return backtestErrorToResponse(err)
+	}
+	return response.JSON(http.StatusOK, BacktestResultToTimeline(result))
+}
+
+func (srv TestingApiSrv) BacktestCompareAlertRules(c *contextmodel.ReqContext, cmd apimodels.BacktestCompareConfig) response.Response {
+	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+	}
+
+	if cmd.From.After(cmd.To) {
+		return ErrResp(400, nil, "From cannot be greater than To")
+	}
+
+	ruleA, errResp := srv.backtestRule(c, cmd.A)
+	if errResp != nil {
+		return errResp
+	}
+	ruleB, errResp := srv.backtestRule(c, cmd.B)
+	if errResp != nil {
+		return errResp
+	}
+
+	comparison, err := srv.backtesting.Compare(c.Req.Context(), c.SignedInUser, ruleA, ruleB, cmd.From, cmd.To)
+	if err != nil {
+		return backtestErrorToResponse(err)
+	}
+	return response.JSON(http.StatusOK, BacktestComparisonToAPI(comparison))
+}
+
+// backtestRule validates the rule to backtest and converts it to an alert rule.
+func (srv TestingApiSrv) backtestRule(c *contextmodel.ReqContext, cmd apimodels.BacktestRule) (*ngmodels.AlertRule, response.Response) {
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
 
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
+		return nil, ErrResp(400, nil, "Bad For interval")
+	}
+	keepFiringFor := time.Duration(cmd.KeepFiringFor)
+	if keepFiringFor < 0 {
+		return nil, ErrResp(400, nil, "Bad KeepFiringFor interval")
 	}
 
 	intervalSeconds, err := validateInterval(srv.cfg, time.Duration(cmd.Interval))
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 
 	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
 		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
 	}) {
-		return errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
+		return nil, errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
 	}
 
-	rule := &ngmodels.AlertRule{
+	return &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
 		// Version:        0,
@@ -194,21 +270,90 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		IntervalSeconds: intervalSeconds,
 		NoDataState:     noDataState,
 		For:             forInterval,
+		KeepFiringFor:   keepFiringFor,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
+	}, nil
+}
+
+// RouteTestNotificationRoute routes alerts through the notification policy tree of the org without sending any notification.
+// It returns the policies every alert matches, the contact points and timings they use, and what mutes the notifications at the requested time.
+func (srv TestingApiSrv) RouteTestNotificationRoute(c *contextmodel.ReqContext, body apimodels.TestNotificationRoutePayload) response.Response {
+	at := body.Time
+	if at.IsZero() {
+		at = timeNow()
 	}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	result := apimodels.TestNotificationRouteResponse{Time: at, Alerts: []apimodels.TestNotificationRouted{}}
+	if body.RuleUID != "" {
+		rule, errResp := getAuthorizedRule(c, srv.ruleStore, srv.accessControl, body.RuleUID, false)
+		if errResp != nil {
+			return errResp
+		}
+		for _, s := range srv.stateManager.GetStatesForRuleUID(c.OrgID, rule.UID) {
+			result.Alerts = append(result.Alerts, apimodels.TestNotificationRouted{Labels: s.Labels, State: s.State.String()})
+		}
+	} else {
+		result.Alerts = append(result.Alerts, apimodels.TestNotificationRouted{Labels: body.Labels})
+	}
+
+	cfg, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.OrgID)
 	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusNotFound, err, "")
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the Alertmanager configuration")
+	}
+	if cfg.AlertmanagerConfig.Route == nil {
+		return ErrResp(http.StatusNotFound, errors.New("the Alertmanager configuration has no notification policies"), "")
 	}
 
-	body, err := data.FrameToJSON(result, data.IncludeAll)
+	am, errResp := alertmanagerFor(srv.mam, srv.log, c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+	silences, err := am.ListSilences(nil)
 	if err != nil {
-		return ErrResp(500, err, "Failed to convert frame to JSON")
+		return ErrResp(http.StatusInternalServerError, err, "failed to list silences")
 	}
-	return response.JSON(http.StatusOK, body)
+
+	for i := range result.Alerts {
+		alert := &result.Alerts[i]
+		lset := make(model.LabelSet, len(alert.Labels))
+		for k, v := range alert.Labels {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+
+		alert.SilencedBy, err = notifier.MatchingSilences(silences, lset, at)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to match silences")
+		}
+		if alert.SilencedBy == nil {
+			alert.SilencedBy = []string{}
+		}
+
+		for _, match := range notifier.MatchRoutes(cfg.AlertmanagerConfig.Route, lset) {
+			opts := match.Route.RouteOpts
+			alert.Routes = append(alert.Routes, apimodels.TestNotificationRouteMatch{
+				Path:                    match.Path,
+				Receiver:                opts.Receiver,
+				GroupBy:                 match.GroupBy(),
+				GroupWait:               model.Duration(opts.GroupWait),
+				GroupInterval:           model.Duration(opts.GroupInterval),
+				RepeatInterval:          model.Duration(opts.RepeatInterval),
+				Continue:                match.Route.Continue,
+				MuteTimeIntervals:       opts.MuteTimeIntervals,
+				ActiveMuteTimeIntervals: notifier.ActiveMuteTimeIntervals(cfg.AlertmanagerConfig.MuteTimeIntervals, opts.MuteTimeIntervals, at),
+			})
+		}
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+func backtestErrorToResponse(err error) response.Response {
+	if errors.Is(err, backtesting.ErrInvalidInputData) {
+		return ErrResp(400, err, "Failed to evaluate")
+	}
+	return ErrResp(500, err, "Failed to evaluate")
 }
@@ -1,14 +1,19 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -18,6 +23,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -288,3 +295,83 @@ func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator
 		evaluator:       evaluator,
 	}
 }
+
+func TestRouteTestNotificationRoute(t *testing.T) {
+	orgID := int64(1)
+	folder := randFolder()
+	ruleStore := ngfakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	rule := models.AlertRuleGen(withOrgID(orgID), withNamespace(folder))()
+	ruleStore.PutRule(context.Background(), rule)
+	stateManager := NewFakeAlertInstanceManager(t)
+	stateManager.GenerateAlertInstances(orgID, rule.UID, 2)
+
+	mam := createMultiOrgAlertmanager(t)
+	srv := &TestingApiSrv{
+		accessControl: acMock.New().WithDisabled(),
+		log:           log.NewNopLogger(),
+		mam:           mam,
+		stateManager:  stateManager,
+		ruleStore:     ruleStore,
+	}
+
+	t.Run("should route labels to the default policy", func(t *testing.T) {
+		response := srv.RouteTestNotificationRoute(createRequestContext(orgID, org.RoleViewer, nil), definitions.TestNotificationRoutePayload{
+			Labels: map[string]string{"alertname": "test"},
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result definitions.TestNotificationRouteResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Alerts, 1)
+		require.Empty(t, result.Alerts[0].SilencedBy)
+		require.Len(t, result.Alerts[0].Routes, 1)
+		require.Equal(t, []int{}, result.Alerts[0].Routes[0].Path)
+		require.Equal(t, "grafana-default-email", result.Alerts[0].Routes[0].Receiver)
+	})
+
+	t.Run("should return the silences that match the labels", func(t *testing.T) {
+		am, err := mam.AlertmanagerFor(orgID)
+		require.NoError(t, err)
+		silence := silenceGen(withEmptyID, func(s *definitions.PostableSilence) {
+			name, value, isEqual, isRegex := "team", "a", true, false
+			s.Matchers = amv2.Matchers{&amv2.Matcher{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex}}
+			starts, ends := strfmt.DateTime(time.Now().Add(-time.Hour)), strfmt.DateTime(time.Now().Add(time.Hour))
+			s.StartsAt, s.EndsAt = &starts, &ends
+		})()
+		id, err := am.CreateSilence(&silence)
+		require.NoError(t, err)
+
+		response := srv.RouteTestNotificationRoute(createRequestContext(orgID, org.RoleViewer, nil), definitions.TestNotificationRoutePayload{
+			Labels: map[string]string{"team": "a"},
+			Time:   time.Now().Add(time.Minute),
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result definitions.TestNotificationRouteResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, []string{id}, result.Alerts[0].SilencedBy)
+	})
+
+	t.Run("should route the current alert instances of a rule", func(t *testing.T) {
+		response := srv.RouteTestNotificationRoute(createRequestContext(orgID, org.RoleViewer, nil), definitions.TestNotificationRoutePayload{
+			RuleUID: rule.UID,
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var result definitions.TestNotificationRouteResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Alerts, 2)
+		for _, alert := range result.Alerts {
+			require.Equal(t, eval.Normal.String(), alert.State)
+			require.Equal(t, "grafana-default-email", alert.Routes[0].Receiver)
+		}
+	})
+
+	t.Run("should return 404 if rule does not exist", func(t *testing.T) {
+		response := srv.RouteTestNotificationRoute(createRequestContext(orgID, org.RoleViewer, nil), definitions.TestNotificationRoutePayload{
+			RuleUID: "unknown",
+		})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+}
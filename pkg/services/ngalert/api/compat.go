@@ -1,54 +1,94 @@
 package api
 
 import (
+	"sort"
 	"time"
 
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting/file"
 )
 
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         a.Data,
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:                   a.ID,
+		UID:                  a.UID,
+		OrgID:                a.OrgID,
+		NamespaceUID:         a.FolderUID,
+		RuleGroup:            a.RuleGroup,
+		Title:                a.Title,
+		Condition:            a.Condition,
+		Data:                 a.Data,
+		Updated:              a.Updated,
+		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:                  time.Duration(a.For),
+		KeepFiringFor:        time.Duration(a.KeepFiringFor),
+		Annotations:          a.Annotations,
+		Labels:               a.Labels,
+		IsPaused:             a.IsPaused,
+		NotificationSettings: NotificationSettingsFromAPI(a.NotificationSettings),
+		DependsOn:            a.DependsOn,
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         rule.Data,
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:                   rule.ID,
+		UID:                  rule.UID,
+		OrgID:                rule.OrgID,
+		FolderUID:            rule.NamespaceUID,
+		RuleGroup:            rule.RuleGroup,
+		Title:                rule.Title,
+		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
+		Condition:            rule.Condition,
+		Data:                 rule.Data,
+		Updated:              rule.Updated,
+		NoDataState:          definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:         definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:          rule.Annotations,
+		Labels:               rule.Labels,
+		Provenance:           definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:             rule.IsPaused,
+		NotificationSettings: NotificationSettingsToAPI(rule.NotificationSettings),
+		DependsOn:            rule.DependsOn,
+	}
+}
+
+// NotificationSettingsFromAPI converts definitions.AlertRuleNotificationSettings to the notification settings of models.AlertRule
+func NotificationSettingsFromAPI(s *definitions.AlertRuleNotificationSettings) []models.NotificationSettings {
+	if s == nil {
+		return nil
+	}
+	return []models.NotificationSettings{{
+		Receiver:          s.Receiver,
+		GroupBy:           s.GroupBy,
+		GroupWait:         s.GroupWait,
+		GroupInterval:     s.GroupInterval,
+		RepeatInterval:    s.RepeatInterval,
+		MuteTimeIntervals: s.MuteTimeIntervals,
+	}}
+}
+
+// NotificationSettingsToAPI converts the notification settings of models.AlertRule to definitions.AlertRuleNotificationSettings
+func NotificationSettingsToAPI(settings []models.NotificationSettings) *definitions.AlertRuleNotificationSettings {
+	if len(settings) == 0 {
+		return nil
+	}
+	s := settings[0]
+	return &definitions.AlertRuleNotificationSettings{
+		Receiver:          s.Receiver,
+		GroupBy:           s.GroupBy,
+		GroupWait:         s.GroupWait,
+		GroupInterval:     s.GroupInterval,
+		RepeatInterval:    s.RepeatInterval,
+		MuteTimeIntervals: s.MuteTimeIntervals,
 	}
 }
 
@@ -89,3 +129,154 @@ func AlertRuleGroupToApi(d models.AlertRuleGroup) definitions.AlertRuleGroup {
 		Rules:     rules,
 	}
 }
+
+// BacktestResultToTimeline converts backtesting.Result to definitions.BacktestTimeline
+func BacktestResultToTimeline(r *backtesting.Result) definitions.BacktestTimeline {
+	instances := make([]definitions.BacktestInstance, 0, len(r.Instances))
+	for _, instance := range r.Instances {
+		transitions := make([]definitions.BacktestTransition, 0, len(instance.Transitions))
+		for _, t := range instance.Transitions {
+			transitions = append(transitions, definitions.BacktestTransition{
+				At:          t.At,
+				From:        t.From.String(),
+				To:          t.To.String(),
+				Labels:      t.Labels,
+				Annotations: t.Annotations,
+				Values:      t.Values,
+			})
+		}
+		states := make([]*string, len(instance.States))
+		for i, s := range instance.States {
+			if s != nil {
+				formatted := s.String()
+				states[i] = &formatted
+			}
+		}
+		instances = append(instances, definitions.BacktestInstance{
+			Labels:                instance.Labels,
+			Annotations:           instance.Annotations,
+			Transitions:           transitions,
+			States:                states,
+			PendingDuration:       model.Duration(instance.PendingDuration),
+			FiringDuration:        model.Duration(instance.FiringDuration),
+			FiringNotifications:   instance.FiringNotifications,
+			ResolvedNotifications: instance.ResolvedNotifications,
+			Flaps:                 instance.Flaps,
+		})
+	}
+	return definitions.BacktestTimeline{
+		From:       r.From,
+		To:         r.To,
+		Interval:   model.Duration(r.Interval),
+		Timestamps: r.Timestamps,
+		Summary:    definitions.BacktestSummary(r.Summary),
+		Instances:  instances,
+		Frames:     r.Frames(),
+	}
+}
+
+// BacktestComparisonToAPI converts backtesting.Comparison to definitions.BacktestComparison
+func BacktestComparisonToAPI(c *backtesting.Comparison) definitions.BacktestComparison {
+	differences := make([]definitions.BacktestInstanceDifference, 0, len(c.Differences))
+	for _, d := range c.Differences {
+		differences = append(differences, definitions.BacktestInstanceDifference{
+			Labels:         d.Labels,
+			OnlyInA:        d.OnlyInA,
+			OnlyInB:        d.OnlyInB,
+			Evaluations:    d.Evaluations,
+			NotificationsA: d.NotificationsA,
+			NotificationsB: d.NotificationsB,
+		})
+	}
+	return definitions.BacktestComparison{
+		A:           BacktestResultToTimeline(c.A),
+		B:           BacktestResultToTimeline(c.B),
+		Differences: differences,
+	}
+}
+
+// ContactPointsExportFromEmbeddedContactPoints converts []definitions.EmbeddedContactPoint to []file.ContactPointExport.
+// The integrations are grouped into contact points by name, in the order of the first integration of each contact point.
+func ContactPointsExportFromEmbeddedContactPoints(orgID int64, cps []definitions.EmbeddedContactPoint) ([]file.ContactPointExport, error) {
+	result := make([]file.ContactPointExport, 0, len(cps))
+	byName := make(map[string]int, len(cps))
+	for _, cp := range cps {
+		settings, err := cp.Settings.Map()
+		if err != nil {
+			return nil, err
+		}
+		idx, ok := byName[cp.Name]
+		if !ok {
+			idx = len(result)
+			byName[cp.Name] = idx
+			result = append(result, file.ContactPointExport{OrgID: orgID, Name: cp.Name})
+		}
+		result[idx].Receivers = append(result[idx].Receivers, file.ReceiverExport{
+			UID:                   cp.UID,
+			Type:                  cp.Type,
+			Settings:              settings,
+			DisableResolveMessage: cp.DisableResolveMessage,
+		})
+	}
+	return result, nil
+}
+
+// NotificationPolicyExportFromRoute converts definitions.Route to file.NotificationPolicyExport
+func NotificationPolicyExportFromRoute(orgID int64, route definitions.Route) file.NotificationPolicyExport {
+	return file.NotificationPolicyExport{
+		OrgID:       orgID,
+		RouteExport: RouteExportFromRoute(&route),
+	}
+}
+
+// RouteExportFromRoute converts definitions.Route and all its nested routes to file.RouteExport
+func RouteExportFromRoute(route *definitions.Route) file.RouteExport {
+	export := file.RouteExport{
+		Receiver:          route.Receiver,
+		GroupByStr:        route.GroupByStr,
+		Match:             route.Match,
+		MatchRE:           route.MatchRE,
+		Matchers:          route.Matchers,
+		MuteTimeIntervals: route.MuteTimeIntervals,
+		Continue:          route.Continue,
+		GroupWait:         route.GroupWait,
+		GroupInterval:     route.GroupInterval,
+		RepeatInterval:    route.RepeatInterval,
+	}
+	for _, m := range route.ObjectMatchers {
+		export.ObjectMatchers = append(export.ObjectMatchers, file.ObjectMatcher{m.Name, m.Type.String(), m.Value})
+	}
+	for _, r := range route.Routes {
+		child := RouteExportFromRoute(r)
+		export.Routes = append(export.Routes, &child)
+	}
+	return export
+}
+
+// MuteTimingsExportFromMuteTimeIntervals converts []definitions.MuteTimeInterval to []file.MuteTimeIntervalExport
+func MuteTimingsExportFromMuteTimeIntervals(orgID int64, intervals []definitions.MuteTimeInterval) []file.MuteTimeIntervalExport {
+	result := make([]file.MuteTimeIntervalExport, 0, len(intervals))
+	for _, interval := range intervals {
+		result = append(result, file.MuteTimeIntervalExport{
+			OrgID:            orgID,
+			MuteTimeInterval: interval.MuteTimeInterval,
+		})
+	}
+	return result
+}
+
+// TemplatesExportFromTemplates converts the content of templates by name to []file.NotificationTemplateExport sorted by name
+func TemplatesExportFromTemplates(orgID int64, templates map[string]string) []file.NotificationTemplateExport {
+	result := make([]file.NotificationTemplateExport, 0, len(templates))
+	for name, tmpl := range templates {
+		result = append(result, file.NotificationTemplateExport{
+			OrgID:    orgID,
+			Name:     name,
+			Template: tmpl,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -36,3 +36,11 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteGetStateArchive(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetStateArchive(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostStateArchive(c *contextmodel.ReqContext, body apimodels.StateArchive) response.Response {
+	return f.grafana.RoutePostStateArchive(c, body)
+}
@@ -22,8 +22,10 @@ type ConfigurationApi interface {
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
+	RouteGetStateArchive(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
+	RoutePostStateArchive(*contextmodel.ReqContext) response.Response
 }
 
 func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
@@ -35,6 +37,9 @@ func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqCon
 func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetNGalertConfig(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetStateArchive(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetStateArchive(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStatus(ctx)
 }
@@ -46,6 +51,14 @@ func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqCo
 	}
 	return f.handleRoutePostNGalertConfig(ctx, conf)
 }
+func (f *ConfigurationApiHandler) RoutePostStateArchive(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.StateArchive{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostStateArchive(ctx, conf)
+}
 
 func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -79,6 +92,16 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/state/export"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/state/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/state/export",
+				srv.RouteGetStateArchive,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert"),
 			api.authorize(http.MethodGet, "/api/v1/ngalert"),
@@ -99,5 +122,15 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/state/import"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/state/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/state/import",
+				srv.RoutePostStateArchive,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -19,12 +19,23 @@ import (
 )
 
 type TestingApi interface {
+	BacktestCompareConfig(*contextmodel.ReqContext) response.Response
 	BacktestConfig(*contextmodel.ReqContext) response.Response
+	BacktestTimelineConfig(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
+	RouteTestNotificationRoute(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 }
 
+func (f *TestingApiHandler) BacktestCompareConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestCompareConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBacktestCompareConfig(ctx, conf)
+}
 func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.BacktestConfig{}
@@ -33,6 +44,14 @@ func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) respons
 	}
 	return f.handleBacktestConfig(ctx, conf)
 }
+func (f *TestingApiHandler) BacktestTimelineConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBacktestTimelineConfig(ctx, conf)
+}
 func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EvalQueriesPayload{}
@@ -41,6 +60,14 @@ func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) respo
 	}
 	return f.handleRouteEvalQueries(ctx, conf)
 }
+func (f *TestingApiHandler) RouteTestNotificationRoute(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.TestNotificationRoutePayload{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteTestNotificationRoute(ctx, conf)
+}
 func (f *TestingApiHandler) RouteTestRuleConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -62,6 +89,16 @@ func (f *TestingApiHandler) RouteTestRuleGrafanaConfig(ctx *contextmodel.ReqCont
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/compare"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/compare"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/compare",
+				srv.BacktestCompareConfig,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/backtest"),
 			api.authorize(http.MethodPost, "/api/v1/rule/backtest"),
@@ -72,6 +109,16 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/timeline"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/timeline"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/timeline",
+				srv.BacktestTimelineConfig,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/eval"),
 			api.authorize(http.MethodPost, "/api/v1/eval"),
@@ -82,6 +129,16 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/route/test"),
+			api.authorize(http.MethodPost, "/api/v1/route/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/route/test",
+				srv.RouteTestNotificationRoute,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/test/{DatasourceUID}"),
 			api.authorize(http.MethodPost, "/api/v1/rule/test/{DatasourceUID}"),
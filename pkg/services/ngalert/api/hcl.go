@@ -0,0 +1,546 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting/file"
+)
+
+// hclBlock is a block of a HCL document, such as a Terraform resource or one of its nested blocks.
+// Attributes are rendered in the order they were added, followed by the nested blocks.
+type hclBlock struct {
+	Type       string
+	Labels     []string
+	Attributes []hclAttribute
+	Blocks     []*hclBlock
+}
+
+type hclAttribute struct {
+	Name  string
+	Value interface{}
+}
+
+// hclExpression is rendered as is, without quoting. It is used for function calls such as jsonencode.
+type hclExpression string
+
+func newHCLBlock(typ string, labels ...string) *hclBlock {
+	return &hclBlock{Type: typ, Labels: labels}
+}
+
+func (b *hclBlock) attr(name string, value interface{}) *hclBlock {
+	b.Attributes = append(b.Attributes, hclAttribute{Name: name, Value: value})
+	return b
+}
+
+func (b *hclBlock) block(child *hclBlock) *hclBlock {
+	b.Blocks = append(b.Blocks, child)
+	return b
+}
+
+func (b *hclBlock) write(buf *bytes.Buffer, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	buf.WriteString(prefix)
+	buf.WriteString(b.Type)
+	for _, label := range b.Labels {
+		buf.WriteString(" ")
+		buf.WriteString(hclQuote(label))
+	}
+	buf.WriteString(" {\n")
+
+	// Like terraform fmt, align the equals signs of consecutive attributes. A multi-line value ends the group.
+	values := make([]string, len(b.Attributes))
+	for i, a := range b.Attributes {
+		values[i] = hclValue(a.Value, indent+1)
+	}
+	for start := 0; start < len(b.Attributes); {
+		end := start
+		for end < len(b.Attributes)-1 && !strings.Contains(values[end], "\n") {
+			end++
+		}
+		width := 0
+		for i := start; i <= end; i++ {
+			if l := len(hclKey(b.Attributes[i].Name)); l > width {
+				width = l
+			}
+		}
+		for i := start; i <= end; i++ {
+			key := hclKey(b.Attributes[i].Name)
+			fmt.Fprintf(buf, "%s  %s%s = %s\n", prefix, key, strings.Repeat(" ", width-len(key)), values[i])
+		}
+		start = end + 1
+	}
+
+	for i, child := range b.Blocks {
+		if i > 0 || len(b.Attributes) > 0 {
+			buf.WriteString("\n")
+		}
+		child.write(buf, indent+1)
+	}
+	buf.WriteString(prefix)
+	buf.WriteString("}\n")
+}
+
+var hclIdentifierRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func hclKey(key string) string {
+	if hclIdentifierRE.MatchString(key) {
+		return key
+	}
+	return hclQuote(key)
+}
+
+// hclQuote returns s as a quoted HCL string. Template sequences are escaped so that the value is taken literally.
+func hclQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '$', '%':
+			sb.WriteByte(c)
+			if i+1 < len(s) && s[i+1] == '{' {
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func hclValue(v interface{}, indent int) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case hclExpression:
+		return string(t)
+	case string:
+		return hclQuote(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case json.Number:
+		return t.String()
+	case []string:
+		items := make([]interface{}, 0, len(t))
+		for _, s := range t {
+			items = append(items, s)
+		}
+		return hclValue(items, indent)
+	case []interface{}:
+		items := make([]string, 0, len(t))
+		multiline := false
+		for _, item := range t {
+			s := hclValue(item, indent+1)
+			multiline = multiline || strings.Contains(s, "\n")
+			items = append(items, s)
+		}
+		if !multiline {
+			return "[" + strings.Join(items, ", ") + "]"
+		}
+		prefix := strings.Repeat("  ", indent)
+		return "[\n" + prefix + "  " + strings.Join(items, ",\n"+prefix+"  ") + ",\n" + prefix + "]"
+	case map[string]string:
+		m := make(map[string]interface{}, len(t))
+		for k, s := range t {
+			m[k] = s
+		}
+		return hclValue(m, indent)
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(t))
+		width := 0
+		for k := range t {
+			keys = append(keys, k)
+			if l := len(hclKey(k)); l > width {
+				width = l
+			}
+		}
+		sort.Strings(keys)
+		prefix := strings.Repeat("  ", indent)
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, k := range keys {
+			key := hclKey(k)
+			fmt.Fprintf(&sb, "%s  %s%s = %s\n", prefix, key, strings.Repeat(" ", width-len(key)), hclValue(t[k], indent+1))
+		}
+		sb.WriteString(prefix)
+		sb.WriteString("}")
+		return sb.String()
+	default:
+		return hclQuote(fmt.Sprintf("%v", t))
+	}
+}
+
+// hclResourceNames generates unique Terraform resource names from the names of the exported objects.
+type hclResourceNames map[string]int
+
+var hclInvalidNameCharsRE = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func (n hclResourceNames) next(kind, name string) string {
+	sanitized := strings.Trim(hclInvalidNameCharsRE.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	result := kind
+	if sanitized != "" {
+		result = kind + "_" + sanitized
+	}
+	n[result]++
+	if count := n[result]; count > 1 {
+		result = fmt.Sprintf("%s_%d", result, count)
+	}
+	return result
+}
+
+// AlertingFileExportToHCL converts the provisioning file export to Terraform resources of the Grafana provider.
+func AlertingFileExportToHCL(export file.AlertingFileExport) ([]byte, error) {
+	names := hclResourceNames{}
+	var resources []*hclBlock
+	for _, group := range export.Groups {
+		resources = append(resources, ruleGroupToHCL(group, names.next("rule_group", group.Folder+"_"+group.Name)))
+	}
+	for _, cp := range export.ContactPoints {
+		resources = append(resources, contactPointToHCL(cp, names.next("contact_point", cp.Name)))
+	}
+	for _, policy := range export.Policies {
+		resources = append(resources, notificationPolicyToHCL(policy, names.next("notification_policy", "")))
+	}
+	for _, mt := range export.MuteTimes {
+		r, err := muteTimingToHCL(mt, names.next("mute_timing", mt.Name))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+	for _, tmpl := range export.Templates {
+		resources = append(resources, newHCLBlock("resource", "grafana_message_template", names.next("message_template", tmpl.Name)).
+			attr("name", tmpl.Name).
+			attr("template", tmpl.Template))
+	}
+
+	var buf bytes.Buffer
+	for i, r := range resources {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		r.write(&buf, 0)
+	}
+	return buf.Bytes(), nil
+}
+
+func ruleGroupToHCL(group file.AlertRuleGroupExport, name string) *hclBlock {
+	b := newHCLBlock("resource", "grafana_rule_group", name).
+		attr("name", group.Name).
+		attr("folder_uid", group.FolderUID).
+		attr("interval_seconds", int64(time.Duration(group.Interval).Seconds()))
+	for _, rule := range group.Rules {
+		r := newHCLBlock("rule").
+			attr("name", rule.Title).
+			attr("condition", rule.Condition)
+		for _, query := range rule.Data {
+			q := newHCLBlock("data").attr("ref_id", query.RefID)
+			if query.QueryType != "" {
+				q.attr("query_type", query.QueryType)
+			}
+			q.block(newHCLBlock("relative_time_range").
+				attr("from", int64(time.Duration(query.RelativeTimeRange.From).Seconds())).
+				attr("to", int64(time.Duration(query.RelativeTimeRange.To).Seconds())))
+			q.attr("datasource_uid", query.DatasourceUID)
+			q.attr("model", hclExpression("jsonencode("+hclValue(query.Model, 3)+")"))
+			r.block(q)
+		}
+		r.attr("no_data_state", string(rule.NoDataState)).
+			attr("exec_err_state", string(rule.ExecErrState)).
+			attr("for", rule.For.String())
+		if len(rule.Annotations) > 0 {
+			r.attr("annotations", rule.Annotations)
+		}
+		if len(rule.Labels) > 0 {
+			r.attr("labels", rule.Labels)
+		}
+		r.attr("is_paused", rule.IsPaused)
+		if settings := rule.NotificationSettings; settings != nil {
+			ns := newHCLBlock("notification_settings").attr("contact_point", settings.Receiver)
+			if len(settings.GroupBy) > 0 {
+				ns.attr("group_by", settings.GroupBy)
+			}
+			for _, timing := range []struct {
+				name  string
+				value *model.Duration
+			}{
+				{"group_wait", settings.GroupWait},
+				{"group_interval", settings.GroupInterval},
+				{"repeat_interval", settings.RepeatInterval},
+			} {
+				if timing.value != nil {
+					ns.attr(timing.name, timing.value.String())
+				}
+			}
+			if len(settings.MuteTimeIntervals) > 0 {
+				ns.attr("mute_timings", settings.MuteTimeIntervals)
+			}
+			r.block(ns)
+		}
+		b.block(r)
+	}
+	return b
+}
+
+func contactPointToHCL(cp file.ContactPointExport, name string) *hclBlock {
+	b := newHCLBlock("resource", "grafana_contact_point", name).attr("name", cp.Name)
+	for _, receiver := range cp.Receivers {
+		typ := contactPointIntegrationType(receiver.Type)
+		integration := newHCLBlock(typ)
+		attrs := make(map[string]string, len(receiver.Settings))
+		names := make([]string, 0, len(receiver.Settings))
+		for k := range receiver.Settings {
+			name := contactPointAttributeName(typ, k)
+			attrs[name] = k
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			k := attrs[name]
+			value := receiver.Settings[k]
+			if s, ok := value.(string); ok && typ == "email" && k == "addresses" {
+				value = splitEmailAddresses(s)
+			}
+			integration.attr(name, value)
+		}
+		integration.attr("uid", receiver.UID).
+			attr("disable_resolve_message", receiver.DisableResolveMessage)
+		b.block(integration)
+	}
+	return b
+}
+
+// contactPointIntegrationType returns the name of the block of the integration in the grafana_contact_point resource.
+func contactPointIntegrationType(typ string) string {
+	typ = strings.ToLower(typ)
+	if typ == "prometheus-alertmanager" {
+		return "alertmanager"
+	}
+	return typ
+}
+
+// contactPointAttributes are the names of integration settings in the grafana_contact_point resource
+// which are not the settings key in snake case, by integration.
+var contactPointAttributes = map[string]map[string]string{
+	"dingding": {"msgType": "message_type"},
+	"kafka": {
+		"kafkaRestProxy": "rest_proxy_url",
+		"kafkaTopic":     "topic",
+		"kafkaClusterId": "cluster_id",
+	},
+	"opsgenie": {"apiUrl": "url"},
+	"sensugo":  {"apikey": "api_key"},
+	"teams":    {"sectiontitle": "section_title"},
+	"telegram": {
+		"bottoken": "token",
+		"chatid":   "chat_id",
+	},
+	"webex": {"bot_token": "token"},
+	"wecom": {
+		"msgtype": "msg_type",
+		"touser":  "to_user",
+	},
+}
+
+// contactPointAttributeName returns the name of the attribute of an integration setting.
+func contactPointAttributeName(integration, key string) string {
+	if name, ok := contactPointAttributes[integration][key]; ok {
+		return name
+	}
+	return camelToSnake(key)
+}
+
+func splitEmailAddresses(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == ',' || r == '\n'
+	})
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func camelToSnake(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func notificationPolicyToHCL(policy file.NotificationPolicyExport, name string) *hclBlock {
+	b := newHCLBlock("resource", "grafana_notification_policy", name).
+		attr("contact_point", policy.Receiver)
+	groupBy := policy.GroupByStr
+	if groupBy == nil {
+		groupBy = []string{}
+	}
+	b.attr("group_by", groupBy)
+	routeTimingsToHCL(b, &policy.RouteExport)
+	for _, route := range policy.Routes {
+		b.block(policyToHCL(route))
+	}
+	return b
+}
+
+func policyToHCL(route *file.RouteExport) *hclBlock {
+	b := newHCLBlock("policy")
+	if route.Receiver != "" {
+		b.attr("contact_point", route.Receiver)
+	}
+	if len(route.GroupByStr) > 0 {
+		b.attr("group_by", route.GroupByStr)
+	}
+	if route.Continue {
+		b.attr("continue", true)
+	}
+	if len(route.MuteTimeIntervals) > 0 {
+		b.attr("mute_timings", route.MuteTimeIntervals)
+	}
+	routeTimingsToHCL(b, route)
+
+	for _, m := range routeMatchers(route) {
+		b.block(newHCLBlock("matcher").
+			attr("label", m[0]).
+			attr("match", m[1]).
+			attr("value", m[2]))
+	}
+	for _, child := range route.Routes {
+		b.block(policyToHCL(child))
+	}
+	return b
+}
+
+func routeTimingsToHCL(b *hclBlock, route *file.RouteExport) {
+	for _, timing := range []struct {
+		name  string
+		value *model.Duration
+	}{
+		{"group_wait", route.GroupWait},
+		{"group_interval", route.GroupInterval},
+		{"repeat_interval", route.RepeatInterval},
+	} {
+		if timing.value != nil {
+			b.attr(timing.name, timing.value.String())
+		}
+	}
+}
+
+// routeMatchers returns all matchers of the route in the [name, operator, value] form, regardless of the field they are defined in.
+func routeMatchers(route *file.RouteExport) []file.ObjectMatcher {
+	var result []file.ObjectMatcher
+	keys := make([]string, 0, len(route.Match))
+	for k := range route.Match {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		result = append(result, file.ObjectMatcher{k, "=", route.Match[k]})
+	}
+	keys = keys[:0]
+	for k := range route.MatchRE {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// MarshalYAML returns the regular expression as it was written, without the anchors added when it was parsed.
+		original, _ := route.MatchRE[k].MarshalYAML()
+		result = append(result, file.ObjectMatcher{k, "=~", fmt.Sprintf("%v", original)})
+	}
+	for _, m := range route.Matchers {
+		result = append(result, file.ObjectMatcher{m.Name, m.Type.String(), m.Value})
+	}
+	result = append(result, route.ObjectMatchers...)
+	return result
+}
+
+func muteTimingToHCL(mt file.MuteTimeIntervalExport, name string) (*hclBlock, error) {
+	b := newHCLBlock("resource", "grafana_mute_timing", name).attr("name", mt.Name)
+	for _, interval := range mt.TimeIntervals {
+		i := newHCLBlock("intervals")
+		ranges := []struct {
+			name   string
+			values func() ([]string, error)
+		}{
+			{"weekdays", func() ([]string, error) { return marshalTextRanges(interval.Weekdays) }},
+			{"days_of_month", func() ([]string, error) { return marshalTextRanges(interval.DaysOfMonth) }},
+			{"months", func() ([]string, error) { return marshalTextRanges(interval.Months) }},
+			{"years", func() ([]string, error) { return marshalTextRanges(interval.Years) }},
+		}
+		for _, r := range ranges {
+			values, err := r.values()
+			if err != nil {
+				return nil, fmt.Errorf("mute timing '%s': %w", mt.Name, err)
+			}
+			if len(values) > 0 {
+				i.attr(r.name, values)
+			}
+		}
+		if interval.Location != nil && interval.Location.Location != nil {
+			i.attr("location", interval.Location.String())
+		}
+		for _, tr := range interval.Times {
+			i.block(newHCLBlock("times").
+				attr("start", fmt.Sprintf("%02d:%02d", tr.StartMinute/60, tr.StartMinute%60)).
+				attr("end", fmt.Sprintf("%02d:%02d", tr.EndMinute/60, tr.EndMinute%60)))
+		}
+		b.block(i)
+	}
+	return b, nil
+}
+
+type textRange interface {
+	timeinterval.WeekdayRange | timeinterval.DayOfMonthRange | timeinterval.MonthRange | timeinterval.YearRange
+	MarshalText() ([]byte, error)
+}
+
+func marshalTextRanges[T textRange](ranges []T) ([]string, error) {
+	result := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		text, err := r.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, string(text))
+	}
+	return result, nil
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting/file"
+)
+
+func TestHCLQuote(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "simple", expected: `"simple"`},
+		{input: `with "quotes" and \ backslash`, expected: `"with \"quotes\" and \\ backslash"`},
+		{input: "multi\nline\ttext", expected: `"multi\nline\ttext"`},
+		{input: "{{ $labels.instance }} is ${down}", expected: `"{{ $labels.instance }} is $${down}"`},
+		{input: "%{ if true }100%", expected: `"%%{ if true }100%"`},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, hclQuote(tc.input))
+	}
+}
+
+func TestHCLResourceNames(t *testing.T) {
+	names := hclResourceNames{}
+	require.Equal(t, "contact_point_my_receiver", names.next("contact_point", "My Receiver"))
+	require.Equal(t, "contact_point_my_receiver_2", names.next("contact_point", "my-receiver"))
+	require.Equal(t, "contact_point", names.next("contact_point", "!!!"))
+	require.Equal(t, "mute_timing_my_receiver", names.next("mute_timing", "My Receiver"))
+}
+
+func TestAlertingFileExportToHCL(t *testing.T) {
+	t.Run("contact point integrations are converted to blocks of the provider", func(t *testing.T) {
+		export := file.AlertingFileExport{
+			ContactPoints: []file.ContactPointExport{{
+				Name: "team",
+				Receivers: []file.ReceiverExport{
+					{
+						UID:      "email-uid",
+						Type:     "email",
+						Settings: map[string]interface{}{"addresses": "a@example.com;b@example.com", "singleEmail": true},
+					},
+					{
+						UID:                   "am-uid",
+						Type:                  "prometheus-alertmanager",
+						Settings:              map[string]interface{}{"url": "http://localhost:9093"},
+						DisableResolveMessage: true,
+					},
+				},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, `resource "grafana_contact_point" "contact_point_team" {
+  name = "team"
+
+  email {
+    addresses               = ["a@example.com", "b@example.com"]
+    single_email            = true
+    uid                     = "email-uid"
+    disable_resolve_message = false
+  }
+
+  alertmanager {
+    url                     = "http://localhost:9093"
+    uid                     = "am-uid"
+    disable_resolve_message = true
+  }
+}
+`, string(b))
+	})
+
+	t.Run("integration settings are converted to attributes of the provider", func(t *testing.T) {
+		export := file.AlertingFileExport{
+			ContactPoints: []file.ContactPointExport{{
+				Name: "chat",
+				Receivers: []file.ReceiverExport{
+					{
+						UID:      "telegram-uid",
+						Type:     "telegram",
+						Settings: map[string]interface{}{"bottoken": "[REDACTED]", "chatid": "-100123", "parse_mode": "HTML"},
+					},
+					{
+						UID:  "kafka-uid",
+						Type: "kafka",
+						Settings: map[string]interface{}{
+							"kafkaRestProxy": "http://localhost:8082",
+							"kafkaTopic":     "alerts",
+							"apiVersion":     "v3",
+							"kafkaClusterId": "cluster",
+						},
+					},
+					{
+						UID:      "opsgenie-uid",
+						Type:     "opsgenie",
+						Settings: map[string]interface{}{"apiUrl": "https://api.opsgenie.com/v2/alerts", "autoClose": true},
+					},
+				},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, `resource "grafana_contact_point" "contact_point_chat" {
+  name = "chat"
+
+  telegram {
+    chat_id                 = "-100123"
+    parse_mode              = "HTML"
+    token                   = "[REDACTED]"
+    uid                     = "telegram-uid"
+    disable_resolve_message = false
+  }
+
+  kafka {
+    api_version             = "v3"
+    cluster_id              = "cluster"
+    rest_proxy_url          = "http://localhost:8082"
+    topic                   = "alerts"
+    uid                     = "kafka-uid"
+    disable_resolve_message = false
+  }
+
+  opsgenie {
+    auto_close              = true
+    url                     = "https://api.opsgenie.com/v2/alerts"
+    uid                     = "opsgenie-uid"
+    disable_resolve_message = false
+  }
+}
+`, string(b))
+	})
+
+	t.Run("all kinds of route matchers are converted to matcher blocks", func(t *testing.T) {
+		var re amConfig.Regexp
+		require.NoError(t, json.Unmarshal([]byte(`"b.*"`), &re))
+		matcher, err := labels.NewMatcher(labels.MatchNotEqual, "c", "3")
+		require.NoError(t, err)
+		export := file.AlertingFileExport{
+			Policies: []file.NotificationPolicyExport{{
+				RouteExport: file.RouteExport{
+					Receiver: "default",
+					Routes: []*file.RouteExport{{
+						Match:          map[string]string{"a": "1"},
+						MatchRE:        amConfig.MatchRegexps{"b": re},
+						Matchers:       amConfig.Matchers{matcher},
+						ObjectMatchers: []file.ObjectMatcher{{"d", "!~", "4"}},
+					}},
+				},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, `resource "grafana_notification_policy" "notification_policy" {
+  contact_point = "default"
+  group_by      = []
+
+  policy {
+    matcher {
+      label = "a"
+      match = "="
+      value = "1"
+    }
+
+    matcher {
+      label = "b"
+      match = "=~"
+      value = "b.*"
+    }
+
+    matcher {
+      label = "c"
+      match = "!="
+      value = "3"
+    }
+
+    matcher {
+      label = "d"
+      match = "!~"
+      value = "4"
+    }
+  }
+}
+`, string(b))
+	})
+
+	t.Run("mute timing intervals are converted to interval blocks", func(t *testing.T) {
+		location, err := time.LoadLocation("UTC")
+		require.NoError(t, err)
+		export := file.AlertingFileExport{
+			MuteTimes: []file.MuteTimeIntervalExport{{
+				MuteTimeInterval: amConfig.MuteTimeInterval{
+					Name: "weekends",
+					TimeIntervals: []timeinterval.TimeInterval{{
+						Times:       []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 1440}},
+						Weekdays:    []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}}, {InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}},
+						DaysOfMonth: []timeinterval.DayOfMonthRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 1, End: 7}}},
+						Location:    &timeinterval.Location{Location: location},
+					}},
+				},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, `resource "grafana_mute_timing" "mute_timing_weekends" {
+  name = "weekends"
+
+  intervals {
+    weekdays      = ["saturday", "sunday"]
+    days_of_month = ["1:7"]
+    location      = "UTC"
+
+    times {
+      start = "01:00"
+      end   = "24:00"
+    }
+  }
+}
+`, string(b))
+	})
+	t.Run("notification settings of rules are converted to notification_settings blocks", func(t *testing.T) {
+		repeatInterval := model.Duration(4 * time.Hour)
+		export := file.AlertingFileExport{
+			Groups: []file.AlertRuleGroupExport{{
+				Name:      "group",
+				FolderUID: "folder-uid",
+				Interval:  model.Duration(time.Minute),
+				Rules: []file.AlertRuleExport{{
+					Title:        "rule",
+					Condition:    "A",
+					NoDataState:  "NoData",
+					ExecErrState: "Alerting",
+					For:          model.Duration(5 * time.Minute),
+					NotificationSettings: &file.NotificationSettingsExport{
+						Receiver:          "team",
+						GroupBy:           []string{"alertname", "instance"},
+						RepeatInterval:    &repeatInterval,
+						MuteTimeIntervals: []string{"weekends"},
+					},
+				}},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, `resource "grafana_rule_group" "rule_group_group" {
+  name             = "group"
+  folder_uid       = "folder-uid"
+  interval_seconds = 60
+
+  rule {
+    name           = "rule"
+    condition      = "A"
+    no_data_state  = "NoData"
+    exec_err_state = "Alerting"
+    for            = "5m"
+    is_paused      = false
+
+    notification_settings {
+      contact_point   = "team"
+      group_by        = ["alertname", "instance"]
+      repeat_interval = "4h"
+      mute_timings    = ["weekends"]
+    }
+  }
+}
+`, string(b))
+	})
+}
@@ -14,6 +14,9 @@ type RuleStore interface {
 	GetNamespaceByTitle(context.Context, string, int64, *user.SignedInUser, bool) (*folder.Folder, error)
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) error
 	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) error
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) error
+	ListAlertRuleVersions(ctx context.Context, query *ngmodels.ListAlertRuleVersionsQuery) error
+	GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) error
 
 	// InsertAlertRules will insert all alert rules passed into the function
 	// and return the map of uuid to id.
@@ -92,6 +92,26 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetContactPointsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetPolicyTreeExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetPolicyTreeExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimingsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetTemplatesExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetTemplatesExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertingConfigExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertingConfigExport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
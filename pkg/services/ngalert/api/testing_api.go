@@ -32,3 +32,15 @@ func (f *TestingApiHandler) handleRouteEvalQueries(c *contextmodel.ReqContext, b
 func (f *TestingApiHandler) handleBacktestConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
 	return f.svc.BacktestAlertRule(ctx, conf)
 }
+
+func (f *TestingApiHandler) handleBacktestTimelineConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestConfig) response.Response {
+	return f.svc.BacktestAlertRuleTimeline(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleBacktestCompareConfig(ctx *contextmodel.ReqContext, conf apimodels.BacktestCompareConfig) response.Response {
+	return f.svc.BacktestCompareAlertRules(ctx, conf)
+}
+
+func (f *TestingApiHandler) handleRouteTestNotificationRoute(ctx *contextmodel.ReqContext, body apimodels.TestNotificationRoutePayload) response.Response {
+	return f.svc.RouteTestNotificationRoute(ctx, body)
+}
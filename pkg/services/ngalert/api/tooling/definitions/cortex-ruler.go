@@ -260,12 +260,13 @@ func (c *GettableRuleGroupConfig) validate() error {
 }
 
 type ApiRuleNode struct {
-	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
-	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
-	Expr        string            `yaml:"expr" json:"expr"`
-	For         *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor *model.Duration   `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 type RuleType int
@@ -368,31 +369,61 @@ const (
 
 // swagger:model
 type PostableGrafanaRule struct {
-	Title        string              `json:"title" yaml:"title"`
-	Condition    string              `json:"condition" yaml:"condition"`
-	Data         []models.AlertQuery `json:"data" yaml:"data"`
-	UID          string              `json:"uid" yaml:"uid"`
-	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
-	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
-	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	Title                string                         `json:"title" yaml:"title"`
+	Condition            string                         `json:"condition" yaml:"condition"`
+	Data                 []models.AlertQuery            `json:"data" yaml:"data"`
+	UID                  string                         `json:"uid" yaml:"uid"`
+	NoDataState          NoDataState                    `json:"no_data_state" yaml:"no_data_state"`
+	ExecErrState         ExecutionErrorState            `json:"exec_err_state" yaml:"exec_err_state"`
+	IsPaused             *bool                          `json:"is_paused" yaml:"is_paused"`
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
+	// UIDs of the rules of the same group that the rule depends on. The notifications of the rule are suppressed while any of them is firing.
+	// example: ["database-down"]
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+}
+
+// AlertRuleNotificationSettings send the alerts of a rule directly to a contact point, bypassing the notification policy tree.
+// Options that are not set are inherited from the default notification policy.
+// swagger:model
+type AlertRuleNotificationSettings struct {
+	// Name of the contact point the alerts are sent to.
+	// required: true
+	// example: grafana-default-email
+	Receiver string `json:"receiver" yaml:"receiver"`
+	// Labels the alerts are grouped by. The special value '...' groups alerts by all labels.
+	// example: ["alertname", "grafana_folder"]
+	GroupBy []string `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	// example: 30s
+	GroupWait *model.Duration `json:"group_wait,omitempty" yaml:"group_wait,omitempty"`
+	// example: 5m
+	GroupInterval *model.Duration `json:"group_interval,omitempty" yaml:"group_interval,omitempty"`
+	// example: 4h
+	RepeatInterval *model.Duration `json:"repeat_interval,omitempty" yaml:"repeat_interval,omitempty"`
+	// Names of the mute timings during which no notifications are sent.
+	// example: ["weekends"]
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty" yaml:"mute_time_intervals,omitempty"`
 }
 
 // swagger:model
 type GettableGrafanaRule struct {
-	ID              int64               `json:"id" yaml:"id"`
-	OrgID           int64               `json:"orgId" yaml:"orgId"`
-	Title           string              `json:"title" yaml:"title"`
-	Condition       string              `json:"condition" yaml:"condition"`
-	Data            []models.AlertQuery `json:"data" yaml:"data"`
-	Updated         time.Time           `json:"updated" yaml:"updated"`
-	IntervalSeconds int64               `json:"intervalSeconds" yaml:"intervalSeconds"`
-	Version         int64               `json:"version" yaml:"version"`
-	UID             string              `json:"uid" yaml:"uid"`
-	NamespaceUID    string              `json:"namespace_uid" yaml:"namespace_uid"`
-	NamespaceID     int64               `json:"namespace_id" yaml:"namespace_id"`
-	RuleGroup       string              `json:"rule_group" yaml:"rule_group"`
-	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
-	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
-	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
-	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	ID                   int64                          `json:"id" yaml:"id"`
+	OrgID                int64                          `json:"orgId" yaml:"orgId"`
+	Title                string                         `json:"title" yaml:"title"`
+	Condition            string                         `json:"condition" yaml:"condition"`
+	Data                 []models.AlertQuery            `json:"data" yaml:"data"`
+	Updated              time.Time                      `json:"updated" yaml:"updated"`
+	IntervalSeconds      int64                          `json:"intervalSeconds" yaml:"intervalSeconds"`
+	Version              int64                          `json:"version" yaml:"version"`
+	UID                  string                         `json:"uid" yaml:"uid"`
+	NamespaceUID         string                         `json:"namespace_uid" yaml:"namespace_uid"`
+	NamespaceID          int64                          `json:"namespace_id" yaml:"namespace_id"`
+	RuleGroup            string                         `json:"rule_group" yaml:"rule_group"`
+	NoDataState          NoDataState                    `json:"no_data_state" yaml:"no_data_state"`
+	ExecErrState         ExecutionErrorState            `json:"exec_err_state" yaml:"exec_err_state"`
+	Provenance           Provenance                     `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	IsPaused             bool                           `json:"is_paused" yaml:"is_paused"`
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
+	// UIDs of the rules of the same group that the rule depends on. The notifications of the rule are suppressed while any of them is firing.
+	// example: ["database-down"]
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
@@ -0,0 +1,125 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetGrafanaRuleVersions
+//
+// List versions of a rule, latest first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionsResponse
+//       404: NotFound
+
+// swagger:route Get /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetGrafanaRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionDiff
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route POST /api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostGrafanaRuleVersionRestore
+//
+// Restore the definition of a rule from a previous version
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: NotFound
+//       409: Failure
+
+// swagger:parameters RouteGetGrafanaRuleVersions RouteGetGrafanaRuleVersionsDiff RoutePostGrafanaRuleVersionRestore
+type RuleUIDReference struct {
+	// in:path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetGrafanaRuleVersionsDiff
+type RuleVersionsDiffParams struct {
+	// Version to compare from
+	// in:query
+	// required:true
+	From int64 `json:"from"`
+	// Version to compare to. Defaults to the current version of the rule
+	// in:query
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RoutePostGrafanaRuleVersionRestore
+type RuleVersionRestoreParams struct {
+	// in:path
+	Version int64
+	// in:body
+	Body RestoreRuleVersionPayload
+}
+
+// swagger:model
+type RestoreRuleVersionPayload struct {
+	// Version is the current version of the rule. The restore is rejected if the rule was changed in the meantime.
+	// required:true
+	Version int64 `json:"version"`
+}
+
+// swagger:model
+type RuleVersionsResponse []GettableRuleVersion
+
+// swagger:model
+type GettableRuleVersion struct {
+	Version       int64 `json:"version"`
+	ParentVersion int64 `json:"parentVersion"`
+	// RestoredFrom is the version that this version was restored from, if any.
+	RestoredFrom int64     `json:"restoredFrom,omitempty"`
+	Created      time.Time `json:"created"`
+	// CreatedBy is the login of the user who made the change. Empty if the rule was changed by file provisioning.
+	CreatedBy       string              `json:"createdBy,omitempty"`
+	Title           string              `json:"title"`
+	Condition       string              `json:"condition"`
+	Data            []models.AlertQuery `json:"data"`
+	For             model.Duration      `json:"for"`
+	KeepFiringFor   model.Duration      `json:"keep_firing_for,omitempty"`
+	IntervalSeconds int64               `json:"intervalSeconds"`
+	NamespaceUID    string              `json:"namespace_uid"`
+	RuleGroup       string              `json:"rule_group"`
+	NoDataState     NoDataState         `json:"no_data_state"`
+	ExecErrState    ExecutionErrorState `json:"exec_err_state"`
+	IsPaused        bool                `json:"is_paused"`
+	Labels          map[string]string   `json:"labels,omitempty"`
+	Annotations     map[string]string   `json:"annotations,omitempty"`
+	// NotificationSettings of the rule, if its alerts bypass the notification policy tree.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty"`
+	// DependsOn contains the UIDs of the rules of the same group that the rule depends on.
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// swagger:model
+type RuleVersionDiff struct {
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Changes []RuleVersionChange `json:"changes"`
+}
+
+// swagger:model
+type RuleVersionChange struct {
+	// Field is the path of the changed field, such as "title", "for", "labels.team" or "data.A".
+	Field string `json:"field"`
+	// Old is absent if the field was added.
+	Old interface{} `json:"old,omitempty"`
+	// New is absent if the field was removed.
+	New interface{} `json:"new,omitempty"`
+}
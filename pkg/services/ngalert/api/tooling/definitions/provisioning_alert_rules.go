@@ -24,6 +24,20 @@ import (
 //       200: AlertingFileExport
 //       404: description: Not found.
 
+// swagger:route GET /api/v1/provisioning/export provisioning stable RouteGetAlertingConfigExport
+//
+// Export all alert rules, contact points, notification policies, mute timings and templates in a single provisioning file.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       403: description: Not allowed to export the secure settings.
+
 // swagger:route GET /api/v1/provisioning/alert-rules/{UID} provisioning stable RouteGetAlertRule
 //
 // Get a specific alert rule by UID.
@@ -39,6 +53,7 @@ import (
 //     Produces:
 //     - application/json
 //     - application/yaml
+//     - text/hcl
 //     - text/yaml
 //
 //     Responses:
@@ -128,6 +143,8 @@ type ProvisionedAlertRule struct {
 	ExecErrState ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// example: 5m
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -136,6 +153,11 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// NotificationSettings send the alerts of the rule directly to a contact point, bypassing the notification policy tree.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notificationSettings,omitempty"`
+	// UIDs of the rules of the same group that the rule depends on. The notifications of the rule are suppressed while any of them is firing.
+	// example: ["database-down"]
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -153,6 +175,7 @@ type ProvisionedAlertRule struct {
 //     Produces:
 //     - application/json
 //     - application/yaml
+//     - text/hcl
 //     - text/yaml
 //
 //     Responses:
@@ -193,7 +216,7 @@ type AlertRuleGroupMetadata struct {
 	Interval int64 `json:"interval"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetPolicyTreeExport RouteGetMuteTimingsExport RouteGetTemplatesExport RouteGetAlertingConfigExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -201,13 +224,22 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file, either yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file, either yaml, json or hcl. Accept header can also be used, but the query parameter will take precedence.
 	// in: query
 	// required: false
 	// default: yaml
 	Format string `json:"format"`
 }
 
+// swagger:parameters RouteGetContactpointsExport RouteGetAlertingConfigExport
+type ExportSecretsQueryParams struct {
+	// Whether to export the secure settings of contact points in plain text instead of redacting them. Requires the alert.provisioning.secrets:read permission.
+	// in: query
+	// required: false
+	// default: false
+	Decrypt bool `json:"decrypt"`
+}
+
 // swagger:model
 type AlertRuleGroup struct {
 	Title     string                 `json:"title"`
@@ -4,10 +4,10 @@ import (
 	"fmt"
 
 	"github.com/grafana/alerting/logging"
-	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/alerting/receivers"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -19,6 +19,20 @@ import (
 //     Responses:
 //       200: ContactPoints
 
+// swagger:route GET /api/v1/provisioning/contact-points/export provisioning stable RouteGetContactpointsExport
+//
+// Export all contact points in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       403: description: Not allowed to export the secure settings.
+
 // swagger:route POST /api/v1/provisioning/contact-points provisioning stable RoutePostContactpoints
 //
 // Create a contact point.
@@ -58,7 +72,7 @@ type ContactPointUIDReference struct {
 	UID string
 }
 
-// swagger:parameters RouteGetContactpoints
+// swagger:parameters RouteGetContactpoints RouteGetContactpointsExport
 type ContactPointParams struct {
 	// Filter by name
 	// in: query
@@ -108,7 +122,7 @@ func (e *EmbeddedContactPoint) Valid(decryptFunc receivers.GetDecryptedValueFn)
 	if e.Settings == nil {
 		return fmt.Errorf("settings should not be empty")
 	}
-	factory, exists := alertingNotify.Factory(e.Type)
+	factory, exists := channels.Factory(e.Type)
 	if !exists {
 		return fmt.Errorf("unknown type '%s'", e.Type)
 	}
@@ -11,6 +11,19 @@ import (
 //     Responses:
 //       200: MuteTimings
 
+// swagger:route GET /api/v1/provisioning/mute-timings/export provisioning stable RouteGetMuteTimingsExport
+//
+// Export all mute timings in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
 // swagger:route GET /api/v1/provisioning/mute-timings/{name} provisioning stable RouteGetMuteTiming
 //
 // Get a mute timing.
@@ -8,6 +8,20 @@ package definitions
 //       200: Route
 //         description: The currently active notification routing tree
 
+// swagger:route GET /api/v1/provisioning/policies/export provisioning stable RouteGetPolicyTreeExport
+//
+// Export the notification policy tree in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       404: description: Not found.
+
 // swagger:route PUT /api/v1/provisioning/policies provisioning stable RoutePutPolicyTree
 //
 // Sets the notification policy tree.
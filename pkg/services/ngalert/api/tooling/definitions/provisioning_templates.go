@@ -8,6 +8,19 @@ package definitions
 //       200: NotificationTemplates
 //       404: description: Not found.
 
+// swagger:route GET /api/v1/provisioning/templates/export provisioning stable RouteGetTemplatesExport
+//
+// Export all notification templates in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
 // swagger:route GET /api/v1/provisioning/templates/{name} provisioning stable RouteGetTemplate
 //
 // Get a notification template.
@@ -0,0 +1,67 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/ngalert/state/export configuration RouteGetStateArchive
+//
+// Export the runtime state of Grafana Alerting of all organizations: alert instances, silences, notification log and Alertmanager configuration.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StateArchive
+//       500: Failure
+
+// swagger:route POST /api/v1/ngalert/state/import configuration RoutePostStateArchive
+//
+// Import the runtime state of Grafana Alerting from an archive created by the export. The state of every organization in the archive is replaced.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       500: Failure
+
+// swagger:parameters RoutePostStateArchive
+type StateArchiveParams struct {
+	// in:body
+	Body StateArchive
+}
+
+// StateArchive is a snapshot of the runtime state of Grafana Alerting.
+// swagger:model
+type StateArchive struct {
+	// Version of the archive format.
+	Version int               `json:"version"`
+	Created time.Time         `json:"created"`
+	Orgs    []OrgStateArchive `json:"orgs"`
+}
+
+// swagger:model
+type OrgStateArchive struct {
+	OrgID int64 `json:"orgId"`
+	// AlertmanagerConfiguration is the latest configuration of the Grafana Alertmanager. The secure settings of contact points
+	// are encrypted with the secret key of the Grafana instance that created the archive.
+	AlertmanagerConfiguration string `json:"alertmanagerConfiguration,omitempty"`
+	// Silences is the last snapshot of silences persisted by the Grafana Alertmanager.
+	Silences []byte `json:"silences,omitempty"`
+	// NotificationLog is the last snapshot of the notification log persisted by the Grafana Alertmanager.
+	NotificationLog []byte                  `json:"notificationLog,omitempty"`
+	AlertInstances  []ArchivedAlertInstance `json:"alertInstances"`
+}
+
+// swagger:model
+type ArchivedAlertInstance struct {
+	RuleUID        string            `json:"ruleUid"`
+	Labels         map[string]string `json:"labels"`
+	State          string            `json:"state"`
+	Reason         string            `json:"reason,omitempty"`
+	StateSince     time.Time         `json:"stateSince"`
+	StateEnd       time.Time         `json:"stateEnd"`
+	LastEvaluation time.Time         `json:"lastEvaluation"`
+}
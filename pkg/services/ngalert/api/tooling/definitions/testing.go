@@ -67,6 +67,47 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /api/v1/rule/backtest/timeline testing BacktestTimelineConfig
+//
+// Test rule and return the state timeline of each alert instance
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestTimeline
+
+// swagger:route Post /api/v1/rule/backtest/compare testing BacktestCompareConfig
+//
+// Test two versions of a rule over the same range of time and compare the results
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestComparison
+
+// swagger:route Post /api/v1/route/test testing RouteTestNotificationRoute
+//
+// Test which notification policies and contact points alerts are routed to, without sending any notification
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TestNotificationRouteResponse
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -132,6 +173,74 @@ func (p *TestRulePayload) Type() (backend Backend) {
 	return
 }
 
+// swagger:parameters RouteTestNotificationRoute
+type TestNotificationRouteRequest struct {
+	// in:body
+	Body TestNotificationRoutePayload
+}
+
+// swagger:model
+type TestNotificationRoutePayload struct {
+	// Labels of the alert to route. Either labels or rule_uid must be set.
+	Labels map[string]string `json:"labels,omitempty"`
+	// RuleUID is the UID of an alert rule whose current alert instances are routed.
+	RuleUID string `json:"rule_uid,omitempty"`
+	// Time at which mute timings and silences are evaluated. Defaults to the current time.
+	Time time.Time `json:"time,omitempty"`
+}
+
+func (p *TestNotificationRoutePayload) UnmarshalJSON(b []byte) error {
+	type plain TestNotificationRoutePayload
+	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
+		return err
+	}
+
+	if len(p.Labels) > 0 && p.RuleUID != "" {
+		return fmt.Errorf("cannot route both labels and the alerts of a rule")
+	}
+
+	if len(p.Labels) == 0 && p.RuleUID == "" {
+		return fmt.Errorf("missing either labels or rule_uid")
+	}
+
+	return nil
+}
+
+// swagger:model
+type TestNotificationRouteResponse struct {
+	Time   time.Time                `json:"time"`
+	Alerts []TestNotificationRouted `json:"alerts"`
+}
+
+// TestNotificationRouted describes how a single alert is routed.
+// swagger:model
+type TestNotificationRouted struct {
+	Labels map[string]string `json:"labels"`
+	// State of the alert instance. It is only set when the alerts of a rule are routed.
+	State string `json:"state,omitempty"`
+	// SilencedBy holds the IDs of the active silences that match the alert.
+	SilencedBy []string                     `json:"silenced_by"`
+	Routes     []TestNotificationRouteMatch `json:"routes"`
+}
+
+// TestNotificationRouteMatch is a notification policy that an alert is routed to, with the options it inherits from its parents.
+// swagger:model
+type TestNotificationRouteMatch struct {
+	// Path holds the indices of the nested policies that lead from the root policy to the matched policy.
+	Path           []int          `json:"path"`
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+	Continue       bool           `json:"continue"`
+	// MuteTimeIntervals are the mute timings of the policy.
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty"`
+	// ActiveMuteTimeIntervals are the mute timings of the policy that are active at the time of the test.
+	// The notifications of the policy are not sent if any of them is active.
+	ActiveMuteTimeIntervals []string `json:"active_mute_time_intervals,omitempty"`
+}
+
 // swagger:model
 type TestRuleResponse struct {
 	Alerts                promql.Vector          `json:"alerts"`
@@ -175,7 +284,7 @@ type ResponseDetails struct {
 	Msg string `json:"msg"`
 }
 
-// swagger:parameters BacktestConfig
+// swagger:parameters BacktestConfig BacktestTimelineConfig
 type BacktestConfigRequest struct {
 	// in:body
 	Body BacktestConfig
@@ -183,13 +292,22 @@ type BacktestConfigRequest struct {
 
 // swagger:model
 type BacktestConfig struct {
-	From     time.Time      `json:"from"`
-	To       time.Time      `json:"to"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	BacktestRule
+}
+
+// BacktestRule is the version of the rule to test.
+// swagger:model
+type BacktestRule struct {
 	Interval model.Duration `json:"interval,omitempty"`
 
 	Condition string              `json:"condition"`
 	Data      []models.AlertQuery `json:"data"` // TODO yuri. Create API model for AlertQuery
 	For       model.Duration      `json:"for,omitempty"`
+	// KeepFiringFor is how long an alert keeps firing after the condition stopped being met.
+	KeepFiringFor model.Duration `json:"keep_firing_for,omitempty"`
 
 	Title       string            `json:"title"`
 	Labels      map[string]string `json:"labels,omitempty"`
@@ -198,5 +316,88 @@ type BacktestConfig struct {
 	NoDataState NoDataState `json:"no_data_state"`
 }
 
+// swagger:parameters BacktestCompareConfig
+type BacktestCompareConfigRequest struct {
+	// in:body
+	Body BacktestCompareConfig
+}
+
+// swagger:model
+type BacktestCompareConfig struct {
+	From time.Time    `json:"from"`
+	To   time.Time    `json:"to"`
+	A    BacktestRule `json:"a"`
+	B    BacktestRule `json:"b"`
+}
+
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:model
+type BacktestTimeline struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Interval model.Duration `json:"interval"`
+	// Timestamps are the times of the evaluations. The states of each instance are aligned with them.
+	Timestamps []time.Time        `json:"timestamps"`
+	Summary    BacktestSummary    `json:"summary"`
+	Instances  []BacktestInstance `json:"instances"`
+	// Frames contains the timeline, the transitions and a summary per instance as data frames.
+	Frames []*data.Frame `json:"frames"`
+}
+
+// swagger:model
+type BacktestSummary struct {
+	Evaluations           int `json:"evaluations"`
+	Instances             int `json:"instances"`
+	Transitions           int `json:"transitions"`
+	FiringNotifications   int `json:"firingNotifications"`
+	ResolvedNotifications int `json:"resolvedNotifications"`
+	// Notifications is the number of notifications that would have been sent, not counting re-sends.
+	Notifications int `json:"notifications"`
+	// FlappingInstances is the number of instances that started firing more than once.
+	FlappingInstances int `json:"flappingInstances"`
+	Flaps             int `json:"flaps"`
+}
+
+// swagger:model
+type BacktestInstance struct {
+	Labels      map[string]string    `json:"labels"`
+	Annotations map[string]string    `json:"annotations,omitempty"`
+	Transitions []BacktestTransition `json:"transitions"`
+	// States contains the state of the instance at each evaluation, or null if the instance was not evaluated.
+	States                []*string      `json:"states"`
+	PendingDuration       model.Duration `json:"pendingDuration"`
+	FiringDuration        model.Duration `json:"firingDuration"`
+	FiringNotifications   int            `json:"firingNotifications"`
+	ResolvedNotifications int            `json:"resolvedNotifications"`
+	Flaps                 int            `json:"flaps"`
+}
+
+// swagger:model
+type BacktestTransition struct {
+	At          time.Time          `json:"at"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Labels      map[string]string  `json:"labels"`
+	Annotations map[string]string  `json:"annotations,omitempty"`
+	Values      map[string]float64 `json:"values,omitempty"`
+}
+
+// swagger:model
+type BacktestComparison struct {
+	A           BacktestTimeline             `json:"a"`
+	B           BacktestTimeline             `json:"b"`
+	Differences []BacktestInstanceDifference `json:"differences"`
+}
+
+// swagger:model
+type BacktestInstanceDifference struct {
+	Labels  map[string]string `json:"labels"`
+	OnlyInA bool              `json:"onlyInA,omitempty"`
+	OnlyInB bool              `json:"onlyInB,omitempty"`
+	// Evaluations is the number of evaluations at the same time that resulted in a different state.
+	Evaluations    int `json:"evaluations"`
+	NotificationsA int `json:"notificationsA"`
+	NotificationsB int `json:"notificationsB"`
+}
@@ -0,0 +1,136 @@
+package backtesting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Comparison is the outcome of backtesting two versions of an alert rule over the same range of time.
+type Comparison struct {
+	A *Result
+	B *Result
+	// Differences contains the instances whose timelines differ between the versions, sorted by labels.
+	Differences []InstanceDifference
+}
+
+// InstanceDifference describes how the timeline of an instance differs between two versions of a rule.
+// Instances are matched by their labels.
+type InstanceDifference struct {
+	Labels  data.Labels
+	OnlyInA bool
+	OnlyInB bool
+	// Evaluations is the number of evaluations at the same time that resulted in a different state.
+	Evaluations int
+	// NotificationsA and NotificationsB are the number of notifications sent for the instance by each version.
+	NotificationsA int
+	NotificationsB int
+}
+
+// Compare backtests two versions of a rule over the same range of time and returns both results and their differences.
+func (e *Engine) Compare(ctx context.Context, user *user.SignedInUser, a, b *models.AlertRule, from, to time.Time) (*Comparison, error) {
+	resultA, err := e.Run(ctx, user, a, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backtest the first version of the rule: %w", err)
+	}
+	resultB, err := e.Run(ctx, user, b, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backtest the second version of the rule: %w", err)
+	}
+	return &Comparison{
+		A:           resultA,
+		B:           resultB,
+		Differences: diffResults(resultA, resultB),
+	}, nil
+}
+
+func diffResults(a, b *Result) []InstanceDifference {
+	byLabelsB := make(map[string]*InstanceTimeline, len(b.Instances))
+	for _, instance := range b.Instances {
+		byLabelsB[instance.Labels.String()] = instance
+	}
+
+	var diffs []InstanceDifference
+	for _, instanceA := range a.Instances {
+		key := instanceA.Labels.String()
+		instanceB, ok := byLabelsB[key]
+		if !ok {
+			diffs = append(diffs, InstanceDifference{
+				Labels:         instanceA.Labels,
+				OnlyInA:        true,
+				NotificationsA: instanceA.FiringNotifications + instanceA.ResolvedNotifications,
+			})
+			continue
+		}
+		delete(byLabelsB, key)
+		diff := InstanceDifference{
+			Labels:         instanceA.Labels,
+			Evaluations:    countDifferentStates(a, instanceA, b, instanceB),
+			NotificationsA: instanceA.FiringNotifications + instanceA.ResolvedNotifications,
+			NotificationsB: instanceB.FiringNotifications + instanceB.ResolvedNotifications,
+		}
+		if diff.Evaluations > 0 || diff.NotificationsA != diff.NotificationsB {
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, instanceB := range byLabelsB {
+		diffs = append(diffs, InstanceDifference{
+			Labels:         instanceB.Labels,
+			OnlyInB:        true,
+			NotificationsB: instanceB.FiringNotifications + instanceB.ResolvedNotifications,
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Labels.String() < diffs[j].Labels.String()
+	})
+	return diffs
+}
+
+// countDifferentStates counts the evaluations that happened at the same time in both results
+// and where the instance was in a different state.
+func countDifferentStates(a *Result, instanceA *InstanceTimeline, b *Result, instanceB *InstanceTimeline) int {
+	statesB := make(map[time.Time]*EvaluatedState, len(b.Timestamps))
+	for idx, ts := range b.Timestamps {
+		statesB[ts] = instanceB.States[idx]
+	}
+	count := 0
+	for idx, ts := range a.Timestamps {
+		stateB, ok := statesB[ts]
+		if !ok || ts.IsZero() {
+			continue
+		}
+		stateA := instanceA.States[idx]
+		if stateA == nil || stateB == nil {
+			if stateA != stateB {
+				count++
+			}
+			continue
+		}
+		if *stateA != *stateB {
+			count++
+		}
+	}
+	return count
+}
+
+// ComparisonFrame returns a frame with a row per instance whose timeline differs between the versions.
+func (c *Comparison) ComparisonFrame() *data.Frame {
+	frame := data.NewFrame("Backtesting comparison",
+		data.NewField("Instance", nil, make([]string, 0, len(c.Differences))),
+		data.NewField("Only in A", nil, make([]bool, 0, len(c.Differences))),
+		data.NewField("Only in B", nil, make([]bool, 0, len(c.Differences))),
+		data.NewField("Different evaluations", nil, make([]int64, 0, len(c.Differences))),
+		data.NewField("Notifications A", nil, make([]int64, 0, len(c.Differences))),
+		data.NewField("Notifications B", nil, make([]int64, 0, len(c.Differences))),
+	)
+	for _, d := range c.Differences {
+		frame.AppendRow(d.Labels.String(), d.OnlyInA, d.OnlyInB, int64(d.Evaluations), int64(d.NotificationsA), int64(d.NotificationsB))
+	}
+	return frame
+}
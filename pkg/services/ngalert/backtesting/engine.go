@@ -58,7 +58,18 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory) *Engine {
 	}
 }
 
+// Test evaluates the rule over the range of time and returns the state of each alert instance at each evaluation.
 func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	result, err := e.Run(ctx, user, rule, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return result.TimelineFrame(), nil
+}
+
+// Run evaluates the rule over the range of time and returns the timeline of each alert instance
+// together with a summary of the notifications that would have been sent.
+func (e *Engine) Run(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (*Result, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -81,42 +92,19 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 
 	start := time.Now()
 
-	tsField := data.NewField("Time", nil, make([]time.Time, length))
-	valueFields := make(map[string]*data.Field)
-
-	err = evaluator.Eval(ruleCtx, from, to, time.Duration(rule.IntervalSeconds)*time.Second, func(currentTime time.Time, results eval.Results) error {
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	timeline := newTimelineBuilder(from, to, interval, length)
+	err = evaluator.Eval(ruleCtx, from, to, interval, func(currentTime time.Time, results eval.Results) error {
 		idx := int(currentTime.Sub(from).Seconds()) / int(rule.IntervalSeconds)
 		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
-		tsField.Set(idx, currentTime)
-		for _, s := range states {
-			field, ok := valueFields[s.CacheID]
-			if !ok {
-				field = data.NewField("", s.Labels, make([]*string, length))
-				valueFields[s.CacheID] = field
-			}
-			if s.State.State != eval.NoData { // set nil if NoData
-				value := s.State.State.String()
-				if s.StateReason != "" {
-					value += " (" + s.StateReason + ")"
-				}
-				field.Set(idx, &value)
-				continue
-			}
-		}
+		timeline.record(idx, currentTime, states)
 		return nil
 	})
-	fields := make([]*data.Field, 0, len(valueFields)+1)
-	fields = append(fields, tsField)
-	for _, f := range valueFields {
-		fields = append(fields, f)
-	}
-	result := data.NewFrame("Backtesting results", fields...)
-
 	if err != nil {
 		return nil, err
 	}
 	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
-	return result, nil
+	return timeline.build(), nil
 }
 
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
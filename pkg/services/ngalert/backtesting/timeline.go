@@ -0,0 +1,251 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Result is the outcome of backtesting an alert rule over a range of time.
+type Result struct {
+	From     time.Time
+	To       time.Time
+	Interval time.Duration
+	// Timestamps are the times of the evaluations. Instances' states are aligned with them.
+	Timestamps []time.Time
+	// Instances are the timelines of all alert instances, sorted by labels.
+	Instances []*InstanceTimeline
+	Summary   Summary
+}
+
+// InstanceTimeline is the history of a single alert instance during backtesting.
+type InstanceTimeline struct {
+	CacheID string
+	// Labels and Annotations are rendered by the last evaluation of the instance.
+	Labels      data.Labels
+	Annotations map[string]string
+	// States contains the state of the instance at each evaluation, or nil if the instance was not evaluated.
+	States      []*EvaluatedState
+	Transitions []Transition
+	// PendingDuration and FiringDuration are the total time the instance spent in each state.
+	PendingDuration       time.Duration
+	FiringDuration        time.Duration
+	FiringNotifications   int
+	ResolvedNotifications int
+	// Flaps is the number of times the instance started firing again after it was resolved.
+	Flaps int
+}
+
+// EvaluatedState is the state of an instance at an evaluation.
+type EvaluatedState struct {
+	State  eval.State
+	Reason string
+}
+
+func (s EvaluatedState) String() string {
+	return state.FormatStateAndReason(s.State, s.Reason)
+}
+
+// Transition is a change of the state of an instance.
+type Transition struct {
+	At          time.Time
+	From        EvaluatedState
+	To          EvaluatedState
+	Labels      data.Labels
+	Annotations map[string]string
+	Values      map[string]float64
+}
+
+// Summary aggregates the timelines of all instances.
+type Summary struct {
+	Evaluations           int
+	Instances             int
+	Transitions           int
+	FiringNotifications   int
+	ResolvedNotifications int
+	// Notifications is the number of notifications that would have been sent, not counting re-sends.
+	Notifications int
+	// FlappingInstances is the number of instances that started firing more than once.
+	FlappingInstances int
+	Flaps             int
+}
+
+// isFiring returns true if alerts in the state are sent to the Alertmanager.
+func isFiring(s eval.State) bool {
+	return s == eval.Alerting || s == eval.NoData || s == eval.Error
+}
+
+type timelineBuilder struct {
+	result    *Result
+	instances map[string]*InstanceTimeline
+	// fired remembers the instances that were firing at some point
+	fired map[string]bool
+}
+
+func newTimelineBuilder(from, to time.Time, interval time.Duration, length int) *timelineBuilder {
+	return &timelineBuilder{
+		result: &Result{
+			From:       from,
+			To:         to,
+			Interval:   interval,
+			Timestamps: make([]time.Time, length),
+		},
+		instances: make(map[string]*InstanceTimeline),
+		fired:     make(map[string]bool),
+	}
+}
+
+// record adds the states of an evaluation to the timeline.
+func (b *timelineBuilder) record(idx int, now time.Time, transitions []state.StateTransition) {
+	b.result.Timestamps[idx] = now
+	for _, t := range transitions {
+		instance, ok := b.instances[t.CacheID]
+		if !ok {
+			instance = &InstanceTimeline{
+				CacheID: t.CacheID,
+				States:  make([]*EvaluatedState, len(b.result.Timestamps)),
+			}
+			b.instances[t.CacheID] = instance
+		}
+		instance.Labels = t.Labels
+		instance.Annotations = t.Annotations
+
+		current := EvaluatedState{State: t.State.State, Reason: t.StateReason}
+		instance.States[idx] = &current
+
+		switch {
+		case current.State == eval.Pending:
+			instance.PendingDuration += b.result.Interval
+		case isFiring(current.State):
+			instance.FiringDuration += b.result.Interval
+		}
+
+		if !t.Changed() {
+			continue
+		}
+		instance.Transitions = append(instance.Transitions, Transition{
+			At:          now,
+			From:        EvaluatedState{State: t.PreviousState, Reason: t.PreviousStateReason},
+			To:          current,
+			Labels:      t.Labels,
+			Annotations: t.Annotations,
+			Values:      t.Values,
+		})
+		wasFiring, firing := isFiring(t.PreviousState), isFiring(current.State)
+		switch {
+		case !wasFiring && firing:
+			instance.FiringNotifications++
+			if b.fired[t.CacheID] {
+				instance.Flaps++
+			}
+			b.fired[t.CacheID] = true
+		case wasFiring && !firing:
+			instance.ResolvedNotifications++
+		}
+	}
+}
+
+// build returns the result with instances sorted by labels and the summary calculated.
+func (b *timelineBuilder) build() *Result {
+	r := b.result
+	r.Instances = make([]*InstanceTimeline, 0, len(b.instances))
+	for _, instance := range b.instances {
+		r.Instances = append(r.Instances, instance)
+	}
+	sort.Slice(r.Instances, func(i, j int) bool {
+		return r.Instances[i].Labels.String() < r.Instances[j].Labels.String()
+	})
+
+	r.Summary = Summary{Instances: len(r.Instances)}
+	for _, ts := range r.Timestamps {
+		if !ts.IsZero() {
+			r.Summary.Evaluations++
+		}
+	}
+	for _, instance := range r.Instances {
+		r.Summary.Transitions += len(instance.Transitions)
+		r.Summary.FiringNotifications += instance.FiringNotifications
+		r.Summary.ResolvedNotifications += instance.ResolvedNotifications
+		r.Summary.Flaps += instance.Flaps
+		if instance.Flaps > 0 {
+			r.Summary.FlappingInstances++
+		}
+	}
+	r.Summary.Notifications = r.Summary.FiringNotifications + r.Summary.ResolvedNotifications
+	return r
+}
+
+// TimelineFrame returns a frame with a time field and a field per instance that contains the state
+// of the instance at each evaluation. States with NoData are returned as null.
+func (r *Result) TimelineFrame() *data.Frame {
+	tsField := data.NewField("Time", nil, r.Timestamps)
+	fields := make([]*data.Field, 0, len(r.Instances)+1)
+	fields = append(fields, tsField)
+	for _, instance := range r.Instances {
+		field := data.NewField("", instance.Labels, make([]*string, len(r.Timestamps)))
+		for idx, s := range instance.States {
+			if s == nil || s.State == eval.NoData { // set nil if NoData
+				continue
+			}
+			value := s.String()
+			field.Set(idx, &value)
+		}
+		fields = append(fields, field)
+	}
+	return data.NewFrame("Backtesting results", fields...)
+}
+
+// TransitionsFrame returns a frame with a row per state transition of any instance, ordered by time.
+func (r *Result) TransitionsFrame() *data.Frame {
+	var transitions []Transition
+	for _, instance := range r.Instances {
+		transitions = append(transitions, instance.Transitions...)
+	}
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].At.Before(transitions[j].At)
+	})
+
+	frame := data.NewFrame("Backtesting transitions",
+		data.NewField("Time", nil, make([]time.Time, 0, len(transitions))),
+		data.NewField("Instance", nil, make([]string, 0, len(transitions))),
+		data.NewField("From", nil, make([]string, 0, len(transitions))),
+		data.NewField("To", nil, make([]string, 0, len(transitions))),
+		data.NewField("Annotations", nil, make([]string, 0, len(transitions))),
+	)
+	for _, t := range transitions {
+		frame.AppendRow(t.At, t.Labels.String(), t.From.String(), t.To.String(), data.Labels(t.Annotations).String())
+	}
+	return frame
+}
+
+// SummaryFrame returns a frame with a row per instance that summarizes its timeline.
+func (r *Result) SummaryFrame() *data.Frame {
+	frame := data.NewFrame("Backtesting summary",
+		data.NewField("Instance", nil, make([]string, 0, len(r.Instances))),
+		data.NewField("Transitions", nil, make([]int64, 0, len(r.Instances))),
+		data.NewField("Notifications", nil, make([]int64, 0, len(r.Instances))),
+		data.NewField("Flaps", nil, make([]int64, 0, len(r.Instances))),
+		data.NewField("Pending", nil, make([]float64, 0, len(r.Instances))).SetConfig(&data.FieldConfig{Unit: "s"}),
+		data.NewField("Firing", nil, make([]float64, 0, len(r.Instances))).SetConfig(&data.FieldConfig{Unit: "s"}),
+	)
+	for _, instance := range r.Instances {
+		frame.AppendRow(
+			instance.Labels.String(),
+			int64(len(instance.Transitions)),
+			int64(instance.FiringNotifications+instance.ResolvedNotifications),
+			int64(instance.Flaps),
+			instance.PendingDuration.Seconds(),
+			instance.FiringDuration.Seconds(),
+		)
+	}
+	return frame
+}
+
+// Frames returns the timeline, the transitions and the summary of the result as frames.
+func (r *Result) Frames() data.Frames {
+	return data.Frames{r.TimelineFrame(), r.TransitionsFrame(), r.SummaryFrame()}
+}
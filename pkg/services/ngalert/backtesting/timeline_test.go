@@ -0,0 +1,183 @@
+package backtesting
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestEngineRun(t *testing.T) {
+	from := time.Unix(0, 0)
+	rule := models.AlertRuleGen(models.WithInterval(10*time.Second), models.WithFor(0))()
+	rule.Labels = map[string]string{"team": "a"}
+	rule.Annotations = map[string]string{"summary": "{{ $labels.host }} is down"}
+	interval := 10 * time.Second
+
+	hostA := data.Labels{"host": "a"}
+	hostB := data.Labels{"host": "b"}
+	// host a flaps and host b fires once and stays firing
+	statesA := []eval.State{eval.Normal, eval.Alerting, eval.Alerting, eval.Normal, eval.Alerting}
+	statesB := []eval.State{eval.Normal, eval.Normal, eval.Normal, eval.Alerting, eval.Alerting}
+
+	useStatesEvaluator(t, func(now time.Time) (eval.Results, error) {
+		idx := int(now.Sub(from) / interval)
+		return eval.Results{
+			{Instance: hostA, State: statesA[idx], EvaluatedAt: now},
+			{Instance: hostB, State: statesB[idx], EvaluatedAt: now},
+		}, nil
+	})
+
+	engine := NewEngine(&url.URL{}, nil)
+	result, err := engine.Run(context.Background(), &user.SignedInUser{}, rule, from, from.Add(5*interval))
+	require.NoError(t, err)
+
+	require.Len(t, result.Timestamps, 5)
+	require.Len(t, result.Instances, 2)
+
+	a := result.Instances[0]
+	require.Equal(t, "a", a.Labels["host"])
+	require.Equal(t, "a", a.Labels["team"])
+	require.Equal(t, "a is down", a.Annotations["summary"])
+	require.Len(t, a.Transitions, 3)
+	require.Equal(t, from.Add(interval), a.Transitions[0].At)
+	require.Equal(t, eval.Normal, a.Transitions[0].From.State)
+	require.Equal(t, eval.Alerting, a.Transitions[0].To.State)
+	require.Equal(t, "a is down", a.Transitions[0].Annotations["summary"])
+	require.Equal(t, 2, a.FiringNotifications)
+	require.Equal(t, 1, a.ResolvedNotifications)
+	require.Equal(t, 1, a.Flaps)
+	require.Equal(t, 3*interval, a.FiringDuration)
+	for i, s := range statesA {
+		require.Equal(t, s, a.States[i].State)
+	}
+
+	b := result.Instances[1]
+	require.Equal(t, "b", b.Labels["host"])
+	require.Equal(t, 1, b.FiringNotifications)
+	require.Equal(t, 0, b.Flaps)
+	require.Equal(t, 2*interval, b.FiringDuration)
+
+	require.Equal(t, Summary{
+		Evaluations:           5,
+		Instances:             2,
+		Transitions:           4,
+		FiringNotifications:   3,
+		ResolvedNotifications: 1,
+		Notifications:         4,
+		FlappingInstances:     1,
+		Flaps:                 1,
+	}, result.Summary)
+
+	t.Run("exports frames", func(t *testing.T) {
+		frames := result.Frames()
+		require.Len(t, frames, 3)
+
+		timeline := frames[0]
+		require.Len(t, timeline.Fields, 3)
+		require.Equal(t, 5, timeline.Rows())
+		require.Equal(t, "Alerting", *timeline.Fields[1].At(1).(*string))
+
+		transitions := frames[1]
+		require.Equal(t, 4, transitions.Rows())
+		require.Equal(t, from.Add(interval), transitions.Fields[0].At(0))
+		require.Equal(t, "Normal", transitions.Fields[2].At(0))
+		require.Equal(t, "Alerting", transitions.Fields[3].At(0))
+
+		summary := frames[2]
+		require.Equal(t, 2, summary.Rows())
+		require.Equal(t, int64(3), summary.Fields[2].At(0))
+		require.Equal(t, 30.0, summary.Fields[5].At(0))
+	})
+}
+
+func TestEngineRunKeepFiringFor(t *testing.T) {
+	from := time.Unix(0, 0)
+	interval := 10 * time.Second
+	host := data.Labels{"host": "a"}
+	// the condition clears for a single evaluation
+	states := []eval.State{eval.Alerting, eval.Normal, eval.Alerting, eval.Normal, eval.Normal, eval.Normal}
+
+	useStatesEvaluator(t, func(now time.Time) (eval.Results, error) {
+		return eval.Results{{Instance: host, State: states[int(now.Sub(from)/interval)], EvaluatedAt: now}}, nil
+	})
+
+	rule := models.AlertRuleGen(models.WithInterval(interval), models.WithFor(0), models.WithKeepFiringFor(2*interval))()
+	rule.Labels = nil
+
+	engine := NewEngine(&url.URL{}, nil)
+	result, err := engine.Run(context.Background(), &user.SignedInUser{}, rule, from, from.Add(time.Duration(len(states))*interval))
+	require.NoError(t, err)
+
+	require.Len(t, result.Instances, 1)
+	instance := result.Instances[0]
+	require.Equal(t, 1, instance.FiringNotifications)
+	require.Equal(t, 1, instance.ResolvedNotifications)
+	require.Equal(t, 0, instance.Flaps)
+	require.Equal(t, eval.Alerting, instance.States[1].State)
+	require.Equal(t, models.StateReasonKeepFiring, instance.States[1].Reason)
+	require.Equal(t, eval.Normal, instance.States[5].State)
+}
+
+func TestEngineCompare(t *testing.T) {
+	from := time.Unix(0, 0)
+	interval := 10 * time.Second
+	values := []float64{1, 5, 8, 3, 9}
+
+	// the rule versions only differ by the threshold that is stored in the title
+	thresholds := map[string]float64{"low": 4, "high": 7}
+	useStatesEvaluatorForCondition(t, func(condition models.Condition) func(now time.Time) (eval.Results, error) {
+		threshold := thresholds[condition.Condition]
+		return func(now time.Time) (eval.Results, error) {
+			s := eval.Normal
+			if values[int(now.Sub(from)/interval)] > threshold {
+				s = eval.Alerting
+			}
+			return eval.Results{{Instance: data.Labels{"host": "a"}, State: s, EvaluatedAt: now}}, nil
+		}
+	})
+
+	gen := models.AlertRuleGen(models.WithInterval(interval), models.WithFor(0))
+	low, high := gen(), gen()
+	low.Condition, high.Condition = "low", "high"
+	low.Labels, high.Labels = nil, nil
+
+	engine := NewEngine(&url.URL{}, nil)
+	comparison, err := engine.Compare(context.Background(), &user.SignedInUser{}, low, high, from, from.Add(5*interval))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, comparison.A.Summary.FiringNotifications)
+	require.Equal(t, 2, comparison.B.Summary.FiringNotifications)
+	require.Equal(t, []InstanceDifference{{
+		Labels:         data.Labels{"host": "a"},
+		Evaluations:    1,
+		NotificationsA: 3,
+		NotificationsB: 3,
+	}}, comparison.Differences)
+
+	frame := comparison.ComparisonFrame()
+	require.Equal(t, 1, frame.Rows())
+	require.Equal(t, int64(1), frame.Fields[3].At(0))
+}
+
+func useStatesEvaluator(t *testing.T, callback func(now time.Time) (eval.Results, error)) {
+	useStatesEvaluatorForCondition(t, func(models.Condition) func(now time.Time) (eval.Results, error) {
+		return callback
+	})
+}
+
+func useStatesEvaluatorForCondition(t *testing.T, factory func(condition models.Condition) func(now time.Time) (eval.Results, error)) {
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
+		return &fakeBacktestingEvaluator{evalCallback: factory(condition)}, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+}
@@ -3,6 +3,8 @@ package eval
 import (
 	"context"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -10,6 +12,14 @@ import (
 type EvaluationContext struct {
 	Ctx  context.Context
 	User *user.SignedInUser
+	// AlertingResultsReader provides the results that are alerting from the previous evaluation, if any.
+	// Threshold expressions with a recovery threshold need them.
+	AlertingResultsReader AlertingResultsReader
+}
+
+// AlertingResultsReader reads the labels of the results of a rule that are currently alerting.
+type AlertingResultsReader interface {
+	Read() []data.Labels
 }
 
 func Context(ctx context.Context, user *user.SignedInUser) EvaluationContext {
@@ -18,3 +28,9 @@ func Context(ctx context.Context, user *user.SignedInUser) EvaluationContext {
 		User: user,
 	}
 }
+
+// WithAlertingResultsReader returns a copy of the context that uses the reader to get the results that are alerting.
+func (c EvaluationContext) WithAlertingResultsReader(reader AlertingResultsReader) EvaluationContext {
+	c.AlertingResultsReader = reader
+	return c
+}
@@ -4,6 +4,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime/debug"
@@ -256,6 +257,12 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 		if err != nil {
 			return nil, fmt.Errorf("failed to get query model from '%s': %w", q.RefID, err)
 		}
+		if ctx.AlertingResultsReader != nil && expr.IsDataSource(q.DatasourceUID) {
+			model, err = withLoadedDimensions(model, ctx.AlertingResultsReader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set alerting results to '%s': %w", q.RefID, err)
+			}
+		}
 		interval, err := q.GetIntervalDuration()
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve intervalMs from '%s': %w", q.RefID, err)
@@ -292,6 +299,20 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 	return req, nil
 }
 
+// withLoadedDimensions adds the results that are alerting to the model if it is a threshold expression with a
+// recovery threshold. Other models are returned as they are.
+func withLoadedDimensions(model json.RawMessage, reader AlertingResultsReader) (json.RawMessage, error) {
+	var query map[string]interface{}
+	if err := json.Unmarshal(model, &query); err != nil {
+		return nil, err
+	}
+	if !expr.IsHysteresisExpression(query) {
+		return model, nil
+	}
+	expr.SetLoadedDimensionsToHysteresisCommand(query, reader.Read())
+	return json.Marshal(query)
+}
+
 type NumberValueCapture struct {
 	Var    string // RefID
 	Labels data.Labels
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -579,3 +580,35 @@ type fakeExpressionService struct {
 func (f fakeExpressionService) ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
 	return f.hook(ctx, now, pipeline)
 }
+
+type fakeAlertingResultsReader []data.Labels
+
+func (r fakeAlertingResultsReader) Read() []data.Labels {
+	return r
+}
+
+func TestWithLoadedDimensions(t *testing.T) {
+	reader := fakeAlertingResultsReader{{"host": "a"}}
+
+	t.Run("adds loaded dimensions to threshold expressions with a recovery threshold", func(t *testing.T) {
+		model := json.RawMessage(`{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[80]},"unloadEvaluator":{"type":"lt","params":[70]}}]}`)
+
+		result, err := withLoadedDimensions(model, reader)
+		require.NoError(t, err)
+
+		var query map[string]interface{}
+		require.NoError(t, json.Unmarshal(result, &query))
+		require.Equal(t, []interface{}{map[string]interface{}{"host": "a"}}, query["loadedDimensions"])
+	})
+
+	t.Run("leaves other models unchanged", func(t *testing.T) {
+		for _, model := range []json.RawMessage{
+			json.RawMessage(`{"type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[80]}}]}`),
+			json.RawMessage(`{"type":"math","expression":"$A > 1"}`),
+		} {
+			result, err := withLoadedDimensions(model, reader)
+			require.NoError(t, err)
+			require.Equal(t, model, result)
+		}
+	})
+}
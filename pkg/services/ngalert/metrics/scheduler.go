@@ -25,6 +25,8 @@ type Scheduler struct {
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
+	EvaluationSkipped                   *prometheus.CounterVec
+	EvalQueueWait                       *prometheus.HistogramVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -121,5 +123,24 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "name"},
 		),
+		EvaluationSkipped: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_rule_evaluations_skipped_total",
+				Help:      "The total number of rule evaluations skipped because a newer evaluation was scheduled while waiting in the evaluation queue.",
+			},
+			[]string{"org"},
+		),
+		EvalQueueWait: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_queue_wait_duration_seconds",
+				Help:      "The time a rule evaluation waited for a free slot when the number of concurrent evaluations is limited.",
+				Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25, 50, 100},
+			},
+			[]string{"org"},
+		),
 	}
 }
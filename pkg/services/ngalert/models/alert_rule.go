@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -20,6 +21,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -112,8 +115,34 @@ const (
 	StateReasonPaused        = "Paused"
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
+	StateReasonKeepFiring    = "KeepFiring"
+	StateReasonSuppressed    = "Suppressed"
 )
 
+// stateReasonSeparator separates the reasons of a state that has several of them, for example "NoData, Suppressed".
+const stateReasonSeparator = ", "
+
+// ConcatReasons joins the non-empty state reasons into one.
+func ConcatReasons(reasons ...string) string {
+	nonEmpty := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		if reason != "" {
+			nonEmpty = append(nonEmpty, reason)
+		}
+	}
+	return strings.Join(nonEmpty, stateReasonSeparator)
+}
+
+// HasReason returns whether the state reason, possibly made of several reasons, contains the reason.
+func HasReason(stateReason, reason string) bool {
+	for _, r := range strings.Split(stateReason, stateReasonSeparator) {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	// InternalLabelNameSet are labels that grafana automatically include as part of the labelset.
 	InternalLabelNameSet = map[string]struct{}{
@@ -156,10 +185,19 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is how long an alert keeps firing after the condition stopped being met.
+	// It prevents notifications about flapping alerts that resolve and fire again shortly after.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// NotificationSettings send the alerts of the rule directly to a contact point, bypassing the notification policy tree.
+	// It is a slice to be stored as JSON by the database, but it holds at most one element.
+	NotificationSettings []NotificationSettings `xorm:"notification_settings"`
+	// DependsOn contains the UIDs of rules of the same group that this rule depends on.
+	// The notifications of the rule are suppressed while any of these rules is firing.
+	DependsOn []string `xorm:"depends_on"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -212,6 +250,14 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// GetNotificationSettings returns the notification settings of the rule, or nil if its alerts are routed by the notification policy tree.
+func (alertRule *AlertRule) GetNotificationSettings() *NotificationSettings {
+	if len(alertRule.NotificationSettings) == 0 {
+		return nil
+	}
+	return &alertRule.NotificationSettings[0]
+}
+
 func (alertRule *AlertRule) GetEvalCondition() Condition {
 	return Condition{
 		Condition: alertRule.Condition,
@@ -352,10 +398,16 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For                  time.Duration
+	KeepFiringFor        time.Duration
+	Annotations          map[string]string
+	Labels               map[string]string
+	IsPaused             bool
+	NotificationSettings []NotificationSettings `xorm:"notification_settings"`
+	DependsOn            []string               `xorm:"depends_on"`
+	// CreatedBy is the login of the user that created the version. It is empty if the version
+	// was not created by a user, for example by file provisioning.
+	CreatedBy string `xorm:"created_by"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -428,6 +480,8 @@ type ListOrgRuleGroupsQuery struct {
 type UpdateRule struct {
 	Existing *AlertRule
 	New      AlertRule
+	// RestoredFrom is the version of the rule that the update restores, if any.
+	RestoredFrom int64
 }
 
 // Condition contains backend expressions and queries and the RefID
@@ -480,6 +534,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
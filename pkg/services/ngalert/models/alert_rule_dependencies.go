@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ValidateRuleGroupDependencies checks the dependencies between the rules of a group. Every rule must depend only on
+// other rules of the same group, at most once on each of them, and the dependencies must not form a cycle.
+func ValidateRuleGroupDependencies(rules []*AlertRule) error {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		if rule.UID != "" {
+			byUID[rule.UID] = rule
+		}
+	}
+	for _, rule := range rules {
+		seen := make(map[string]struct{}, len(rule.DependsOn))
+		for _, uid := range rule.DependsOn {
+			if uid == rule.UID {
+				return fmt.Errorf("%w: rule '%s' cannot depend on itself", ErrAlertRuleFailedValidation, rule.Title)
+			}
+			if _, ok := seen[uid]; ok {
+				return fmt.Errorf("%w: rule '%s' depends on rule '%s' more than once", ErrAlertRuleFailedValidation, rule.Title, uid)
+			}
+			seen[uid] = struct{}{}
+			if _, ok := byUID[uid]; !ok {
+				return fmt.Errorf("%w: rule '%s' depends on rule '%s' that does not belong to the group", ErrAlertRuleFailedValidation, rule.Title, uid)
+			}
+		}
+	}
+
+	// A depth-first search that finds back edges. Rules are visited in the order of their UIDs to report the same cycle every time.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(byUID))
+	var visit func(uid string, path []string) error
+	visit = func(uid string, path []string) error {
+		switch marks[uid] {
+		case visiting:
+			return fmt.Errorf("%w: the dependencies between the rules form a cycle: %v", ErrAlertRuleFailedValidation, append(path, uid))
+		case visited:
+			return nil
+		}
+		marks[uid] = visiting
+		for _, dep := range byUID[uid].DependsOn {
+			if err := visit(dep, append(path, uid)); err != nil {
+				return err
+			}
+		}
+		marks[uid] = visited
+		return nil
+	}
+	uids := make([]string, 0, len(byUID))
+	for uid := range byUID {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	for _, uid := range uids {
+		if err := visit(uid, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DependencyDepths returns the length of the longest chain of dependencies of every rule by the rule UID. It is 0 for
+// the rules that do not depend on other rules. Dependencies on rules that are not in the list are ignored.
+// Sorting the rules by depth puts every rule after the rules it depends on.
+func DependencyDepths(rules []*AlertRule) map[string]int {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		byUID[rule.UID] = rule
+	}
+	depths := make(map[string]int, len(rules))
+	inProgress := make(map[string]struct{})
+	var depth func(uid string) int
+	depth = func(uid string) int {
+		if d, ok := depths[uid]; ok {
+			return d
+		}
+		if _, ok := inProgress[uid]; ok { // a cycle, which is rejected by ValidateRuleGroupDependencies
+			return 0
+		}
+		inProgress[uid] = struct{}{}
+		d := 0
+		for _, dep := range byUID[uid].DependsOn {
+			if _, ok := byUID[dep]; ok {
+				if depDepth := depth(dep) + 1; depDepth > d {
+					d = depDepth
+				}
+			}
+		}
+		delete(inProgress, uid)
+		depths[uid] = d
+		return d
+	}
+	for uid := range byUID {
+		depth(uid)
+	}
+	return depths
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRuleGroupDependencies(t *testing.T) {
+	rule := func(uid string, dependsOn ...string) *AlertRule {
+		return &AlertRule{UID: uid, Title: "rule-" + uid, DependsOn: dependsOn}
+	}
+
+	testCases := []struct {
+		name   string
+		rules  []*AlertRule
+		expErr string
+	}{
+		{name: "no dependencies", rules: []*AlertRule{rule("a"), rule("b")}},
+		{name: "chain of dependencies", rules: []*AlertRule{rule("c", "b"), rule("b", "a"), rule("a")}},
+		{name: "several dependencies", rules: []*AlertRule{rule("a"), rule("b"), rule("c", "a", "b"), rule("d", "a")}},
+		{name: "new rule depends on existing rule", rules: []*AlertRule{rule("a"), rule("", "a")}},
+		{
+			name:   "self reference",
+			rules:  []*AlertRule{rule("a", "a")},
+			expErr: "rule 'rule-a' cannot depend on itself",
+		},
+		{
+			name:   "duplicated reference",
+			rules:  []*AlertRule{rule("a"), rule("b", "a", "a")},
+			expErr: "rule 'rule-b' depends on rule 'a' more than once",
+		},
+		{
+			name:   "rule of another group",
+			rules:  []*AlertRule{rule("a"), rule("b", "x")},
+			expErr: "rule 'rule-b' depends on rule 'x' that does not belong to the group",
+		},
+		{
+			name:   "cycle",
+			rules:  []*AlertRule{rule("a", "c"), rule("b", "a"), rule("c", "b"), rule("d", "a")},
+			expErr: "the dependencies between the rules form a cycle: [a c b a]",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRuleGroupDependencies(tc.rules)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestDependencyDepths(t *testing.T) {
+	rules := []*AlertRule{
+		{UID: "d", DependsOn: []string{"b", "c"}},
+		{UID: "c", DependsOn: []string{"a"}},
+		{UID: "b", DependsOn: []string{"a", "missing"}},
+		{UID: "a"},
+		{UID: "e", DependsOn: []string{"missing"}},
+	}
+	require.Equal(t, map[string]int{"a": 0, "b": 1, "c": 1, "d": 2, "e": 0}, DependencyDepths(rules))
+}
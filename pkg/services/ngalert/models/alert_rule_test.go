@@ -199,6 +199,12 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.KeepFiringFor = -1
+				},
+			},
 			{
 				name: "IsPaused did not come in request",
 				mutator: func(r *AlertRuleWithOptionals) {
@@ -213,6 +219,7 @@ func TestPatchPartialAlertRule(t *testing.T) {
 				for {
 					rule := AlertRuleGen(func(rule *AlertRule) {
 						rule.For = time.Duration(rand.Int63n(1000) + 1)
+						rule.KeepFiringFor = time.Duration(rand.Int63n(1000) + 1)
 					})()
 					existing = &AlertRuleWithOptionals{AlertRule: *rule}
 					cloned := *existing
@@ -684,3 +691,13 @@ func TestTimeRangeYAML(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, yamlRaw, string(serialized))
 }
+
+func TestConcatReasons(t *testing.T) {
+	require.Equal(t, StateReasonSuppressed, ConcatReasons("", StateReasonSuppressed))
+	require.Equal(t, "NoData, Suppressed", ConcatReasons("NoData", StateReasonSuppressed))
+
+	require.True(t, HasReason(ConcatReasons(StateReasonKeepFiring, StateReasonSuppressed), StateReasonSuppressed))
+	require.True(t, HasReason(StateReasonSuppressed, StateReasonSuppressed))
+	require.False(t, HasReason(StateReasonKeepFiring, StateReasonSuppressed))
+	require.False(t, HasReason("", StateReasonSuppressed))
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// ListAlertRuleVersionsQuery is the query for retrieving all versions of an alert rule, latest first.
+type ListAlertRuleVersionsQuery struct {
+	OrgID   int64
+	RuleUID string
+
+	Result []*AlertRuleVersion
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a single version of an alert rule.
+type GetAlertRuleVersionQuery struct {
+	OrgID   int64
+	RuleUID string
+	Version int64
+
+	Result *AlertRuleVersion
+}
+
+// AlertRuleVersionChange is a difference between two versions of an alert rule.
+type AlertRuleVersionChange struct {
+	// Field is the path of the changed field, such as "title", "for", "labels.team" or "data.A".
+	// Labels, annotations and queries are compared by key and refID respectively.
+	Field string
+	// Old and New are the values of the field in each version. Old is nil if the field was added, and New is nil if it was removed.
+	Old interface{}
+	New interface{}
+}
+
+// Diff returns the changes that were made to the version to get the other version.
+func (v *AlertRuleVersion) Diff(other *AlertRuleVersion) []AlertRuleVersionChange {
+	var changes []AlertRuleVersionChange
+	add := func(field string, old, new interface{}) {
+		changes = append(changes, AlertRuleVersionChange{Field: field, Old: old, New: new})
+	}
+
+	if v.Title != other.Title {
+		add("title", v.Title, other.Title)
+	}
+	if v.Condition != other.Condition {
+		add("condition", v.Condition, other.Condition)
+	}
+	changes = append(changes, diffAlertQueries(v.Data, other.Data)...)
+	if v.IntervalSeconds != other.IntervalSeconds {
+		add("intervalSeconds", v.IntervalSeconds, other.IntervalSeconds)
+	}
+	if v.For != other.For {
+		add("for", v.For.String(), other.For.String())
+	}
+	if v.KeepFiringFor != other.KeepFiringFor {
+		add("keepFiringFor", v.KeepFiringFor.String(), other.KeepFiringFor.String())
+	}
+	if v.NoDataState != other.NoDataState {
+		add("noDataState", v.NoDataState, other.NoDataState)
+	}
+	if v.ExecErrState != other.ExecErrState {
+		add("execErrState", v.ExecErrState, other.ExecErrState)
+	}
+	if v.IsPaused != other.IsPaused {
+		add("isPaused", v.IsPaused, other.IsPaused)
+	}
+	if old, new := notificationSettingsValue(v.NotificationSettings), notificationSettingsValue(other.NotificationSettings); !reflect.DeepEqual(old, new) {
+		add("notificationSettings", old, new)
+	}
+	if !stringSlicesEqual(v.DependsOn, other.DependsOn) {
+		add("dependsOn", v.DependsOn, other.DependsOn)
+	}
+	if v.RuleNamespaceUID != other.RuleNamespaceUID {
+		add("namespaceUID", v.RuleNamespaceUID, other.RuleNamespaceUID)
+	}
+	if v.RuleGroup != other.RuleGroup {
+		add("ruleGroup", v.RuleGroup, other.RuleGroup)
+	}
+	changes = append(changes, diffStringMaps("labels", v.Labels, other.Labels)...)
+	changes = append(changes, diffStringMaps("annotations", v.Annotations, other.Annotations)...)
+	return changes
+}
+
+// Restore returns a copy of the rule with the definition of the version. The folder, group and evaluation
+// interval of the rule are kept as they are shared with the other rules in the group.
+func (v *AlertRuleVersion) Restore(rule AlertRule) AlertRule {
+	rule.Title = v.Title
+	rule.Condition = v.Condition
+	rule.Data = v.Data
+	rule.For = v.For
+	rule.KeepFiringFor = v.KeepFiringFor
+	rule.NoDataState = v.NoDataState
+	rule.ExecErrState = v.ExecErrState
+	rule.Annotations = v.Annotations
+	rule.Labels = v.Labels
+	rule.IsPaused = v.IsPaused
+	rule.NotificationSettings = v.NotificationSettings
+	rule.DependsOn = v.DependsOn
+	return rule
+}
+
+// notificationSettingsValue returns the notification settings of a version as a change value, which is nil if there are none.
+func notificationSettingsValue(settings []NotificationSettings) interface{} {
+	if len(settings) == 0 {
+		return nil
+	}
+	return settings[0]
+}
+
+func diffAlertQueries(old, new []AlertQuery) []AlertRuleVersionChange {
+	oldByRefID := make(map[string]AlertQuery, len(old))
+	for _, q := range old {
+		oldByRefID[q.RefID] = q
+	}
+	newByRefID := make(map[string]AlertQuery, len(new))
+	for _, q := range new {
+		newByRefID[q.RefID] = q
+	}
+
+	var changes []AlertRuleVersionChange
+	for _, refID := range sortedKeys(oldByRefID, newByRefID) {
+		o, inOld := oldByRefID[refID]
+		n, inNew := newByRefID[refID]
+		switch {
+		case !inOld:
+			changes = append(changes, AlertRuleVersionChange{Field: "data." + refID, New: n})
+		case !inNew:
+			changes = append(changes, AlertRuleVersionChange{Field: "data." + refID, Old: o})
+		default:
+			// marshalling compacts the model, so that only changes of the content are reported
+			oldJSON, oldErr := json.Marshal(o)
+			newJSON, newErr := json.Marshal(n)
+			if oldErr != nil || newErr != nil || string(oldJSON) != string(newJSON) {
+				changes = append(changes, AlertRuleVersionChange{Field: "data." + refID, Old: o, New: n})
+			}
+		}
+	}
+	return changes
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func diffStringMaps(field string, old, new map[string]string) []AlertRuleVersionChange {
+	var changes []AlertRuleVersionChange
+	for _, key := range sortedKeys(old, new) {
+		o, inOld := old[key]
+		n, inNew := new[key]
+		switch {
+		case !inOld:
+			changes = append(changes, AlertRuleVersionChange{Field: field + "." + key, New: n})
+		case !inNew:
+			changes = append(changes, AlertRuleVersionChange{Field: field + "." + key, Old: o})
+		case o != n:
+			changes = append(changes, AlertRuleVersionChange{Field: field + "." + key, Old: o, New: n})
+		}
+	}
+	return changes
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleVersionDiff(t *testing.T) {
+	queryA := AlertQuery{RefID: "A", DatasourceUID: "ds", Model: json.RawMessage(`{"expr": "up"}`)}
+	queryB := AlertQuery{RefID: "B", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type": "reduce"}`)}
+
+	old := &AlertRuleVersion{
+		Version:     1,
+		Title:       "rule",
+		Condition:   "B",
+		Data:        []AlertQuery{queryA, queryB},
+		For:         time.Minute,
+		Labels:      map[string]string{"team": "a", "severity": "low"},
+		Annotations: map[string]string{"summary": "down"},
+	}
+
+	t.Run("should return nothing if versions are equal", func(t *testing.T) {
+		same := *old
+		same.Version = 2
+		require.Empty(t, old.Diff(&same))
+	})
+
+	t.Run("should ignore formatting of query models", func(t *testing.T) {
+		same := *old
+		reformatted := queryA
+		reformatted.Model = json.RawMessage(`{"expr":"up"}`)
+		same.Data = []AlertQuery{reformatted, queryB}
+		require.Empty(t, old.Diff(&same))
+	})
+
+	t.Run("should report changed fields", func(t *testing.T) {
+		changedA := queryA
+		changedA.Model = json.RawMessage(`{"expr":"down"}`)
+		queryC := AlertQuery{RefID: "C", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type": "threshold"}`)}
+		updated := &AlertRuleVersion{
+			Version:     2,
+			Title:       "rule",
+			Condition:   "C",
+			Data:        []AlertQuery{changedA, queryC},
+			For:         5 * time.Minute,
+			Labels:      map[string]string{"team": "b", "env": "prod"},
+			Annotations: map[string]string{"summary": "down"},
+			DependsOn:   []string{"database"},
+		}
+
+		require.Equal(t, []AlertRuleVersionChange{
+			{Field: "condition", Old: "B", New: "C"},
+			{Field: "data.A", Old: queryA, New: changedA},
+			{Field: "data.B", Old: queryB},
+			{Field: "data.C", New: queryC},
+			{Field: "for", Old: "1m0s", New: "5m0s"},
+			{Field: "dependsOn", Old: []string(nil), New: []string{"database"}},
+			{Field: "labels.env", New: "prod"},
+			{Field: "labels.severity", Old: "low"},
+			{Field: "labels.team", Old: "a", New: "b"},
+		}, old.Diff(updated))
+	})
+}
+
+func TestAlertRuleVersionRestore(t *testing.T) {
+	rule := AlertRuleGen()()
+	version := &AlertRuleVersion{
+		Title:            "old title",
+		Condition:        "A",
+		Data:             []AlertQuery{{RefID: "A"}},
+		For:              time.Hour,
+		RuleNamespaceUID: "other-folder",
+		RuleGroup:        "other-group",
+		IntervalSeconds:  rule.IntervalSeconds + 10,
+		Labels:           map[string]string{"team": "a"},
+		DependsOn:        []string{"database"},
+	}
+
+	restored := version.Restore(*rule)
+	require.Equal(t, "old title", restored.Title)
+	require.Equal(t, version.Data, restored.Data)
+	require.Equal(t, time.Hour, restored.For)
+	require.Equal(t, version.Labels, restored.Labels)
+	require.Equal(t, version.DependsOn, restored.DependsOn)
+	// the folder, group and interval are shared with other rules in the group
+	require.Equal(t, rule.NamespaceUID, restored.NamespaceUID)
+	require.Equal(t, rule.RuleGroup, restored.RuleGroup)
+	require.Equal(t, rule.IntervalSeconds, restored.IntervalSeconds)
+	require.Equal(t, rule.UID, restored.UID)
+}
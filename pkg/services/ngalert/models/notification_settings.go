@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// AutogeneratedRouteLabel is set on the alerts of rules that have notification settings.
+	// Such alerts are dispatched by the routes generated from the settings instead of the notification policy tree.
+	AutogeneratedRouteLabel = "__grafana_autogenerated__"
+	// AutogeneratedRouteReceiverNameLabel contains the name of the contact point the notification settings send the alerts to.
+	AutogeneratedRouteReceiverNameLabel = "__grafana_receiver__"
+	// AutogeneratedRouteSettingsHashLabel contains the fingerprint of the notification settings, which identifies the generated route.
+	AutogeneratedRouteSettingsHashLabel = "__grafana_route_settings_hash__"
+)
+
+var ErrNotificationSettingsInvalid = errors.New("invalid notification settings")
+
+// NotificationSettings are the notification settings of an alert rule. They send the alerts of the rule
+// to a contact point directly, bypassing the notification policy tree of the organization.
+// Options that are not set are inherited from the default notification policy.
+type NotificationSettings struct {
+	Receiver          string          `json:"receiver"`
+	GroupBy           []string        `json:"group_by,omitempty"`
+	GroupWait         *model.Duration `json:"group_wait,omitempty"`
+	GroupInterval     *model.Duration `json:"group_interval,omitempty"`
+	RepeatInterval    *model.Duration `json:"repeat_interval,omitempty"`
+	MuteTimeIntervals []string        `json:"mute_time_intervals,omitempty"`
+}
+
+// Validate checks that the notification settings are well-formed.
+// It does not check that the contact point and the mute timings exist.
+func (s NotificationSettings) Validate() error {
+	if s.Receiver == "" {
+		return fmt.Errorf("%w: receiver must be specified", ErrNotificationSettingsInvalid)
+	}
+	groupBy := make(map[string]struct{}, len(s.GroupBy))
+	for _, l := range s.GroupBy {
+		if l == "..." {
+			if len(s.GroupBy) > 1 {
+				return fmt.Errorf("%w: group_by cannot contain other labels together with '...'", ErrNotificationSettingsInvalid)
+			}
+			continue
+		}
+		if !model.LabelName(l).IsValid() {
+			return fmt.Errorf("%w: invalid label %q in group_by", ErrNotificationSettingsInvalid, l)
+		}
+		if _, ok := groupBy[l]; ok {
+			return fmt.Errorf("%w: duplicated label %q in group_by", ErrNotificationSettingsInvalid, l)
+		}
+		groupBy[l] = struct{}{}
+	}
+	if s.GroupWait != nil && *s.GroupWait < 0 {
+		return fmt.Errorf("%w: group_wait cannot be negative", ErrNotificationSettingsInvalid)
+	}
+	if s.GroupInterval != nil && *s.GroupInterval <= 0 {
+		return fmt.Errorf("%w: group_interval must be positive", ErrNotificationSettingsInvalid)
+	}
+	if s.RepeatInterval != nil && *s.RepeatInterval <= 0 {
+		return fmt.Errorf("%w: repeat_interval must be positive", ErrNotificationSettingsInvalid)
+	}
+	for _, name := range s.MuteTimeIntervals {
+		if name == "" {
+			return fmt.Errorf("%w: mute time interval name cannot be empty", ErrNotificationSettingsInvalid)
+		}
+	}
+	return nil
+}
+
+// Fingerprint returns a hash of the notification settings. Settings that dispatch alerts in the same way have the same fingerprint.
+func (s NotificationSettings) Fingerprint() string {
+	h := fnv.New64()
+	write := func(values ...string) {
+		for _, v := range values {
+			_, _ = h.Write([]byte(v))
+			_, _ = h.Write([]byte{255})
+		}
+		_, _ = h.Write([]byte{254})
+	}
+	duration := func(d *model.Duration) string {
+		if d == nil {
+			return ""
+		}
+		return time.Duration(*d).String()
+	}
+	groupBy := append([]string(nil), s.GroupBy...)
+	sort.Strings(groupBy)
+	muteTimeIntervals := append([]string(nil), s.MuteTimeIntervals...)
+	sort.Strings(muteTimeIntervals)
+
+	write(s.Receiver)
+	write(groupBy...)
+	write(duration(s.GroupWait), duration(s.GroupInterval), duration(s.RepeatInterval))
+	write(muteTimeIntervals...)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// ToLabels returns the labels that make the alerts of a rule match the route generated from the notification settings.
+func (s NotificationSettings) ToLabels() map[string]string {
+	return map[string]string{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteReceiverNameLabel: s.Receiver,
+		AutogeneratedRouteSettingsHashLabel: s.Fingerprint(),
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationSettingsValidate(t *testing.T) {
+	duration := func(d time.Duration) *model.Duration {
+		result := model.Duration(d)
+		return &result
+	}
+
+	testCases := []struct {
+		name     string
+		settings NotificationSettings
+		valid    bool
+	}{
+		{name: "receiver only", settings: NotificationSettings{Receiver: "team"}, valid: true},
+		{name: "all options", settings: NotificationSettings{
+			Receiver:          "team",
+			GroupBy:           []string{"alertname", "instance"},
+			GroupWait:         duration(0),
+			GroupInterval:     duration(time.Minute),
+			RepeatInterval:    duration(time.Hour),
+			MuteTimeIntervals: []string{"weekends"},
+		}, valid: true},
+		{name: "group by all labels", settings: NotificationSettings{Receiver: "team", GroupBy: []string{"..."}}, valid: true},
+		{name: "missing receiver", settings: NotificationSettings{GroupBy: []string{"alertname"}}},
+		{name: "group by all labels with other labels", settings: NotificationSettings{Receiver: "team", GroupBy: []string{"...", "alertname"}}},
+		{name: "invalid group by label", settings: NotificationSettings{Receiver: "team", GroupBy: []string{"invalid-label"}}},
+		{name: "duplicated group by label", settings: NotificationSettings{Receiver: "team", GroupBy: []string{"alertname", "alertname"}}},
+		{name: "negative group wait", settings: NotificationSettings{Receiver: "team", GroupWait: duration(-time.Second)}},
+		{name: "zero group interval", settings: NotificationSettings{Receiver: "team", GroupInterval: duration(0)}},
+		{name: "zero repeat interval", settings: NotificationSettings{Receiver: "team", RepeatInterval: duration(0)}},
+		{name: "empty mute timing name", settings: NotificationSettings{Receiver: "team", MuteTimeIntervals: []string{""}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrNotificationSettingsInvalid)
+		})
+	}
+}
+
+func TestNotificationSettingsFingerprint(t *testing.T) {
+	repeatInterval := model.Duration(time.Hour)
+	settings := NotificationSettings{
+		Receiver:          "team",
+		GroupBy:           []string{"alertname", "instance"},
+		RepeatInterval:    &repeatInterval,
+		MuteTimeIntervals: []string{"weekends", "nights"},
+	}
+
+	reordered := settings
+	reordered.GroupBy = []string{"instance", "alertname"}
+	reordered.MuteTimeIntervals = []string{"nights", "weekends"}
+	require.Equal(t, settings.Fingerprint(), reordered.Fingerprint(), "order of labels and mute timings must not change the fingerprint")
+
+	otherReceiver := settings
+	otherReceiver.Receiver = "other"
+	require.NotEqual(t, settings.Fingerprint(), otherReceiver.Fingerprint())
+
+	otherTiming := settings
+	otherTiming.RepeatInterval = nil
+	otherTiming.GroupInterval = &repeatInterval
+	require.NotEqual(t, settings.Fingerprint(), otherTiming.Fingerprint())
+
+	movedLabel := settings
+	movedLabel.GroupBy = []string{"alertname"}
+	movedLabel.MuteTimeIntervals = []string{"instance", "weekends", "nights"}
+	require.NotEqual(t, settings.Fingerprint(), movedLabel.Fingerprint())
+
+	require.Equal(t, map[string]string{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteReceiverNameLabel: "team",
+		AutogeneratedRouteSettingsHashLabel: settings.Fingerprint(),
+	}, settings.ToLabels())
+}
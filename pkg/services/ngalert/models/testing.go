@@ -164,6 +164,24 @@ func WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+func WithNotificationSettings(settings NotificationSettings) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.NotificationSettings = []NotificationSettings{settings}
+	}
+}
+
+func WithDependsOn(uids ...string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.DependsOn = uids
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -256,6 +274,7 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
 	}
 
 	if r.DashboardUID != nil {
@@ -293,6 +312,16 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	for _, ns := range r.NotificationSettings {
+		ns.GroupBy = append([]string(nil), ns.GroupBy...)
+		ns.MuteTimeIntervals = append([]string(nil), ns.MuteTimeIntervals...)
+		result.NotificationSettings = append(result.NotificationSettings, ns)
+	}
+
+	if r.DependsOn != nil {
+		result.DependsOn = append([]string(nil), r.DependsOn...)
+	}
+
 	return &result
 }
 
@@ -16,20 +16,25 @@ import (
 	alertingTemplates "github.com/grafana/alerting/templates"
 
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/alertmanager/nflog"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
-	notificationLogFilename = "notifications"
-	silencesFilename        = "silences"
+	NotificationLogFilename = "notifications"
+	SilencesFilename        = "silences"
 
 	workingDir = "alerting"
 	// maintenanceNotificationAndSilences how often should we flush and garbage collect notifications
@@ -43,6 +48,8 @@ var silenceMaintenanceInterval = 15 * time.Minute
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	// ListNotificationSettings returns the notification settings of the alert rules of the organization that have any.
+	ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error)
 }
 
 type Alertmanager struct {
@@ -56,6 +63,28 @@ type Alertmanager struct {
 
 	decryptFn receivers.GetDecryptedValueFn
 	orgID     int64
+
+	// silences and notificationLog are persisted by the maintenance of the Alertmanager, and by PersistState.
+	silences        alertingNotify.State
+	notificationLog alertingNotify.State
+}
+
+// statePeer records the silences and the notification log that the Alertmanager adds to the cluster peer
+// when it is created, so that they can be persisted without waiting for the maintenance.
+type statePeer struct {
+	alertingNotify.ClusterPeer
+	silences        alertingNotify.State
+	notificationLog alertingNotify.State
+}
+
+func (p *statePeer) AddState(key string, state cluster.State, reg prometheus.Registerer) cluster.ClusterChannel {
+	switch state.(type) {
+	case *silence.Silences:
+		p.silences = state
+	case *nflog.Log:
+		p.notificationLog = state
+	}
+	return p.ClusterPeer.AddState(key, state, reg)
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -89,11 +118,11 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 	workingPath := filepath.Join(cfg.DataPath, workingDir, strconv.Itoa(int(orgID)))
 	fileStore := NewFileStore(orgID, kvStore, workingPath)
 
-	nflogFilepath, err := fileStore.FilepathFor(ctx, notificationLogFilename)
+	nflogFilepath, err := fileStore.FilepathFor(ctx, NotificationLogFilename)
 	if err != nil {
 		return nil, err
 	}
-	silencesFilePath, err := fileStore.FilepathFor(ctx, silencesFilename)
+	silencesFilePath, err := fileStore.FilepathFor(ctx, SilencesFilename)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +133,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		maintenanceFrequency: silenceMaintenanceInterval,
 		maintenanceFunc: func(state alertingNotify.State) (int64, error) {
 			// Detached context here is to make sure that when the service is shut down the persist operation is executed.
-			return fileStore.Persist(context.Background(), silencesFilename, state)
+			return fileStore.Persist(context.Background(), SilencesFilename, state)
 		},
 	}
 
@@ -114,7 +143,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		maintenanceFrequency: notificationLogMaintenanceInterval,
 		maintenanceFunc: func(state alertingNotify.State) (int64, error) {
 			// Detached context here is to make sure that when the service is shut down the persist operation is executed.
-			return fileStore.Persist(context.Background(), notificationLogFilename, state)
+			return fileStore.Persist(context.Background(), NotificationLogFilename, state)
 		},
 	}
 
@@ -127,7 +156,8 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 	}
 
 	l := log.New("alertmanager", "org", orgID)
-	gam, err := alertingNotify.NewGrafanaAlertmanager("orgID", orgID, amcfg, peer, l, alertingNotify.NewGrafanaAlertmanagerMetrics(m.Registerer))
+	states := &statePeer{ClusterPeer: peer}
+	gam, err := alertingNotify.NewGrafanaAlertmanager("orgID", orgID, amcfg, states, l, alertingNotify.NewGrafanaAlertmanagerMetrics(m.Registerer))
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +171,8 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		decryptFn:           decryptFn,
 		fileStore:           fileStore,
 		logger:              l,
+		silences:            states.silences,
+		notificationLog:     states.notificationLog,
 	}
 
 	return am, nil
@@ -157,6 +189,22 @@ func (am *Alertmanager) StopAndWait() {
 	am.Base.StopAndWait()
 }
 
+// PersistState persists the silences and the notification log to the database, like the maintenance does periodically.
+func (am *Alertmanager) PersistState(ctx context.Context) error {
+	for filename, state := range map[string]alertingNotify.State{
+		SilencesFilename:        am.silences,
+		NotificationLogFilename: am.notificationLog,
+	} {
+		if state == nil {
+			continue
+		}
+		if _, err := am.fileStore.Persist(ctx, filename, state); err != nil {
+			return fmt.Errorf("failed to persist %s: %w", filename, err)
+		}
+	}
+	return nil
+}
+
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
 // It rolls back the save if we fail to apply the configuration.
 func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
@@ -177,7 +225,7 @@ func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
 		}
 
 		err = am.Store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
-			_, err := am.applyConfig(cfg, []byte(am.Settings.UnifiedAlerting.DefaultConfiguration))
+			_, err := am.applyConfig(ctx, cfg, []byte(am.Settings.UnifiedAlerting.DefaultConfiguration))
 			return err
 		})
 		if err != nil {
@@ -207,7 +255,7 @@ func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.P
 		}
 
 		err = am.Store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
-			_, err := am.applyConfig(cfg, rawConfig)
+			_, err := am.applyConfig(ctx, cfg, rawConfig)
 			return err
 		})
 		if err != nil {
@@ -241,7 +289,18 @@ func (am *Alertmanager) ApplyConfig(ctx context.Context, dbCfg *ngmodels.AlertCo
 // applyConfig applies a new configuration by re-initializing all components using the configuration provided.
 // It returns a boolean indicating whether the user config was changed and an error.
 // It is not safe to call concurrently.
-func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig []byte) (bool, error) {
+func (am *Alertmanager) applyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig, rawConfig []byte) (bool, error) {
+	// The routes generated from the notification settings of alert rules are only added to the applied configuration.
+	settings, err := am.Store.ListNotificationSettings(ctx, am.orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification settings of alert rules: %w", err)
+	}
+	if withRoutes := addAutogeneratedRoutes(am.logger, cfg, settings); withRoutes != cfg {
+		cfg = withRoutes
+		// the raw configuration does not contain the generated routes, so it is serialized again.
+		rawConfig = nil
+	}
+
 	// First, let's make sure this config is not already loaded
 	var amConfigChanged bool
 	if rawConfig == nil {
@@ -296,7 +355,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 // applyAndMarkConfig applies a configuration and marks it as applied if no errors occur.
 func (am *Alertmanager) applyAndMarkConfig(ctx context.Context, hash string, cfg *apimodels.PostableUserConfig, rawConfig []byte) error {
-	configChanged, err := am.applyConfig(cfg, rawConfig)
+	configChanged, err := am.applyConfig(ctx, cfg, rawConfig)
 	if err != nil {
 		return err
 	}
@@ -376,7 +435,7 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 			Err:      err,
 		}
 	}
-	receiverFactory, exists := alertingNotify.Factory(r.Type)
+	receiverFactory, exists := channels.Factory(r.Type)
 	if !exists {
 		return nil, InvalidReceiverError{
 			Receiver: r,
@@ -0,0 +1,123 @@
+package notifier
+
+import (
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// addAutogeneratedRoutes returns a copy of the configuration with a route for each distinct notification settings of alert rules.
+// The routes are added before the notification policies, so they dispatch the alerts of the rules with notification settings
+// before any policy can match them. Options that the settings do not set are inherited from the root route.
+// Settings that use a contact point or a mute timing that does not exist are skipped, so they never make the configuration invalid.
+// Alerts of such rules are then dispatched by the notification policies.
+// If there are no routes to add, the configuration is returned as it is.
+func addAutogeneratedRoutes(logger log.Logger, cfg *apimodels.PostableUserConfig, settings []ngmodels.NotificationSettings) *apimodels.PostableUserConfig {
+	if len(settings) == 0 || cfg.AlertmanagerConfig.Route == nil {
+		return cfg
+	}
+
+	receivers := make(map[string]struct{}, len(cfg.AlertmanagerConfig.Receivers))
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	muteTimes := make(map[string]struct{}, len(cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimes[mt.Name] = struct{}{}
+	}
+
+	routes := make(map[string]*apimodels.Route, len(settings))
+	for _, s := range settings {
+		fingerprint := s.Fingerprint()
+		if _, ok := routes[fingerprint]; ok {
+			continue
+		}
+		if _, ok := receivers[s.Receiver]; !ok {
+			logger.Warn("Skipping notification settings of alert rules because the contact point does not exist", "receiver", s.Receiver)
+			continue
+		}
+		if missing := missingMuteTimeIntervals(s.MuteTimeIntervals, muteTimes); len(missing) > 0 {
+			logger.Warn("Skipping notification settings of alert rules because mute timings do not exist", "receiver", s.Receiver, "mute_time_intervals", missing)
+			continue
+		}
+		route, err := autogeneratedRoute(s, fingerprint)
+		if err != nil {
+			logger.Warn("Skipping invalid notification settings of alert rules", "receiver", s.Receiver, "error", err)
+			continue
+		}
+		routes[fingerprint] = route
+	}
+	if len(routes) == 0 {
+		return cfg
+	}
+
+	// The routes are sorted so that the generated configuration, and therefore its hash, is stable.
+	fingerprints := make([]string, 0, len(routes))
+	for fingerprint := range routes {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	root := *cfg.AlertmanagerConfig.Route
+	root.Routes = make([]*apimodels.Route, 0, len(routes)+len(cfg.AlertmanagerConfig.Route.Routes))
+	for _, fingerprint := range fingerprints {
+		root.Routes = append(root.Routes, routes[fingerprint])
+	}
+	root.Routes = append(root.Routes, cfg.AlertmanagerConfig.Route.Routes...)
+
+	result := *cfg
+	result.AlertmanagerConfig.Route = &root
+	return &result
+}
+
+// autogeneratedRoute creates the route that dispatches the alerts of the rules with the notification settings.
+func autogeneratedRoute(s ngmodels.NotificationSettings, fingerprint string) (*apimodels.Route, error) {
+	matchers := make(apimodels.ObjectMatchers, 0, 3)
+	for name, value := range map[string]string{
+		ngmodels.AutogeneratedRouteLabel:             "true",
+		ngmodels.AutogeneratedRouteReceiverNameLabel: s.Receiver,
+		ngmodels.AutogeneratedRouteSettingsHashLabel: fingerprint,
+	} {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	sort.Slice(matchers, func(i, j int) bool {
+		return matchers[i].Name < matchers[j].Name
+	})
+
+	route := &apimodels.Route{
+		Receiver:          s.Receiver,
+		GroupByStr:        s.GroupBy,
+		ObjectMatchers:    matchers,
+		MuteTimeIntervals: s.MuteTimeIntervals,
+		GroupWait:         s.GroupWait,
+		GroupInterval:     s.GroupInterval,
+		RepeatInterval:    s.RepeatInterval,
+	}
+	for _, l := range s.GroupBy {
+		if l == "..." {
+			route.GroupByAll = true
+			continue
+		}
+		route.GroupBy = append(route.GroupBy, model.LabelName(l))
+	}
+	return route, nil
+}
+
+func missingMuteTimeIntervals(names []string, muteTimes map[string]struct{}) []string {
+	var missing []string
+	for _, name := range names {
+		if _, ok := muteTimes[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
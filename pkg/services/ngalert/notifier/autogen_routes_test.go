@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAddAutogeneratedRoutes(t *testing.T) {
+	cfg, err := Load([]byte(`{
+		"alertmanager_config": {
+			"route": {
+				"receiver": "default",
+				"group_by": ["alertname"],
+				"routes": [{"receiver": "team", "object_matchers": [["team", "=", "a"]]}]
+			},
+			"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+			"receivers": [
+				{"name": "default", "grafana_managed_receiver_configs": [{"uid": "default", "name": "default", "type": "email", "settings": {"addresses": "default@example.com"}}]},
+				{"name": "team", "grafana_managed_receiver_configs": [{"uid": "team", "name": "team", "type": "email", "settings": {"addresses": "team@example.com"}}]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+	repeatInterval := model.Duration(time.Hour)
+	settings := ngmodels.NotificationSettings{
+		Receiver:          "team",
+		GroupBy:           []string{"alertname", "instance"},
+		RepeatInterval:    &repeatInterval,
+		MuteTimeIntervals: []string{"weekends"},
+	}
+
+	t.Run("should return the configuration as it is if there are no notification settings", func(t *testing.T) {
+		require.Same(t, cfg, addAutogeneratedRoutes(log.NewNopLogger(), cfg, nil))
+	})
+
+	t.Run("should add a route for each distinct settings before the notification policies", func(t *testing.T) {
+		other := ngmodels.NotificationSettings{Receiver: "default"}
+		result := addAutogeneratedRoutes(log.NewNopLogger(), cfg, []ngmodels.NotificationSettings{settings, other, settings})
+
+		routes := result.AlertmanagerConfig.Route.Routes
+		require.Len(t, routes, 3)
+		require.Equal(t, "team", routes[2].Receiver)
+		require.Len(t, cfg.AlertmanagerConfig.Route.Routes, 1, "the original configuration must not be changed")
+
+		matches := MatchRoutes(result.AlertmanagerConfig.Route, labelSet(settings.ToLabels(), "team", "a"))
+		require.Len(t, matches, 1)
+		require.Equal(t, "team", matches[0].Route.RouteOpts.Receiver)
+		require.Equal(t, []string{"alertname", "instance"}, matches[0].GroupBy())
+		require.Equal(t, time.Hour, matches[0].Route.RouteOpts.RepeatInterval)
+		require.Equal(t, []string{"weekends"}, matches[0].Route.RouteOpts.MuteTimeIntervals)
+
+		matches = MatchRoutes(result.AlertmanagerConfig.Route, labelSet(other.ToLabels(), "team", "a"))
+		require.Len(t, matches, 1)
+		require.Equal(t, "default", matches[0].Route.RouteOpts.Receiver)
+		require.Equal(t, []string{"alertname"}, matches[0].GroupBy(), "options that are not set must be inherited from the root route")
+
+		matches = MatchRoutes(result.AlertmanagerConfig.Route, labelSet(nil, "team", "a"))
+		require.Len(t, matches, 1)
+		require.Equal(t, "team", matches[0].Route.RouteOpts.Receiver)
+		require.Equal(t, []int{len(routes) - 1}, matches[0].Path, "alerts without notification settings must be routed by the policies")
+	})
+
+	t.Run("should skip settings with unknown contact points or mute timings", func(t *testing.T) {
+		unknownReceiver := ngmodels.NotificationSettings{Receiver: "unknown"}
+		unknownMuteTiming := ngmodels.NotificationSettings{Receiver: "team", MuteTimeIntervals: []string{"unknown"}}
+		require.Same(t, cfg, addAutogeneratedRoutes(log.NewNopLogger(), cfg, []ngmodels.NotificationSettings{unknownReceiver, unknownMuteTiming}))
+	})
+}
+
+func labelSet(lbls map[string]string, kv ...string) model.LabelSet {
+	result := make(model.LabelSet, len(lbls)+len(kv)/2)
+	for k, v := range lbls {
+		result[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		result[model.LabelName(kv[i])] = model.LabelValue(kv[i+1])
+	}
+	return result
+}
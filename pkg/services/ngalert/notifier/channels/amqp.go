@@ -0,0 +1,281 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+)
+
+// amqpHeartbeat is the interval of the heartbeats that keep the connection to the broker open.
+const amqpHeartbeat = 10 * time.Second
+
+// AMQPConfig holds the settings of the AMQP notifier.
+type AMQPConfig struct {
+	URL        string
+	Exchange   string
+	RoutingKey string
+	Username   string
+	Password   string
+	Persistent bool
+	TLS        brokerTLSConfig
+	Payload    brokerPayload
+}
+
+func NewAMQPConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (AMQPConfig, error) {
+	rawSettings := struct {
+		URL                  string `json:"url,omitempty" yaml:"url,omitempty"`
+		Exchange             string `json:"exchange,omitempty" yaml:"exchange,omitempty"`
+		RoutingKey           string `json:"routingKey,omitempty" yaml:"routingKey,omitempty"`
+		MessageFormat        string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title                string `json:"title,omitempty" yaml:"title,omitempty"`
+		Message              string `json:"message,omitempty" yaml:"message,omitempty"`
+		Username             string `json:"username,omitempty" yaml:"username,omitempty"`
+		Password             string `json:"password,omitempty" yaml:"password,omitempty"`
+		Persistent           bool   `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+		InsecureSkipVerify   bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+		TLSCACertificate     string `json:"tlsCaCertificate,omitempty" yaml:"tlsCaCertificate,omitempty"`
+		TLSClientCertificate string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey         string `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+	}{}
+	if err := json.Unmarshal(jsonData, &rawSettings); err != nil {
+		return AMQPConfig{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.URL == "" {
+		return AMQPConfig{}, errors.New("required field 'url' is not specified")
+	}
+	if _, err := parseAMQPURL(rawSettings.URL); err != nil {
+		return AMQPConfig{}, err
+	}
+	if strings.TrimSpace(rawSettings.RoutingKey) == "" {
+		return AMQPConfig{}, errors.New("required field 'routingKey' is not specified")
+	}
+	payload, err := newBrokerPayload(rawSettings.MessageFormat, rawSettings.Title, rawSettings.Message)
+	if err != nil {
+		return AMQPConfig{}, err
+	}
+
+	settings := AMQPConfig{
+		URL:        rawSettings.URL,
+		Exchange:   rawSettings.Exchange,
+		RoutingKey: rawSettings.RoutingKey,
+		Username:   rawSettings.Username,
+		Password:   decryptFn("password", rawSettings.Password),
+		Persistent: rawSettings.Persistent,
+		TLS: brokerTLSConfig{
+			InsecureSkipVerify: rawSettings.InsecureSkipVerify,
+			CACertificate:      rawSettings.TLSCACertificate,
+			ClientCertificate:  rawSettings.TLSClientCertificate,
+			ClientKey:          decryptFn("tlsClientKey", rawSettings.TLSClientKey),
+		},
+		Payload: payload,
+	}
+	if _, err := settings.TLS.build(""); err != nil {
+		return AMQPConfig{}, err
+	}
+	return settings, nil
+}
+
+// amqpEndpoint is the broker and the virtual host that the AMQP notifier publishes to.
+type amqpEndpoint struct {
+	address  string
+	host     string
+	useTLS   bool
+	vhost    string
+	username string
+	password string
+}
+
+// parseAMQPURL parses a URL in the form amqp[s]://[user[:password]@]host[:port][/vhost], as defined by the RabbitMQ URI specification.
+func parseAMQPURL(rawURL string) (amqpEndpoint, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return amqpEndpoint{}, fmt.Errorf("invalid URL: %w", err)
+	}
+	var result amqpEndpoint
+	defaultPort := "5672"
+	switch strings.ToLower(u.Scheme) {
+	case "amqp":
+	case "amqps":
+		result.useTLS, defaultPort = true, "5671"
+	default:
+		return amqpEndpoint{}, fmt.Errorf("invalid URL: unsupported scheme '%s', must be amqp or amqps", u.Scheme)
+	}
+	result.host = u.Hostname()
+	if result.host == "" {
+		return amqpEndpoint{}, errors.New("invalid URL: host is not specified")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	result.address = net.JoinHostPort(result.host, port)
+
+	// The default virtual host is used if the path is empty. An explicit "/" refers to the virtual host with an empty name.
+	result.vhost = "/"
+	if u.Path != "" {
+		result.vhost = strings.TrimPrefix(u.Path, "/")
+	}
+	result.username, result.password = "guest", "guest"
+	if u.User != nil {
+		result.username = u.User.Username()
+		result.password, _ = u.User.Password()
+	}
+	return result, nil
+}
+
+// AMQPNotifier is responsible for publishing
+// alert notifications to an AMQP 0-9-1 broker, such as RabbitMQ.
+type AMQPNotifier struct {
+	*receivers.Base
+	log      logging.Logger
+	images   images.ImageStore
+	tmpl     *template.Template
+	orgID    int64
+	version  string
+	settings AMQPConfig
+}
+
+// NewAMQPNotifier is the constructor for the AMQP notifier.
+func NewAMQPNotifier(fc receivers.FactoryConfig) (*AMQPNotifier, error) {
+	settings, err := NewAMQPConfig(fc.Config.Settings, fc.Decrypt)
+	if err != nil {
+		return nil, err
+	}
+	return &AMQPNotifier{
+		Base:     receivers.NewBase(fc.Config),
+		log:      fc.Logger,
+		images:   fc.ImageStore,
+		tmpl:     fc.Template,
+		orgID:    fc.Config.OrgID,
+		version:  fc.GrafanaBuildVersion,
+		settings: settings,
+	}, nil
+}
+
+// Notify publishes the alert notification to the AMQP exchange.
+func (n *AMQPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	expand, payload, err := n.settings.Payload.build(ctx, n.tmpl, n.orgID, n.log, n.images, as, &tmplErr)
+	if err != nil {
+		return false, err
+	}
+	routingKey := expand(n.settings.RoutingKey)
+	if tmplErr != nil {
+		n.log.Warn("failed to template AMQP message", "error", tmplErr.Error())
+	}
+	if routingKey == "" {
+		return false, errors.New("routing key is empty after templating")
+	}
+
+	endpoint, err := parseAMQPURL(n.settings.URL)
+	if err != nil {
+		return false, err
+	}
+	if n.settings.Username != "" {
+		endpoint.username, endpoint.password = n.settings.Username, n.settings.Password
+	}
+	var tlsConfig *tls.Config
+	if endpoint.useTLS {
+		if tlsConfig, err = n.settings.TLS.build(endpoint.host); err != nil {
+			return false, err
+		}
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultBrokerTimeout)
+		defer cancel()
+	}
+
+	netConn, err := dialBroker(ctx, endpoint.address, tlsConfig)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to the AMQP broker: %w", err)
+	}
+	conn, err := amqp.Open(netConn, amqp.Config{
+		SASL:       []amqp.Authentication{&amqp.PlainAuth{Username: endpoint.username, Password: endpoint.password}},
+		Vhost:      endpoint.vhost,
+		Heartbeat:  amqpHeartbeat,
+		Properties: amqp.Table{"product": "Grafana", "version": n.version},
+	})
+	if err != nil {
+		_ = netConn.Close()
+		return false, fmt.Errorf("failed to open the AMQP connection: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			n.log.Warn("failed to close the connection to AMQP broker", "error", err)
+		}
+	}()
+
+	msg := amqp.Publishing{
+		ContentType: n.settings.Payload.contentType(),
+		Body:        payload,
+	}
+	if n.settings.Persistent {
+		msg.DeliveryMode = amqp.Persistent
+	}
+	if err := publishAMQP(ctx, conn, n.settings.Exchange, routingKey, msg); err != nil {
+		n.log.Error("Failed to publish notification to AMQP broker", "error", err, "exchange", n.settings.Exchange, "routing_key", routingKey)
+		return false, err
+	}
+	return true, nil
+}
+
+// publishAMQP publishes the message on a new channel in confirm mode and waits until the broker confirms it.
+func publishAMQP(ctx context.Context, conn *amqp.Connection, exchange, routingKey string, msg amqp.Publishing) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open an AMQP channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	if err := ch.PublishWithContext(ctx, exchange, routingKey, false, false, msg); err != nil {
+		return err
+	}
+	select {
+	case confirm, ok := <-confirms:
+		if ok {
+			if !confirm.Ack {
+				return errors.New("the AMQP broker rejected the message")
+			}
+			return nil
+		}
+		// The confirmations are closed after the reason of closing the channel is sent.
+		return amqpCloseError(<-closed)
+	case err := <-closed:
+		return amqpCloseError(err)
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for the AMQP broker to confirm the message: %w", ctx.Err())
+	}
+}
+
+// amqpCloseError returns the reason of the broker for closing the channel or the connection as an error.
+func amqpCloseError(err *amqp.Error) error {
+	if err == nil {
+		return errors.New("the AMQP channel was closed")
+	}
+	return fmt.Errorf("the AMQP broker closed the channel: %d %s", err.Code, err.Reason)
+}
+
+func (n *AMQPNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
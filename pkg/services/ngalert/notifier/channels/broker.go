@@ -0,0 +1,175 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	messageFormatJSON = "json"
+	messageFormatText = "text"
+
+	// defaultBrokerTimeout limits the time spent on a connection to a message broker if the context has no deadline.
+	defaultBrokerTimeout = 30 * time.Second
+)
+
+// brokerTLSConfig holds the TLS settings of the notifiers that publish alerts to a message broker.
+type brokerTLSConfig struct {
+	InsecureSkipVerify bool
+	CACertificate      string
+	ClientCertificate  string
+	ClientKey          string
+}
+
+// build returns the TLS configuration to connect to the broker with the given host name.
+func (c brokerTLSConfig) build(serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+		// nolint:gosec
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACertificate)) {
+			return nil, errors.New("failed to parse the CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if c.ClientCertificate != "" || c.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCertificate), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// dialBroker opens a connection to a message broker. If tlsConfig is not nil, the connection is secured with TLS.
+// The deadline of the connection is set to the deadline of the context, or to defaultBrokerTimeout if there is none.
+func dialBroker(ctx context.Context, address string, tlsConfig *tls.Config) (net.Conn, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultBrokerTimeout)
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// brokerMessage is the JSON payload published to message brokers. It is the same as the payload of the webhook notifier.
+type brokerMessage struct {
+	*templates.ExtendedData
+
+	// The protocol version.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	OrgID    int64  `json:"orgId"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+// brokerPayload holds the settings of the notifiers that define the payload published to a message broker.
+type brokerPayload struct {
+	MessageFormat string
+	Title         string
+	Message       string
+}
+
+func newBrokerPayload(format, title, message string) (brokerPayload, error) {
+	switch format {
+	case "":
+		format = messageFormatJSON
+	case messageFormatJSON, messageFormatText:
+	default:
+		return brokerPayload{}, fmt.Errorf("invalid message format '%s', must be '%s' or '%s'", format, messageFormatJSON, messageFormatText)
+	}
+	if title == "" {
+		title = templates.DefaultMessageTitleEmbed
+	}
+	if message == "" {
+		message = templates.DefaultMessageEmbed
+	}
+	return brokerPayload{MessageFormat: format, Title: title, Message: message}, nil
+}
+
+// build renders the payload of the alerts. It returns the template function that was used,
+// so the notifiers can render their other templated settings, such as the topic, with the same data.
+func (p brokerPayload) build(ctx context.Context, tmpl *template.Template, orgID int64, l logging.Logger, store images.ImageStore, as []*types.Alert, tmplErr *error) (func(string) string, []byte, error) {
+	expand, data := templates.TmplText(ctx, tmpl, as, l, tmplErr)
+	if p.MessageFormat == messageFormatText {
+		return expand, []byte(expand(p.Message)), nil
+	}
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	_ = images.WithStoredImages(ctx, l, store,
+		func(index int, image images.Image) error {
+			if len(image.URL) != 0 {
+				data.Alerts[index].ImageURL = image.URL
+			}
+			return nil
+		},
+		as...)
+
+	msg := &brokerMessage{
+		ExtendedData: data,
+		Version:      "1",
+		GroupKey:     groupKey.String(),
+		OrgID:        orgID,
+		Title:        expand(p.Title),
+		Message:      expand(p.Message),
+		State:        string(receivers.AlertStateOK),
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(receivers.AlertStateAlerting)
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return expand, body, nil
+}
+
+// contentType returns the MIME type of the payload.
+func (p brokerPayload) contentType() string {
+	if p.MessageFormat == messageFormatText {
+		return "text/plain"
+	}
+	return "application/json"
+}
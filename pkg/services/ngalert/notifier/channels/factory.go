@@ -0,0 +1,43 @@
+// Package channels contains the notifiers of Grafana-managed alerts that are not provided by the alerting library.
+package channels
+
+import (
+	"strings"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+)
+
+var receiverFactories = map[string]func(receivers.FactoryConfig) (alertingNotify.NotificationChannel, error){
+	"amqp": wrap(NewAMQPNotifier),
+	"mqtt": wrap(NewMQTTNotifier),
+}
+
+// Factory returns the constructor of the notifier of the given type.
+// It looks up the notifiers of this package first and then the ones of the alerting library.
+func Factory(receiverType string) (func(receivers.FactoryConfig) (alertingNotify.NotificationChannel, error), bool) {
+	if factory, exists := receiverFactories[strings.ToLower(receiverType)]; exists {
+		return factory, true
+	}
+	return alertingNotify.Factory(receiverType)
+}
+
+// wrap wraps the notifier's factory errors with alertingNotify.ReceiverValidationError, as the alerting library does for its notifiers.
+func wrap[T alertingNotify.NotificationChannel](f func(fc receivers.FactoryConfig) (T, error)) func(receivers.FactoryConfig) (alertingNotify.NotificationChannel, error) {
+	return func(fc receivers.FactoryConfig) (alertingNotify.NotificationChannel, error) {
+		ch, err := f(fc)
+		if err != nil {
+			return nil, alertingNotify.ReceiverValidationError{
+				Err: err,
+				Cfg: &alertingNotify.GrafanaReceiver{
+					UID:                   fc.Config.UID,
+					Name:                  fc.Config.Name,
+					Type:                  fc.Config.Type,
+					DisableResolveMessage: fc.Config.DisableResolveMessage,
+					Settings:              fc.Config.Settings,
+				},
+			}
+		}
+		return ch, nil
+	}
+}
@@ -0,0 +1,246 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// mqttKeepAlive is the interval of the pings that keep the connection to the broker open.
+	mqttKeepAlive = 30 * time.Second
+	// mqttDisconnectQuiesce is the time in milliseconds to wait for pending work before disconnecting.
+	mqttDisconnectQuiesce = 250
+)
+
+// MQTTConfig holds the settings of the MQTT notifier.
+type MQTTConfig struct {
+	BrokerURL string
+	ClientID  string
+	Topic     string
+	Username  string
+	Password  string
+	QoS       byte
+	Retain    bool
+	TLS       brokerTLSConfig
+	Payload   brokerPayload
+}
+
+func NewMQTTConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (MQTTConfig, error) {
+	rawSettings := struct {
+		BrokerURL            string                   `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+		ClientID             string                   `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+		Topic                string                   `json:"topic,omitempty" yaml:"topic,omitempty"`
+		MessageFormat        string                   `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title                string                   `json:"title,omitempty" yaml:"title,omitempty"`
+		Message              string                   `json:"message,omitempty" yaml:"message,omitempty"`
+		Username             string                   `json:"username,omitempty" yaml:"username,omitempty"`
+		Password             string                   `json:"password,omitempty" yaml:"password,omitempty"`
+		QoS                  receivers.OptionalNumber `json:"qos,omitempty" yaml:"qos,omitempty"`
+		Retain               bool                     `json:"retain,omitempty" yaml:"retain,omitempty"`
+		InsecureSkipVerify   bool                     `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+		TLSCACertificate     string                   `json:"tlsCaCertificate,omitempty" yaml:"tlsCaCertificate,omitempty"`
+		TLSClientCertificate string                   `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey         string                   `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+	}{}
+	if err := json.Unmarshal(jsonData, &rawSettings); err != nil {
+		return MQTTConfig{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.BrokerURL == "" {
+		return MQTTConfig{}, errors.New("required field 'brokerUrl' is not specified")
+	}
+	if _, _, err := parseMQTTBrokerURL(rawSettings.BrokerURL); err != nil {
+		return MQTTConfig{}, err
+	}
+	if strings.TrimSpace(rawSettings.Topic) == "" {
+		return MQTTConfig{}, errors.New("required field 'topic' is not specified")
+	}
+	qos, err := rawSettings.QoS.Int64()
+	if err != nil || qos < 0 || qos > 2 {
+		return MQTTConfig{}, fmt.Errorf("invalid QoS '%s', must be 0, 1 or 2", rawSettings.QoS)
+	}
+	payload, err := newBrokerPayload(rawSettings.MessageFormat, rawSettings.Title, rawSettings.Message)
+	if err != nil {
+		return MQTTConfig{}, err
+	}
+
+	settings := MQTTConfig{
+		BrokerURL: rawSettings.BrokerURL,
+		ClientID:  rawSettings.ClientID,
+		Topic:     rawSettings.Topic,
+		Username:  rawSettings.Username,
+		Password:  decryptFn("password", rawSettings.Password),
+		QoS:       byte(qos),
+		Retain:    rawSettings.Retain,
+		TLS: brokerTLSConfig{
+			InsecureSkipVerify: rawSettings.InsecureSkipVerify,
+			CACertificate:      rawSettings.TLSCACertificate,
+			ClientCertificate:  rawSettings.TLSClientCertificate,
+			ClientKey:          decryptFn("tlsClientKey", rawSettings.TLSClientKey),
+		},
+		Payload: payload,
+	}
+	if settings.Password != "" && settings.Username == "" {
+		return MQTTConfig{}, errors.New("password cannot be set without a username")
+	}
+	if _, err := settings.TLS.build(""); err != nil {
+		return MQTTConfig{}, err
+	}
+	return settings, nil
+}
+
+// parseMQTTBrokerURL returns the address of the broker and whether the connection must use TLS.
+// The schemes tcp and mqtt are plain connections, the schemes ssl, tls and mqtts are TLS connections.
+func parseMQTTBrokerURL(brokerURL string) (string, bool, error) {
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	var (
+		useTLS      bool
+		defaultPort string
+	)
+	switch strings.ToLower(u.Scheme) {
+	case "tcp", "mqtt":
+		defaultPort = "1883"
+	case "ssl", "tls", "mqtts":
+		useTLS, defaultPort = true, "8883"
+	default:
+		return "", false, fmt.Errorf("invalid broker URL: unsupported scheme '%s', must be one of tcp, mqtt, ssl, tls or mqtts", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", false, errors.New("invalid broker URL: host is not specified")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+// MQTTNotifier is responsible for publishing
+// alert notifications to an MQTT broker.
+type MQTTNotifier struct {
+	*receivers.Base
+	log      logging.Logger
+	images   images.ImageStore
+	tmpl     *template.Template
+	orgID    int64
+	settings MQTTConfig
+}
+
+// NewMQTTNotifier is the constructor for the MQTT notifier.
+func NewMQTTNotifier(fc receivers.FactoryConfig) (*MQTTNotifier, error) {
+	settings, err := NewMQTTConfig(fc.Config.Settings, fc.Decrypt)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTNotifier{
+		Base:     receivers.NewBase(fc.Config),
+		log:      fc.Logger,
+		images:   fc.ImageStore,
+		tmpl:     fc.Template,
+		orgID:    fc.Config.OrgID,
+		settings: settings,
+	}, nil
+}
+
+// Notify publishes the alert notification to the MQTT broker.
+func (n *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	expand, payload, err := n.settings.Payload.build(ctx, n.tmpl, n.orgID, n.log, n.images, as, &tmplErr)
+	if err != nil {
+		return false, err
+	}
+	topic := expand(n.settings.Topic)
+	if tmplErr != nil {
+		n.log.Warn("failed to template MQTT message", "error", tmplErr.Error())
+	}
+	if topic == "" {
+		return false, errors.New("topic is empty after templating")
+	}
+
+	address, useTLS, err := parseMQTTBrokerURL(n.settings.BrokerURL)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultBrokerTimeout)
+		defer cancel()
+	}
+
+	clientID := n.settings.ClientID
+	if clientID == "" {
+		// Brokers disconnect clients that connect with an ID that is already in use,
+		// so every connection gets its own ID to allow concurrent notifications.
+		clientID = "grafana-" + util.GenerateShortUID()
+	}
+	deadline, _ := ctx.Deadline()
+	opts := mqtt.NewClientOptions().
+		SetClientID(clientID).
+		SetUsername(n.settings.Username).
+		SetPassword(n.settings.Password).
+		SetCleanSession(true).
+		// Without an explicit version, the client retries refused connections with MQTT 3.1.
+		SetProtocolVersion(4).
+		// If the connection is lost before the broker acknowledges the message,
+		// the client reconnects and publishes it again until the deadline of the notification.
+		SetAutoReconnect(true).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(time.Until(deadline)).
+		SetWriteTimeout(time.Until(deadline))
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		tlsConfig, err := n.settings.TLS.build(host)
+		if err != nil {
+			return false, err
+		}
+		opts.AddBroker("ssl://" + address).SetTLSConfig(tlsConfig)
+	} else {
+		opts.AddBroker("tcp://" + address)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitForMQTTToken(ctx, client.Connect()); err != nil {
+		return false, fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+	defer client.Disconnect(mqttDisconnectQuiesce)
+
+	if err := waitForMQTTToken(ctx, client.Publish(topic, n.settings.QoS, n.settings.Retain, payload)); err != nil {
+		n.log.Error("Failed to publish notification to MQTT broker", "error", err, "topic", topic)
+		return false, err
+	}
+	return true, nil
+}
+
+// waitForMQTTToken waits until the operation of the token completes or the context is done.
+// Tokens of published messages complete once the broker acknowledged the message as required by its QoS.
+func waitForMQTTToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for the MQTT broker: %w", ctx.Err())
+	}
+}
+
+func (n *MQTTNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
@@ -0,0 +1,281 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// mqttPublished is a message received by the fake MQTT broker.
+type mqttPublished struct {
+	clientID string
+	username string
+	password string
+	topic    string
+	qos      byte
+	retain   bool
+	payload  string
+}
+
+// startFakeMQTTBroker starts a broker stand-in that accepts a single connection, acknowledges the published message
+// according to its QoS and sends it to the returned channel.
+func startFakeMQTTBroker(t *testing.T, connackCode byte) (string, <-chan mqttPublished) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	received := make(chan mqttPublished, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		p, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		connect, ok := p.(*packets.ConnectPacket)
+		if !ok {
+			return
+		}
+		msg := mqttPublished{clientID: connect.ClientIdentifier, username: connect.Username, password: string(connect.Password)}
+		connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+		connack.ReturnCode = connackCode
+		if err := connack.Write(conn); err != nil || connackCode != packets.Accepted {
+			return
+		}
+
+		p, err = packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		publish, ok := p.(*packets.PublishPacket)
+		if !ok {
+			return
+		}
+		msg.topic, msg.qos, msg.retain, msg.payload = publish.TopicName, publish.Qos, publish.Retain, string(publish.Payload)
+		switch publish.Qos {
+		case 1:
+			puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+			puback.MessageID = publish.MessageID
+			_ = puback.Write(conn)
+		case 2:
+			pubrec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+			pubrec.MessageID = publish.MessageID
+			_ = pubrec.Write(conn)
+			if p, err := packets.ReadPacket(conn); err != nil {
+				return
+			} else if pubrel, ok := p.(*packets.PubrelPacket); !ok || pubrel.MessageID != publish.MessageID {
+				return
+			}
+			pubcomp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			pubcomp.MessageID = publish.MessageID
+			_ = pubcomp.Write(conn)
+		}
+		received <- msg
+	}()
+	return l.Addr().String(), received
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		settings string
+		expMsg   mqttPublished
+		expJSON  bool
+	}{
+		{
+			name:     "JSON message with QoS 0",
+			settings: `{"topic": "grafana/alerts"}`,
+			expMsg:   mqttPublished{topic: "grafana/alerts"},
+			expJSON:  true,
+		},
+		{
+			name:     "text message with templated topic, QoS 1 and retain",
+			settings: `{"topic": "grafana/{{ .CommonLabels.alertname }}", "messageFormat": "text", "message": "{{ .CommonLabels.lbl1 }}", "qos": 1, "retain": true}`,
+			expMsg:   mqttPublished{topic: "grafana/alert1", qos: 1, retain: true, payload: "val1"},
+		},
+		{
+			name:     "text message with QoS 2 and credentials",
+			settings: `{"topic": "alerts", "messageFormat": "text", "message": "hello", "qos": "2", "clientId": "grafana", "username": "user", "password": "pass"}`,
+			expMsg:   mqttPublished{clientID: "grafana", username: "user", password: "pass", topic: "alerts", qos: 2, payload: "hello"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			address, received := startFakeMQTTBroker(t, packets.Accepted)
+			settings := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal([]byte(c.settings), &settings))
+			settings["brokerUrl"] = "tcp://" + address
+			raw, err := json.Marshal(settings)
+			require.NoError(t, err)
+
+			cfg, err := NewMQTTConfig(raw, receivers.DecryptFunc(func(_ string, fallback string) string { return fallback }))
+			require.NoError(t, err)
+			n := &MQTTNotifier{
+				Base:     receivers.NewBase(&receivers.NotificationChannelConfig{Name: "mqtt_testing", Type: "mqtt"}),
+				log:      &logging.FakeLogger{},
+				images:   images.NewFakeImageStore(0),
+				tmpl:     tmpl,
+				orgID:    1,
+				settings: cfg,
+			}
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ok, err := n.Notify(ctx, alerts...)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			msg := <-received
+			if c.expMsg.clientID == "" {
+				require.Contains(t, msg.clientID, "grafana-")
+				msg.clientID = ""
+			}
+			if c.expJSON {
+				var payload brokerMessage
+				require.NoError(t, json.Unmarshal([]byte(msg.payload), &payload))
+				require.Equal(t, "alertname", payload.GroupKey)
+				require.Equal(t, "alerting", payload.State)
+				require.Equal(t, "[FIRING:1]  (alert1 val1)", payload.Title)
+				msg.payload = ""
+			}
+			require.Equal(t, c.expMsg, msg)
+		})
+	}
+
+	t.Run("connection refused by the broker", func(t *testing.T) {
+		address, _ := startFakeMQTTBroker(t, packets.ErrRefusedNotAuthorised)
+		cfg, err := NewMQTTConfig(json.RawMessage(`{"brokerUrl": "mqtt://`+address+`", "topic": "alerts"}`), receivers.DecryptFunc(func(_ string, fallback string) string { return fallback }))
+		require.NoError(t, err)
+		n := &MQTTNotifier{
+			Base:     receivers.NewBase(&receivers.NotificationChannelConfig{Name: "mqtt_testing", Type: "mqtt"}),
+			log:      &logging.FakeLogger{},
+			images:   images.NewFakeImageStore(0),
+			tmpl:     tmpl,
+			settings: cfg,
+		}
+		ok, err := n.Notify(notify.WithGroupKey(context.Background(), "alertname"), alerts...)
+		require.ErrorIs(t, err, packets.ErrorRefusedNotAuthorised)
+		require.False(t, ok)
+	})
+}
+
+func TestNewMQTTConfig(t *testing.T) {
+	decrypt := receivers.DecryptFunc(func(key string, fallback string) string {
+		if key == "password" {
+			return "secret"
+		}
+		return fallback
+	})
+
+	cases := []struct {
+		name     string
+		settings string
+		secure   bool
+		expErr   string
+	}{
+		{
+			name:     "minimal configuration",
+			settings: `{"brokerUrl": "tcp://localhost", "topic": "alerts"}`,
+		},
+		{
+			name:     "missing broker URL",
+			settings: `{"topic": "alerts"}`,
+			expErr:   "required field 'brokerUrl' is not specified",
+		},
+		{
+			name:     "unsupported scheme",
+			settings: `{"brokerUrl": "http://localhost", "topic": "alerts"}`,
+			expErr:   "invalid broker URL: unsupported scheme 'http', must be one of tcp, mqtt, ssl, tls or mqtts",
+		},
+		{
+			name:     "missing topic",
+			settings: `{"brokerUrl": "tcp://localhost"}`,
+			expErr:   "required field 'topic' is not specified",
+		},
+		{
+			name:     "invalid QoS",
+			settings: `{"brokerUrl": "tcp://localhost", "topic": "alerts", "qos": 3}`,
+			expErr:   "invalid QoS '3', must be 0, 1 or 2",
+		},
+		{
+			name:     "invalid message format",
+			settings: `{"brokerUrl": "tcp://localhost", "topic": "alerts", "messageFormat": "xml"}`,
+			expErr:   "invalid message format 'xml', must be 'json' or 'text'",
+		},
+		{
+			name:     "password without username",
+			settings: `{"brokerUrl": "tcp://localhost", "topic": "alerts"}`,
+			secure:   true,
+			expErr:   "password cannot be set without a username",
+		},
+		{
+			name:     "invalid CA certificate",
+			settings: `{"brokerUrl": "ssl://localhost", "topic": "alerts", "tlsCaCertificate": "invalid"}`,
+			expErr:   "failed to parse the CA certificate",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fn := receivers.DecryptFunc(func(_ string, fallback string) string { return fallback })
+			if c.secure {
+				fn = decrypt
+			}
+			_, err := NewMQTTConfig(json.RawMessage(c.settings), fn)
+			if c.expErr != "" {
+				require.EqualError(t, err, c.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseMQTTBrokerURL(t *testing.T) {
+	address, useTLS, err := parseMQTTBrokerURL("tcp://localhost")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:1883", address)
+	require.False(t, useTLS)
+
+	address, useTLS, err = parseMQTTBrokerURL("mqtts://broker.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "broker.example.com:8883", address)
+	require.True(t, useTLS)
+
+	address, useTLS, err = parseMQTTBrokerURL("ssl://broker.example.com:1234")
+	require.NoError(t, err)
+	require.Equal(t, "broker.example.com:1234", address)
+	require.True(t, useTLS)
+}
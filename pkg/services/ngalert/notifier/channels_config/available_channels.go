@@ -1237,5 +1237,243 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Description:  "The URL of the MQTT broker. Use the tcp or mqtt scheme for plain connections, and the ssl, tls or mqtts scheme for TLS connections.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Description:  "The topic to publish the messages to. You can use template variables.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Description:  "The client ID to use when connecting to the broker. If empty, a random ID is generated for every connection.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "clientId",
+				},
+				{
+					Label:        "Message format",
+					Description:  "The format of the published messages. JSON messages have the same format as the webhook notifications, text messages contain only the templated message.",
+					Element:      ElementTypeSelect,
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message. Only included in JSON messages.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Description:  "Custom message. You can use template variables.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "QoS",
+					Description:  "The quality of service level of the published messages.",
+					Element:      ElementTypeSelect,
+					PropertyName: "qos",
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+				},
+				{
+					Label:        "Retain",
+					Description:  "Asks the broker to retain the last message of the topic and deliver it to new subscribers.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Disable certificate verification",
+					Description:  "Do not verify the certificate of the broker. Not recommended.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Description:  "The PEM encoded certificate of the CA that signed the certificate of the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCaCertificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Description:  "The PEM encoded client certificate to use for mutual TLS.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS client key",
+					Description:  "The PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
+		{
+			Type:        "amqp",
+			Name:        "AMQP",
+			Description: "Publishes notifications to an AMQP 0-9-1 broker, such as RabbitMQ",
+			Heading:     "AMQP settings",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Description:  "The URL of the broker, in the form amqp[s]://[user[:password]@]host[:port][/vhost].",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "amqp://localhost:5672",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Exchange",
+					Description:  "The exchange to publish the messages to. If empty, the default exchange is used.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "exchange",
+				},
+				{
+					Label:        "Routing key",
+					Description:  "The routing key of the published messages. You can use template variables.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "grafana.alerts",
+					PropertyName: "routingKey",
+					Required:     true,
+				},
+				{
+					Label:        "Message format",
+					Description:  "The format of the published messages. JSON messages have the same format as the webhook notifications, text messages contain only the templated message.",
+					Element:      ElementTypeSelect,
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message. Only included in JSON messages.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Description:  "Custom message. You can use template variables.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Description:  "Overrides the user name of the URL.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Description:  "Overrides the password of the URL.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Persistent",
+					Description:  "Publishes the messages with the persistent delivery mode, so that durable queues keep them when the broker restarts.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "persistent",
+				},
+				{
+					Label:        "Disable certificate verification",
+					Description:  "Do not verify the certificate of the broker. Not recommended.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Description:  "The PEM encoded certificate of the CA that signed the certificate of the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsCaCertificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Description:  "The PEM encoded client certificate to use for mutual TLS.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS client key",
+					Description:  "The PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
 	}
 }
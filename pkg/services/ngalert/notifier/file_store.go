@@ -78,6 +78,30 @@ func (fileStore *FileStore) Persist(ctx context.Context, filename string, st ale
 	return int64(len(bytes)), err
 }
 
+// Get returns the content of a file persisted in the database. It returns false if the file was never persisted.
+func (fileStore *FileStore) Get(ctx context.Context, filename string) ([]byte, bool, error) {
+	content, exists, err := fileStore.kv.Get(ctx, filename)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	bytes, err := decode(content)
+	if err != nil {
+		return nil, false, fmt.Errorf("error decoding file '%s': %w", filename, err)
+	}
+	return bytes, true, nil
+}
+
+// Set persists the content of a file to the database. A running Alertmanager overwrites the file with its own state
+// during the next maintenance, therefore, it must be stopped before and started after the file is set.
+func (fileStore *FileStore) Set(ctx context.Context, filename string, content []byte) error {
+	return fileStore.kv.Set(ctx, filename, encode(content))
+}
+
+// Delete removes a file from the database.
+func (fileStore *FileStore) Delete(ctx context.Context, filename string) error {
+	return fileStore.kv.Del(ctx, filename)
+}
+
 // WriteFileToDisk writes a file with the provided name and contents to the Alertmanager working directory with the default grafana permission.
 func (fileStore *FileStore) WriteFileToDisk(fn string, content []byte) error {
 	// Ensure the working directory is created
@@ -92,3 +92,29 @@ func TestFileStore_Persist(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "something to marshal", string(b))
 }
+
+func TestFileStore_GetSetDelete(t *testing.T) {
+	store := NewFakeKVStore(t)
+	fs := NewFileStore(1, store, t.TempDir())
+	filekey := "silences"
+
+	_, exists, err := fs.Get(context.Background(), filekey)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, fs.Set(context.Background(), filekey, []byte("silence1,silence2")))
+	stored, exists, err := store.Get(context.Background(), 1, KVNamespace, filekey)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, encode([]byte("silence1,silence2")), stored)
+
+	content, exists, err := fs.Get(context.Background(), filekey)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "silence1,silence2", string(content))
+
+	require.NoError(t, fs.Delete(context.Background(), filekey))
+	_, exists, err = fs.Get(context.Background(), filekey)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -273,7 +274,7 @@ func (moa *MultiOrgAlertmanager) cleanupOrphanLocalOrgState(ctx context.Context,
 	// Remove all orphaned items from kvstore by listing all existing items
 	// in our used namespace and comparing them to the currently active
 	// organizations.
-	storedFiles := []string{notificationLogFilename, silencesFilename}
+	storedFiles := []string{NotificationLogFilename, SilencesFilename}
 	for _, fileName := range storedFiles {
 		keys, err := moa.kvStore.Keys(ctx, kvstore.AllOrganizations, KVNamespace, fileName)
 		if err != nil {
@@ -293,6 +294,66 @@ func (moa *MultiOrgAlertmanager) cleanupOrphanLocalOrgState(ctx context.Context,
 	}
 }
 
+// SyncNotificationSettings applies the latest configuration of the organization again, so that the routes generated
+// from the notification settings of its alert rules are updated without waiting for the periodic sync.
+func (moa *MultiOrgAlertmanager) SyncNotificationSettings(ctx context.Context, orgID int64) error {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		// The Alertmanager applies the routes when it is ready.
+		if errors.Is(err, ErrNoAlertmanagerForOrg) || errors.Is(err, ErrAlertmanagerNotReady) {
+			return nil
+		}
+		return err
+	}
+	q := models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := moa.configStore.GetLatestAlertmanagerConfiguration(ctx, &q); err != nil {
+		return fmt.Errorf("failed to get the Alertmanager configuration: %w", err)
+	}
+	return am.ApplyConfig(ctx, q.Result)
+}
+
+// PersistState persists the silences and the notification logs of the running Alertmanagers to the database.
+func (moa *MultiOrgAlertmanager) PersistState(ctx context.Context) error {
+	moa.alertmanagersMtx.RLock()
+	defer moa.alertmanagersMtx.RUnlock()
+
+	for orgID, am := range moa.alertmanagers {
+		if err := am.PersistState(ctx); err != nil {
+			return fmt.Errorf("failed to persist the state of the Alertmanager of organization %d: %w", orgID, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceState stops the Alertmanagers of the organizations and calls replace, which is expected to replace the state
+// they persisted to the database. Stopped Alertmanagers persist their state, therefore, it cannot be replaced while they run.
+// Then, it syncs the Alertmanagers, so that the stopped ones are started again with the new state.
+func (moa *MultiOrgAlertmanager) ReplaceState(ctx context.Context, orgIDs []int64, replace func(ctx context.Context) error) error {
+	moa.alertmanagersMtx.Lock()
+	for _, orgID := range orgIDs {
+		am, ok := moa.alertmanagers[orgID]
+		if !ok {
+			continue
+		}
+		moa.logger.Info("stopping Alertmanager to replace its state", "org", orgID)
+		am.StopAndWait()
+		// Remove the local files, so that the new Alertmanager reads the state from the database.
+		am.fileStore.CleanUp()
+		delete(moa.alertmanagers, orgID)
+		moa.metrics.RemoveOrgRegistry(orgID)
+	}
+	err := replace(ctx)
+	moa.alertmanagersMtx.Unlock()
+
+	if syncErr := moa.LoadAndSyncAlertmanagersForOrgs(ctx); syncErr != nil {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to start Alertmanagers: %w", syncErr)
+	}
+	return err
+}
+
 func (moa *MultiOrgAlertmanager) StopAndWait() {
 	moa.alertmanagersMtx.Lock()
 	defer moa.alertmanagersMtx.Unlock()
@@ -310,6 +371,12 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterPeer returns the peer of this instance of Grafana in the HA cluster. It returns false if HA is not configured.
+func (moa *MultiOrgAlertmanager) ClusterPeer() (*cluster.Peer, bool) {
+	p, ok := moa.peer.(*cluster.Peer)
+	return p, ok
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
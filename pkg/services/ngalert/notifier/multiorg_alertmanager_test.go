@@ -10,6 +10,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
@@ -112,27 +115,27 @@ grafana_alerting_discovered_configurations 4
 		err := os.Mkdir(orphanDir, 0750)
 		require.NoError(t, err)
 
-		silencesPath := filepath.Join(orphanDir, silencesFilename)
+		silencesPath := filepath.Join(orphanDir, SilencesFilename)
 		err = os.WriteFile(silencesPath, []byte("file_1"), 0644)
 		require.NoError(t, err)
 
-		notificationPath := filepath.Join(orphanDir, notificationLogFilename)
+		notificationPath := filepath.Join(orphanDir, NotificationLogFilename)
 		err = os.WriteFile(notificationPath, []byte("file_2"), 0644)
 		require.NoError(t, err)
 
 		// We make sure that both files are on disk.
 		info, err := os.Stat(silencesPath)
 		require.NoError(t, err)
-		require.Equal(t, info.Name(), silencesFilename)
+		require.Equal(t, info.Name(), SilencesFilename)
 		info, err = os.Stat(notificationPath)
 		require.NoError(t, err)
-		require.Equal(t, info.Name(), notificationLogFilename)
+		require.Equal(t, info.Name(), NotificationLogFilename)
 
 		// We also populate the kvstore with orphaned records.
-		err = kvStore.Set(ctx, orgID, KVNamespace, silencesFilename, "file_1")
+		err = kvStore.Set(ctx, orgID, KVNamespace, SilencesFilename, "file_1")
 		require.NoError(t, err)
 
-		err = kvStore.Set(ctx, orgID, KVNamespace, notificationLogFilename, "file_1")
+		err = kvStore.Set(ctx, orgID, KVNamespace, NotificationLogFilename, "file_1")
 		require.NoError(t, err)
 
 		// Now re run the sync job once.
@@ -143,10 +146,10 @@ grafana_alerting_discovered_configurations 4
 		require.True(t, errors.Is(err, fs.ErrNotExist))
 
 		// The organization kvstore records should be gone by now.
-		_, exists, _ := kvStore.Get(ctx, orgID, KVNamespace, silencesFilename)
+		_, exists, _ := kvStore.Get(ctx, orgID, KVNamespace, SilencesFilename)
 		require.False(t, exists)
 
-		_, exists, _ = kvStore.Get(ctx, orgID, KVNamespace, notificationLogFilename)
+		_, exists, _ = kvStore.Get(ctx, orgID, KVNamespace, NotificationLogFilename)
 		require.False(t, exists)
 	}
 }
@@ -297,6 +300,133 @@ func TestMultiOrgAlertmanager_AlertmanagerFor(t *testing.T) {
 	}
 }
 
+func TestMultiOrgAlertmanager_ReplaceState(t *testing.T) {
+	configStore := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	orgStore := &FakeOrgStore{
+		orgs: []int64{1, 2},
+	}
+	tmpDir := t.TempDir()
+	cfg := &setting.Cfg{
+		DataPath:        tmpDir,
+		UnifiedAlerting: setting.UnifiedAlertingSettings{AlertmanagerConfigPollInterval: 3 * time.Minute, DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, // do not poll in tests.
+	}
+	kvStore := NewFakeKVStore(t)
+	provStore := provisioning.NewFakeProvisioningStore()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	decryptFn := secretsService.GetDecryptedValue
+	reg := prometheus.NewPedanticRegistry()
+	m := metrics.NewNGAlert(reg)
+	mam, err := NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	am1, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	am2, err := mam.AlertmanagerFor(2)
+	require.NoError(t, err)
+
+	// The silence is persisted when the Alertmanager is stopped.
+	name, value, comment, createdBy := "team", "a", "comment", "user"
+	isEqual, isRegex := true, false
+	starts, ends := strfmt.DateTime(time.Now()), strfmt.DateTime(time.Now().Add(time.Hour))
+	_, err = am1.CreateSilence(&alertingNotify.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  &starts,
+			EndsAt:    &ends,
+			Matchers:  amv2.Matchers{{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex}},
+		},
+	})
+	require.NoError(t, err)
+
+	// The dispatcher of the Alertmanager cannot be stopped until it runs.
+	time.Sleep(100 * time.Millisecond)
+	err = mam.ReplaceState(ctx, []int64{1}, func(ctx context.Context) error {
+		require.NotContains(t, mam.alertmanagers, int64(1))
+		return NewFileStore(1, kvStore, "").Delete(ctx, SilencesFilename)
+	})
+	require.NoError(t, err)
+
+	newAm1, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	require.NotSame(t, am1, newAm1)
+	silences, err := newAm1.ListSilences(nil)
+	require.NoError(t, err)
+	require.Empty(t, silences)
+
+	// The Alertmanagers of other organizations keep running.
+	sameAm2, err := mam.AlertmanagerFor(2)
+	require.NoError(t, err)
+	require.Same(t, am2, sameAm2)
+
+	t.Run("should start the Alertmanagers if replace fails", func(t *testing.T) {
+		expected := errors.New("test")
+		time.Sleep(100 * time.Millisecond)
+		err := mam.ReplaceState(ctx, []int64{2}, func(ctx context.Context) error {
+			return expected
+		})
+		require.ErrorIs(t, err, expected)
+		newAm2, err := mam.AlertmanagerFor(2)
+		require.NoError(t, err)
+		require.NotSame(t, am2, newAm2)
+	})
+}
+
+func TestMultiOrgAlertmanager_PersistState(t *testing.T) {
+	configStore := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	orgStore := &FakeOrgStore{
+		orgs: []int64{1},
+	}
+	tmpDir := t.TempDir()
+	cfg := &setting.Cfg{
+		DataPath:        tmpDir,
+		UnifiedAlerting: setting.UnifiedAlertingSettings{AlertmanagerConfigPollInterval: 3 * time.Minute, DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, // do not poll in tests.
+	}
+	kvStore := NewFakeKVStore(t)
+	provStore := provisioning.NewFakeProvisioningStore()
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	decryptFn := secretsService.GetDecryptedValue
+	reg := prometheus.NewPedanticRegistry()
+	m := metrics.NewNGAlert(reg)
+	mam, err := NewMultiOrgAlertmanager(cfg, configStore, orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, mam.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	am, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	name, value, comment, createdBy := "team", "a", "comment", "user"
+	isEqual, isRegex := true, false
+	starts, ends := strfmt.DateTime(time.Now()), strfmt.DateTime(time.Now().Add(time.Hour))
+	id, err := am.CreateSilence(&alertingNotify.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  &starts,
+			EndsAt:    &ends,
+			Matchers:  amv2.Matchers{{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex}},
+		},
+	})
+	require.NoError(t, err)
+
+	files := NewFileStore(1, kvStore, "")
+	_, exists, err := files.Get(ctx, SilencesFilename)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// The silence is persisted while the Alertmanager keeps running.
+	require.NoError(t, mam.PersistState(ctx))
+	silences, exists, err := files.Get(ctx, SilencesFilename)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Contains(t, string(silences), id)
+	running, err := mam.AlertmanagerFor(1)
+	require.NoError(t, err)
+	require.Same(t, am, running)
+}
+
 var brokenConfig = `
 	"alertmanager_config": {
 		"route": {
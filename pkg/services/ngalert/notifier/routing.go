@@ -0,0 +1,134 @@
+package notifier
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// RouteMatch is a route of the notification policy tree that an alert is dispatched to.
+type RouteMatch struct {
+	// Path holds the indices of the nested routes that lead from the root route to the matched route.
+	Path  []int
+	Route *dispatch.Route
+}
+
+// GroupBy returns the labels the alerts of the route are grouped by, sorted by name.
+// If the alerts are grouped by all labels, it returns the special value "...".
+func (m RouteMatch) GroupBy() []string {
+	if m.Route.RouteOpts.GroupByAll {
+		return []string{"..."}
+	}
+	result := make([]string, 0, len(m.Route.RouteOpts.GroupBy))
+	for l := range m.Route.RouteOpts.GroupBy {
+		result = append(result, string(l))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// MatchRoutes returns the routes of the notification policy tree that an alert with the labels is dispatched to.
+// Routes are matched in the same way as the Alertmanager does, so more than one route is returned only if a matching route has continue set.
+func MatchRoutes(root *definitions.Route, lset model.LabelSet) []RouteMatch {
+	return matchRoutes(dispatch.NewRoute(root.AsAMRoute(), nil), lset, []int{})
+}
+
+func matchRoutes(r *dispatch.Route, lset model.LabelSet, path []int) []RouteMatch {
+	if !r.Matchers.Matches(lset) {
+		return nil
+	}
+	var result []RouteMatch
+	for i, child := range r.Routes {
+		childPath := make([]int, len(path), len(path)+1)
+		copy(childPath, path)
+		matches := matchRoutes(child, lset, append(childPath, i))
+		result = append(result, matches...)
+		if matches != nil && !child.Continue {
+			break
+		}
+	}
+	// If no child route matches, the alert is handled by the route itself.
+	if len(result) == 0 {
+		result = append(result, RouteMatch{Path: path, Route: r})
+	}
+	return result
+}
+
+// ActiveMuteTimeIntervals returns the names of the mute time intervals that are active at the given time.
+// Names that do not refer to any of the intervals are ignored.
+func ActiveMuteTimeIntervals(intervals []config.MuteTimeInterval, names []string, at time.Time) []string {
+	byName := make(map[string]config.MuteTimeInterval, len(intervals))
+	for _, interval := range intervals {
+		byName[interval.Name] = interval
+	}
+	var result []string
+	for _, name := range names {
+		interval, ok := byName[name]
+		if !ok {
+			continue
+		}
+		for _, ti := range interval.TimeIntervals {
+			if ti.ContainsTime(at.UTC()) {
+				result = append(result, name)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// MatchingSilences returns the IDs of the silences that are active at the given time and match the labels.
+func MatchingSilences(silences definitions.GettableSilences, lset model.LabelSet, at time.Time) ([]string, error) {
+	var result []string
+	for _, s := range silences {
+		if s == nil || s.ID == nil || s.StartsAt == nil || s.EndsAt == nil {
+			continue
+		}
+		if at.Before(time.Time(*s.StartsAt)) || !at.Before(time.Time(*s.EndsAt)) {
+			continue
+		}
+		matchers, err := silenceMatchers(s.Matchers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matchers of silence %s: %w", *s.ID, err)
+		}
+		if matchers.Matches(lset) {
+			result = append(result, *s.ID)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func silenceMatchers(matchers amv2.Matchers) (labels.Matchers, error) {
+	result := make(labels.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		matchType := labels.MatchEqual
+		switch {
+		case isRegex && isEqual:
+			matchType = labels.MatchRegexp
+		case isRegex && !isEqual:
+			matchType = labels.MatchNotRegexp
+		case !isRegex && !isEqual:
+			matchType = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(matchType, *m.Name, *m.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestMatchRoutes(t *testing.T) {
+	var root definitions.Route
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"receiver": "default",
+		"group_by": ["alertname"],
+		"group_wait": "30s",
+		"routes": [
+			{
+				"receiver": "team-a",
+				"object_matchers": [["team", "=", "a"]],
+				"continue": true,
+				"routes": [
+					{"receiver": "team-a-critical", "object_matchers": [["severity", "=", "critical"]], "group_by": ["..."]}
+				]
+			},
+			{
+				"receiver": "team-a-or-b",
+				"object_matchers": [["team", "=~", "a|b"]],
+				"repeat_interval": "1h",
+				"mute_time_intervals": ["weekends"]
+			},
+			{
+				"receiver": "never",
+				"object_matchers": [["team", "=", "b"]]
+			}
+		]
+	}`), &root))
+	require.NoError(t, root.Validate())
+
+	t.Run("alert that matches no nested route is handled by the root route", func(t *testing.T) {
+		matches := MatchRoutes(&root, model.LabelSet{"team": "c"})
+		require.Len(t, matches, 1)
+		require.Equal(t, []int{}, matches[0].Path)
+		require.Equal(t, "default", matches[0].Route.RouteOpts.Receiver)
+		require.Equal(t, []string{"alertname"}, matches[0].GroupBy())
+		require.Equal(t, 30*time.Second, matches[0].Route.RouteOpts.GroupWait)
+	})
+
+	t.Run("continue makes the alert match the following routes", func(t *testing.T) {
+		matches := MatchRoutes(&root, model.LabelSet{"team": "a", "severity": "critical"})
+		require.Len(t, matches, 2)
+		require.Equal(t, []int{0, 0}, matches[0].Path)
+		require.Equal(t, "team-a-critical", matches[0].Route.RouteOpts.Receiver)
+		require.Equal(t, []string{"..."}, matches[0].GroupBy())
+		require.Equal(t, []int{1}, matches[1].Path)
+		require.Equal(t, "team-a-or-b", matches[1].Route.RouteOpts.Receiver)
+		require.Equal(t, time.Hour, matches[1].Route.RouteOpts.RepeatInterval)
+		require.Equal(t, 30*time.Second, matches[1].Route.RouteOpts.GroupWait)
+		require.Equal(t, []string{"weekends"}, matches[1].Route.RouteOpts.MuteTimeIntervals)
+	})
+
+	t.Run("first matching route without continue stops matching", func(t *testing.T) {
+		matches := MatchRoutes(&root, model.LabelSet{"team": "b"})
+		require.Len(t, matches, 1)
+		require.Equal(t, []int{1}, matches[0].Path)
+	})
+}
+
+func TestActiveMuteTimeIntervals(t *testing.T) {
+	intervals := []config.MuteTimeInterval{
+		{
+			Name: "weekends",
+			TimeIntervals: []timeinterval.TimeInterval{{
+				Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}, {InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}}},
+			}},
+		},
+		{
+			Name: "nights",
+			TimeIntervals: []timeinterval.TimeInterval{{
+				Times: []timeinterval.TimeRange{{StartMinute: 0, EndMinute: 6 * 60}},
+			}},
+		},
+	}
+	saturdayNight := time.Date(2023, 3, 18, 2, 0, 0, 0, time.UTC)
+	mondayNoon := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	require.Equal(t, []string{"weekends", "nights"}, ActiveMuteTimeIntervals(intervals, []string{"weekends", "nights", "unknown"}, saturdayNight))
+	require.Equal(t, []string{"nights"}, ActiveMuteTimeIntervals(intervals, []string{"nights"}, saturdayNight))
+	require.Empty(t, ActiveMuteTimeIntervals(intervals, []string{"weekends", "nights"}, mondayNoon))
+}
+
+func TestMatchingSilences(t *testing.T) {
+	now := time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC)
+	silence := func(id string, startsAt, endsAt time.Time, name, value string, isRegex, isEqual bool) *amv2.GettableSilence {
+		starts, ends := strfmt.DateTime(startsAt), strfmt.DateTime(endsAt)
+		return &amv2.GettableSilence{
+			ID: &id,
+			Silence: amv2.Silence{
+				StartsAt: &starts,
+				EndsAt:   &ends,
+				Matchers: amv2.Matchers{{Name: &name, Value: &value, IsRegex: &isRegex, IsEqual: &isEqual}},
+			},
+		}
+	}
+	silences := definitions.GettableSilences{
+		silence("active", now.Add(-time.Hour), now.Add(time.Hour), "team", "a", false, true),
+		silence("active-regex", now.Add(-time.Hour), now.Add(time.Hour), "team", "a|b", true, true),
+		silence("active-not-equal", now.Add(-time.Hour), now.Add(time.Hour), "team", "b", false, false),
+		silence("other-team", now.Add(-time.Hour), now.Add(time.Hour), "team", "b", false, true),
+		silence("expired", now.Add(-2*time.Hour), now.Add(-time.Hour), "team", "a", false, true),
+		silence("pending", now.Add(time.Hour), now.Add(2*time.Hour), "team", "a", false, true),
+	}
+
+	ids, err := MatchingSilences(silences, model.LabelSet{"team": "a"}, now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"active", "active-not-equal", "active-regex"}, ids)
+
+	ids, err = MatchingSilences(silences, model.LabelSet{"team": "a"}, now.Add(90*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, []string{"pending"}, ids)
+}
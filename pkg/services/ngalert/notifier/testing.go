@@ -19,6 +19,9 @@ type fakeConfigStore struct {
 
 	// appliedConfigs stores configs by orgID and config hash.
 	appliedConfigs map[int64]map[string]*models.AlertConfiguration
+
+	// notificationSettings stores the notification settings of alert rules by orgID.
+	notificationSettings map[int64][]models.NotificationSettings
 }
 
 // Saves the image or returns an error.
@@ -43,6 +46,10 @@ func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfigurati
 	}
 }
 
+func (f *fakeConfigStore) ListNotificationSettings(_ context.Context, orgID int64) ([]models.NotificationSettings, error) {
+	return f.notificationSettings[orgID], nil
+}
+
 func (f *fakeConfigStore) GetAllLatestAlertmanagerConfiguration(context.Context) ([]*models.AlertConfiguration, error) {
 	result := make([]*models.AlertConfiguration, 0, len(f.configs))
 	for _, configuration := range f.configs {
@@ -21,6 +21,7 @@ type AlertRuleService struct {
 	baseIntervalSeconds    int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
+	configStore            AMConfigReader
 	dashboardService       dashboards.DashboardService
 	quotas                 QuotaChecker
 	xact                   TransactionManager
@@ -29,6 +30,7 @@ type AlertRuleService struct {
 
 func NewAlertRuleService(ruleStore RuleStore,
 	provenanceStore ProvisioningStore,
+	configStore AMConfigReader,
 	dashboardService dashboards.DashboardService,
 	quotas QuotaChecker,
 	xact TransactionManager,
@@ -40,6 +42,7 @@ func NewAlertRuleService(ruleStore RuleStore,
 		baseIntervalSeconds:    baseIntervalSeconds,
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
+		configStore:            configStore,
 		dashboardService:       dashboardService,
 		quotas:                 quotas,
 		xact:                   xact,
@@ -126,6 +129,12 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err = ValidateNotificationSettings(ctx, service.configStore, rule.OrgID, rule.NotificationSettings...); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err = service.validateRuleDependencies(ctx, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	rule.Updated = time.Now()
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		ids, err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{
@@ -239,12 +248,17 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 		RuleGroup:    group.Title,
 	}
 	rules := make([]*models.AlertRuleWithOptionals, len(group.Rules))
+	groupRules := make([]*models.AlertRule, 0, len(group.Rules))
 	group = *syncGroupRuleFields(&group, orgID)
 	for i := range group.Rules {
 		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
 			return err
 		}
 		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
+		groupRules = append(groupRules, &group.Rules[i])
+	}
+	if err := models.ValidateRuleGroupDependencies(groupRules); err != nil {
+		return err
 	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
@@ -258,6 +272,15 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 		return nil
 	}
 
+	changed := make([]*models.AlertRule, 0, len(delta.New)+len(delta.Update))
+	changed = append(changed, delta.New...)
+	for _, update := range delta.Update {
+		changed = append(changed, update.New)
+	}
+	if err := ValidateNotificationSettings(ctx, service.configStore, orgID, rulesNotificationSettings(changed...)...); err != nil {
+		return err
+	}
+
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		uids, err := service.ruleStore.InsertAlertRules(ctx, withoutNilAlertRules(delta.New))
 		if err != nil {
@@ -333,6 +356,12 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err = ValidateNotificationSettings(ctx, service.configStore, rule.OrgID, rule.NotificationSettings...); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err = service.validateRuleDependencies(ctx, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
 			{
@@ -351,6 +380,29 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	return rule, err
 }
 
+// validateRuleDependencies checks the rules that the rule depends on against the other rules of its group.
+func (service *AlertRuleService) validateRuleDependencies(ctx context.Context, rule models.AlertRule) error {
+	if len(rule.DependsOn) == 0 {
+		return nil
+	}
+	q := models.ListAlertRulesQuery{
+		OrgID:         rule.OrgID,
+		NamespaceUIDs: []string{rule.NamespaceUID},
+		RuleGroup:     rule.RuleGroup,
+	}
+	if err := service.ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	group := make([]*models.AlertRule, 0, len(q.Result)+1)
+	group = append(group, &rule)
+	for _, r := range q.Result {
+		if r.UID != rule.UID {
+			group = append(group, r)
+		}
+	}
+	return models.ValidateRuleGroupDependencies(group)
+}
+
 func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
 	rule := &models.AlertRule{
 		OrgID: orgID,
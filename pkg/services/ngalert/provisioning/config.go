@@ -27,7 +27,7 @@ type cfgRevision struct {
 	version          string
 }
 
-func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigStore) (*cfgRevision, error) {
+func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigReader) (*cfgRevision, error) {
 	q := models.GetLatestAlertmanagerConfigurationQuery{
 		OrgID: orgID,
 	}
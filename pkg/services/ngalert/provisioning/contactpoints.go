@@ -40,6 +40,8 @@ type ContactPointQuery struct {
 	// Optionally filter by name.
 	Name  string
 	OrgID int64
+	// Decrypt returns the secure settings in plain text instead of redacting them.
+	Decrypt bool
 }
 
 func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery) ([]apimodels.EmbeddedContactPoint, error) {
@@ -80,6 +82,10 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactP
 			if decryptedValue == "" {
 				continue
 			}
+			if q.Decrypt {
+				embeddedContactPoint.Settings.Set(k, decryptedValue)
+				continue
+			}
 			embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
 		}
 
@@ -218,6 +218,29 @@ func TestContactPointService(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("service redacts secure settings unless asked to decrypt them", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		newCp := createTestContactPoint()
+
+		newCp, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		q := cpsQuery(1)
+		q.Name = newCp.Name
+		cps, err := sut.GetContactPoints(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("token").MustString())
+		require.Equal(t, "value_recipient", cps[0].Settings.Get("recipient").MustString())
+
+		q.Decrypt = true
+		cps, err = sut.GetContactPoints(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "value_token", cps[0].Settings.Get("token").MustString())
+		require.Equal(t, "value_recipient", cps[0].Settings.Get("recipient").MustString())
+	})
+
 	t.Run("service respects concurrency token when updating", func(t *testing.T) {
 		sut := createContactPointServiceSut(secretsService)
 		newCp := createTestContactPoint()
@@ -0,0 +1,51 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ValidateNotificationSettings checks that the contact points and mute timings used by the notification settings
+// exist in the latest Alertmanager configuration of the organization. The configuration is read only if there are settings to validate.
+// It returns an error that wraps models.ErrAlertRuleFailedValidation if any of the settings refers to an unknown contact point or mute timing.
+func ValidateNotificationSettings(ctx context.Context, store AMConfigReader, orgID int64, settings ...models.NotificationSettings) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	revision, err := getLastConfiguration(ctx, orgID, store)
+	if err != nil {
+		return err
+	}
+	receivers := make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.Receivers))
+	for _, r := range revision.cfg.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	muteTimes := make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimes[mt.Name] = struct{}{}
+	}
+	for _, s := range settings {
+		if _, ok := receivers[s.Receiver]; !ok {
+			return fmt.Errorf("%w: contact point '%s' does not exist", models.ErrAlertRuleFailedValidation, s.Receiver)
+		}
+		for _, name := range s.MuteTimeIntervals {
+			if _, ok := muteTimes[name]; !ok {
+				return fmt.Errorf("%w: mute timing '%s' does not exist", models.ErrAlertRuleFailedValidation, name)
+			}
+		}
+	}
+	return nil
+}
+
+func rulesNotificationSettings(rules ...*models.AlertRule) []models.NotificationSettings {
+	var result []models.NotificationSettings
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		result = append(result, rule.NotificationSettings...)
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestValidateNotificationSettings(t *testing.T) {
+	store := &MockAMConfigStore{}
+	store.EXPECT().
+		GetsConfig(models.AlertConfiguration{
+			AlertmanagerConfiguration: configWithMuteTimings,
+		})
+
+	t.Run("passes without reading the configuration if there are no settings", func(t *testing.T) {
+		require.NoError(t, ValidateNotificationSettings(context.Background(), &MockAMConfigStore{}, 1))
+	})
+
+	t.Run("passes if contact point and mute timings exist", func(t *testing.T) {
+		err := ValidateNotificationSettings(context.Background(), store, 1, models.NotificationSettings{
+			Receiver:          "grafana-default-email",
+			MuteTimeIntervals: []string{"asdf"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("fails if contact point does not exist", func(t *testing.T) {
+		err := ValidateNotificationSettings(context.Background(), store, 1, models.NotificationSettings{Receiver: "unknown"})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("fails if mute timing does not exist", func(t *testing.T) {
+		err := ValidateNotificationSettings(context.Background(), store, 1, models.NotificationSettings{
+			Receiver:          "grafana-default-email",
+			MuteTimeIntervals: []string{"asdf", "unknown"},
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
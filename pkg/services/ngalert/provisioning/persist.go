@@ -10,11 +10,16 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 )
 
+// AMConfigReader reads the latest Alertmanager configuration of an organization.
+type AMConfigReader interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
+}
+
 // AMStore is a store of Alertmanager configurations.
 //
 //go:generate mockery --name AMConfigStore --structname MockAMConfigStore --inpackage --filename persist_mock.go --with-expecter
 type AMConfigStore interface {
-	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
+	AMConfigReader
 	UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
 }
 
@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// JitterStrategy defines how the evaluations of alert rules are spread within their evaluation interval.
+type JitterStrategy string
+
+const (
+	// JitterNever evaluates all rules with the same interval at the same tick.
+	JitterNever JitterStrategy = "none"
+	// JitterByGroup evaluates all rules of a group at the same time, but different groups at different offsets.
+	JitterByGroup JitterStrategy = "group"
+	// JitterByRule evaluates every rule at its own offset.
+	JitterByRule JitterStrategy = "rule"
+)
+
+// jitterOffset returns a deterministic offset of the evaluation of the rule within its evaluation interval.
+// The offset is split into the number of base ticks and the delay within the tick the rule is due at.
+func jitterOffset(rule *ngmodels.AlertRule, baseInterval time.Duration, strategy JitterStrategy) (int64, time.Duration) {
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if interval <= 0 || baseInterval <= 0 {
+		return 0, 0
+	}
+	var h uint64
+	switch strategy {
+	case JitterByGroup:
+		h = hashString(fmt.Sprintf("%d/%s/%s", rule.OrgID, rule.NamespaceUID, rule.RuleGroup))
+	case JitterByRule:
+		h = hashString(fmt.Sprintf("%d/%s/%s/%s", rule.OrgID, rule.NamespaceUID, rule.RuleGroup, rule.UID))
+	default:
+		return 0, 0
+	}
+	offset := time.Duration(h % uint64(interval))
+	return int64(offset / baseInterval), offset % baseInterval
+}
@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestJitterOffset(t *testing.T) {
+	baseInterval := 10 * time.Second
+	gen := models.AlertRuleGen(models.WithInterval(5 * time.Minute))
+
+	t.Run("should be zero if jitter is disabled", func(t *testing.T) {
+		for _, strategy := range []JitterStrategy{"", JitterNever} {
+			ticks, delay := jitterOffset(gen(), baseInterval, strategy)
+			require.Zero(t, ticks)
+			require.Zero(t, delay)
+		}
+	})
+
+	t.Run("should be within the evaluation interval and deterministic", func(t *testing.T) {
+		for _, strategy := range []JitterStrategy{JitterByGroup, JitterByRule} {
+			for i := 0; i < 100; i++ {
+				rule := gen()
+				ticks, delay := jitterOffset(rule, baseInterval, strategy)
+				require.GreaterOrEqual(t, ticks, int64(0))
+				require.Less(t, ticks, rule.IntervalSeconds/int64(baseInterval.Seconds()))
+				require.GreaterOrEqual(t, delay, time.Duration(0))
+				require.Less(t, delay, baseInterval)
+
+				againTicks, againDelay := jitterOffset(models.CopyRule(rule), baseInterval, strategy)
+				require.Equal(t, ticks, againTicks)
+				require.Equal(t, delay, againDelay)
+			}
+		}
+	})
+
+	t.Run("should be the same for all rules in a group if jitter by group", func(t *testing.T) {
+		rule1 := gen()
+		rule2 := models.CopyRule(rule1)
+		rule2.UID = rule1.UID + "-other"
+
+		ticks1, delay1 := jitterOffset(rule1, baseInterval, JitterByGroup)
+		ticks2, delay2 := jitterOffset(rule2, baseInterval, JitterByGroup)
+		require.Equal(t, ticks1, ticks2)
+		require.Equal(t, delay1, delay2)
+	})
+
+	t.Run("should spread rules of a group if jitter by rule", func(t *testing.T) {
+		rule := gen()
+		offsets := make(map[int64]struct{})
+		for i := 0; i < 100; i++ {
+			r := models.CopyRule(rule)
+			r.UID = util.GenerateShortUID()
+			ticks, _ := jitterOffset(r, baseInterval, JitterByRule)
+			offsets[ticks] = struct{}{}
+		}
+		require.Greater(t, len(offsets), 1)
+	})
+}
@@ -11,6 +11,7 @@ import (
 )
 
 var errRuleDeleted = errors.New("rule deleted")
+var errRuleNotOwned = errors.New("rule is evaluated by another member of the cluster")
 
 type alertRuleInfoRegistry struct {
 	mu            sync.Mutex
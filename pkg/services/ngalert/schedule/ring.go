@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/alertmanager/cluster"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of virtual nodes every member has in the ring.
+// The more tokens, the more even the distribution of alert rules between members.
+const ringTokensPerMember = 128
+
+// ClusterPeer is a member of the HA cluster of Grafana instances that share the evaluation of alert rules.
+type ClusterPeer interface {
+	// Name returns the unique name of this instance in the cluster.
+	Name() string
+	// Peers returns all alive members of the cluster, including this instance.
+	Peers() []cluster.ClusterMember
+}
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+// hashRing is a consistent hash ring that assigns alert rules to members of the cluster.
+// When a member joins or leaves, only the rules that belong to it move to other members.
+type hashRing struct {
+	self    string
+	members []string
+	tokens  []ringToken
+}
+
+func newHashRing(self string, members []string) *hashRing {
+	members = normalizeMembers(self, members)
+	tokens := make([]ringToken, 0, len(members)*ringTokensPerMember)
+	for _, member := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			tokens = append(tokens, ringToken{hash: hashString(member + "-" + strconv.Itoa(i)), member: member})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].hash == tokens[j].hash {
+			return tokens[i].member < tokens[j].member
+		}
+		return tokens[i].hash < tokens[j].hash
+	})
+	return &hashRing{self: self, members: members, tokens: tokens}
+}
+
+// owner returns the member that evaluates the alert rule.
+func (r *hashRing) owner(key ngmodels.AlertRuleKey) string {
+	return r.ownerOf(fmt.Sprintf("%d/%s", key.OrgID, key.UID))
+}
+
+// groupOwner returns the member that evaluates all rules of the group.
+func (r *hashRing) groupOwner(key ngmodels.AlertRuleGroupKey) string {
+	return r.ownerOf(fmt.Sprintf("%d/%s/%s", key.OrgID, key.NamespaceUID, key.RuleGroup))
+}
+
+func (r *hashRing) ownerOf(s string) string {
+	h := hashString(s)
+	idx := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if idx == len(r.tokens) {
+		idx = 0
+	}
+	return r.tokens[idx].member
+}
+
+// owns returns true if the alert rule is evaluated by this instance.
+func (r *hashRing) owns(key ngmodels.AlertRuleKey) bool {
+	return r.owner(key) == r.self
+}
+
+// ownsGroup returns true if all rules of the group are evaluated by this instance.
+// The rules that depend on other rules are evaluated by the same member,
+// so that the state of the dependencies is available in its state cache.
+func (r *hashRing) ownsGroup(key ngmodels.AlertRuleGroupKey) bool {
+	return r.groupOwner(key) == r.self
+}
+
+// sameMembers returns true if the ring was built for the provided members.
+func (r *hashRing) sameMembers(self string, members []string) bool {
+	if r.self != self {
+		return false
+	}
+	members = normalizeMembers(self, members)
+	if len(members) != len(r.members) {
+		return false
+	}
+	for i := range members {
+		if members[i] != r.members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeMembers returns a sorted list of unique members that always includes self.
+func normalizeMembers(self string, members []string) []string {
+	unique := make(map[string]struct{}, len(members)+1)
+	unique[self] = struct{}{}
+	for _, member := range members {
+		unique[member] = struct{}{}
+	}
+	result := make([]string, 0, len(unique))
+	for member := range unique {
+		result = append(result, member)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	// We can ignore err as fnv64 does not return an error
+	// nolint:errcheck,gosec
+	h.Write([]byte(s))
+	return h.Sum64()
+}
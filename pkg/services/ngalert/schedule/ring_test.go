@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestHashRing(t *testing.T) {
+	members := []string{"grafana-0", "grafana-1", "grafana-2"}
+	keys := make([]models.AlertRuleKey, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, models.GenerateRuleKey(rand.Int63n(10)+1))
+	}
+
+	t.Run("every rule is owned by exactly one member", func(t *testing.T) {
+		rings := make([]*hashRing, 0, len(members))
+		for _, m := range members {
+			rings = append(rings, newHashRing(m, members))
+		}
+		perMember := make(map[string]int, len(members))
+		for _, key := range keys {
+			owners := 0
+			for _, r := range rings {
+				if r.owns(key) {
+					owners++
+					perMember[r.self]++
+				}
+			}
+			require.Equalf(t, 1, owners, "rule %s is owned by %d members", key, owners)
+		}
+		for _, m := range members {
+			require.Greaterf(t, perMember[m], len(keys)/len(members)/2, "member %s got too few rules: %v", m, perMember)
+		}
+	})
+
+	t.Run("only rules of the member that left move to other members", func(t *testing.T) {
+		before := newHashRing(members[0], members)
+		after := newHashRing(members[0], members[:2])
+		for _, key := range keys {
+			if owner := before.owner(key); owner != members[2] {
+				require.Equal(t, owner, after.owner(key))
+			} else {
+				require.NotEqual(t, members[2], after.owner(key))
+			}
+		}
+	})
+
+	t.Run("ring always includes itself", func(t *testing.T) {
+		r := newHashRing("grafana-0", nil)
+		require.Equal(t, []string{"grafana-0"}, r.members)
+		for _, key := range keys {
+			require.True(t, r.owns(key))
+		}
+	})
+
+	t.Run("rules of groups with dependencies are owned by the member that owns the group", func(t *testing.T) {
+		rings := make([]*hashRing, 0, len(members))
+		for _, m := range members {
+			rings = append(rings, newHashRing(m, members))
+		}
+		for i := 0; i < 100; i++ {
+			groupKey := models.GenerateGroupKey(1)
+			gen := models.AlertRuleGen(models.WithOrgID(groupKey.OrgID), func(rule *models.AlertRule) {
+				rule.NamespaceUID = groupKey.NamespaceUID
+				rule.RuleGroup = groupKey.RuleGroup
+			})
+			dependency := gen()
+			rules := []*models.AlertRule{dependency, gen(), gen()}
+			rules[1].DependsOn = []string{dependency.UID}
+			depths := dependencyDepths(rules)
+			for _, r := range rings {
+				owned := 0
+				for _, rule := range rules {
+					if ownsRule(r, rule, depths) {
+						owned++
+					}
+				}
+				require.Containsf(t, []int{0, len(rules)}, owned, "member %s owns only some rules of group %v", r.self, groupKey)
+				require.Equal(t, r.ownsGroup(groupKey), owned == len(rules))
+			}
+		}
+	})
+
+	t.Run("sameMembers ignores order and duplicates", func(t *testing.T) {
+		r := newHashRing("grafana-0", members)
+		require.True(t, r.sameMembers("grafana-0", []string{"grafana-2", "grafana-1", "grafana-1"}))
+		require.False(t, r.sameMembers("grafana-1", members))
+		require.False(t, r.sameMembers("grafana-0", members[:2]))
+	})
+}
+
+type fakeClusterPeer struct {
+	name    string
+	members []string
+}
+
+func (f *fakeClusterPeer) Name() string {
+	return f.name
+}
+
+func (f *fakeClusterPeer) Peers() []cluster.ClusterMember {
+	result := make([]cluster.ClusterMember, 0, len(f.members))
+	for _, m := range f.members {
+		result = append(result, fakeClusterMember(m))
+	}
+	return result
+}
+
+type fakeClusterMember string
+
+func (f fakeClusterMember) Name() string {
+	return string(f)
+}
+
+func (f fakeClusterMember) Address() string {
+	return fmt.Sprintf("%s:9094", string(f))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -13,6 +14,7 @@ import (
 	prometheusModel "github.com/prometheus/common/model"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -92,6 +94,15 @@ type schedule struct {
 	schedulableAlertRules alertRulesRegistry
 
 	tracer tracing.Tracer
+
+	// peer is the HA peer of this instance. If it is set, alert rules are sharded between the members of the cluster
+	// using the ring, which is rebuilt every time the membership changes.
+	peer ClusterPeer
+	ring *hashRing
+
+	jitterStrategy JitterStrategy
+	// evalSemaphore limits the number of concurrent evaluations. It is nil if the number is not limited.
+	evalSemaphore *semaphore.Weighted
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -107,6 +118,14 @@ type SchedulerCfg struct {
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
+	// Peer is used to shard the evaluation of alert rules between the instances of Grafana in the HA cluster.
+	// If it is nil, all alert rules are evaluated by this instance.
+	Peer ClusterPeer
+	// JitterStrategy defines how the evaluations of rules are spread within their evaluation interval.
+	JitterStrategy JitterStrategy
+	// MaxConcurrentEvaluations limits the number of rules that are evaluated at the same time. Evaluations that
+	// exceed the limit wait in a queue. Zero means no limit.
+	MaxConcurrentEvaluations int64
 }
 
 // NewScheduler returns a new schedule.
@@ -127,6 +146,11 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		peer:                  cfg.Peer,
+		jitterStrategy:        cfg.JitterStrategy,
+	}
+	if cfg.MaxConcurrentEvaluations > 0 {
+		sch.evalSemaphore = semaphore.NewWeighted(cfg.MaxConcurrentEvaluations)
 	}
 
 	return &sch
@@ -191,6 +215,8 @@ func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 type readyToRunItem struct {
 	ruleInfo *alertRuleInfo
 	evaluation
+	// delay is the time since the tick after which the evaluation is started.
+	delay time.Duration
 }
 
 func (sch *schedule) updateRulesMetrics(alertRules []*ngmodels.AlertRule) {
@@ -233,6 +259,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	// this is the new current state. rulesDiff contains the previously existing rules that were different between this state and the previous state.
 	alertRules, folderTitles := sch.schedulableAlertRules.all()
 
+	// if the evaluation of rules is sharded, this instance evaluates only the rules it owns.
+	// When the ring changes, the state of the rules that this instance got from other members is loaded from the database.
+	ringInitialized := sch.ring != nil
+	ring, ringChanged := sch.updateRing()
+
 	// registeredDefinitions is a map used for finding deleted alert rules
 	// initially it is assigned to all known alert rules from the previous cycle
 	// each alert rule found also in this cycle is removed
@@ -241,11 +272,19 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	// the groups where rules depend on each other are evaluated by a single member, and dependencies are evaluated first.
+	depths := dependencyDepths(alertRules)
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
 	for _, item := range alertRules {
 		key := item.GetKey()
+		if ring != nil && !ownsRule(ring, item, depths) {
+			delete(registeredDefinitions, key)
+			sch.releaseAlertRule(key, ringChanged)
+			continue
+		}
 		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
 		// enforce minimum evaluation interval
@@ -257,7 +296,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 		if newRoutine && !invalidInterval {
+			rule := item
 			dispatcherGroup.Go(func() error {
+				if ringInitialized {
+					sch.loadRuleState(ruleInfo.ctx, rule)
+				}
 				return sch.ruleRoutine(ruleInfo.ctx, key, ruleInfo.evalCh, ruleInfo.updateCh)
 			})
 		}
@@ -270,7 +313,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == 0
+		jitterStrategy := sch.jitterStrategy
+		if _, ok := depths[key]; ok && jitterStrategy == JitterByRule {
+			// rules that depend on each other share the offset of their group to be evaluated in the same tick.
+			jitterStrategy = JitterByGroup
+		}
+		offset, delay := jitterOffset(item, sch.baseInterval, jitterStrategy)
+		isReadyToRun := item.IntervalSeconds != 0 && ((tickNum%itemFrequency)+itemFrequency)%itemFrequency == offset
 		if isReadyToRun {
 			var folderTitle string
 			if !sch.disableGrafanaFolder {
@@ -285,7 +334,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 				scheduledAt: tick,
 				rule:        item,
 				folderTitle: folderTitle,
-			}})
+			}, delay: delay})
 		}
 		if _, isUpdated := updated[key]; isUpdated && !isReadyToRun {
 			// if we do not need to eval the rule, check the whether rule was just updated and if it was, notify evaluation routine about that
@@ -310,15 +359,25 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
-	var step int64 = 0
-	if len(readyToRun) > 0 {
-		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
+	// without jitter, evaluations are spread evenly within the tick.
+	if !sch.jitterEnabled() && len(readyToRun) > 0 {
+		if len(depths) > 0 {
+			sort.SliceStable(readyToRun, func(i, j int) bool {
+				return depths[readyToRun[i].rule.GetKey()] < depths[readyToRun[j].rule.GetKey()]
+			})
+		}
+		step := sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
+		for i := range readyToRun {
+			readyToRun[i].delay = time.Duration(int64(i) * step)
+		}
+	} else if len(depths) > 0 {
+		delayDependants(readyToRun, depths, sch.baseInterval)
 	}
 
 	for i := range readyToRun {
 		item := readyToRun[i]
 
-		time.AfterFunc(time.Duration(int64(i)*step), func() {
+		time.AfterFunc(item.delay, func() {
 			key := item.rule.GetKey()
 			success, dropped := item.ruleInfo.eval(&item.evaluation)
 			if !success {
@@ -342,6 +401,106 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	return readyToRun, registeredDefinitions, updatedRules
 }
 
+// dependencyDepths returns the length of the longest chain of dependencies of every rule that belongs to a group
+// where rules depend on each other. The rules of other groups are not included.
+func dependencyDepths(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleKey]int {
+	groups := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRule)
+	withDependencies := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	for _, rule := range rules {
+		key := rule.GetGroupKey()
+		groups[key] = append(groups[key], rule)
+		if len(rule.DependsOn) > 0 {
+			withDependencies[key] = struct{}{}
+		}
+	}
+	result := make(map[ngmodels.AlertRuleKey]int)
+	for key := range withDependencies {
+		group := groups[key]
+		depths := ngmodels.DependencyDepths(group)
+		for _, rule := range group {
+			result[rule.GetKey()] = depths[rule.UID]
+		}
+	}
+	return result
+}
+
+// delayDependants spreads the evaluations of rules that depend on each other over the rest of the tick after the
+// offset of their group, so that with jitter, dependencies are evaluated before the rules that depend on them.
+func delayDependants(items []readyToRunItem, depths map[ngmodels.AlertRuleKey]int, baseInterval time.Duration) {
+	maxDepths := make(map[ngmodels.AlertRuleGroupKey]int)
+	for _, item := range items {
+		groupKey := item.rule.GetGroupKey()
+		if depth := depths[item.rule.GetKey()]; depth > maxDepths[groupKey] {
+			maxDepths[groupKey] = depth
+		}
+	}
+	for i := range items {
+		depth := depths[items[i].rule.GetKey()]
+		if depth == 0 {
+			continue
+		}
+		step := (baseInterval - items[i].delay) / time.Duration(maxDepths[items[i].rule.GetGroupKey()]+1)
+		items[i].delay += time.Duration(depth) * step
+	}
+}
+
+// ownsRule returns true if the rule is evaluated by this instance. The rules of groups
+// where rules depend on each other are assigned to the member that owns the group.
+func ownsRule(ring *hashRing, rule *ngmodels.AlertRule, depths map[ngmodels.AlertRuleKey]int) bool {
+	if _, ok := depths[rule.GetKey()]; ok {
+		return ring.ownsGroup(rule.GetGroupKey())
+	}
+	return ring.owns(rule.GetKey())
+}
+
+func (sch *schedule) jitterEnabled() bool {
+	return sch.jitterStrategy == JitterByGroup || sch.jitterStrategy == JitterByRule
+}
+
+// updateRing rebuilds the hash ring if the members of the cluster changed since the last tick.
+// Returns the current ring, or nil if the evaluation of rules is not sharded, and whether it was rebuilt.
+func (sch *schedule) updateRing() (*hashRing, bool) {
+	if sch.peer == nil {
+		return nil, false
+	}
+	self := sch.peer.Name()
+	peers := sch.peer.Peers()
+	members := make([]string, 0, len(peers))
+	for _, p := range peers {
+		members = append(members, p.Name())
+	}
+	if sch.ring != nil && sch.ring.sameMembers(self, members) {
+		return sch.ring, false
+	}
+	sch.ring = newHashRing(self, members)
+	sch.log.Info("Members of the cluster changed. Alert rules are redistributed", "self", self, "members", sch.ring.members)
+	return sch.ring, true
+}
+
+// releaseAlertRule stops evaluation of the rule that is owned by another member of the cluster. Unlike deleteAlertRule,
+// the state of the rule is kept in the database so the new owner can continue from it.
+func (sch *schedule) releaseAlertRule(key ngmodels.AlertRuleKey, ringChanged bool) {
+	ruleInfo, ok := sch.registry.del(key)
+	if ok {
+		sch.log.Info("Alert rule is evaluated by another member of the cluster. Stopping evaluation", key.LogContext()...)
+		ruleInfo.stop(errRuleNotOwned)
+		return
+	}
+	// the state cache may contain the state of the rule that was warmed up at startup or by an import. It is not updated
+	// by this instance, therefore, it is dropped.
+	if ringChanged {
+		sch.stateManager.ForgetStateByRuleUID(key)
+	}
+}
+
+// loadRuleState replaces the cached state of the rule with the one stored in the database by the previous owner of the rule.
+func (sch *schedule) loadRuleState(ctx context.Context, rule *ngmodels.AlertRule) {
+	key := rule.GetKey()
+	if err := sch.stateManager.LoadStateByRuleUID(ngmodels.WithRuleKey(ctx, key), rule); err != nil {
+		sch.log.Error("Failed to load the state of the alert rule. Evaluation will start from scratch", append(key.LogContext(), "error", err)...)
+	}
+}
+
 func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertRuleKey, evalCh <-chan *evaluation, updateCh <-chan ruleVersionAndPauseStatus) error {
 	grafanaCtx = ngmodels.WithRuleKey(grafanaCtx, key)
 	logger := sch.log.FromContext(grafanaCtx)
@@ -351,6 +510,8 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	evalTotal := sch.metrics.EvalTotal.WithLabelValues(orgID)
 	evalDuration := sch.metrics.EvalDuration.WithLabelValues(orgID)
 	evalTotalFailures := sch.metrics.EvalFailures.WithLabelValues(orgID)
+	evalQueueWait := sch.metrics.EvalQueueWait.WithLabelValues(orgID)
+	evalSkipped := sch.metrics.EvaluationSkipped.WithLabelValues(orgID)
 
 	notify := func(states []state.StateTransition) {
 		expiredAlerts := FromAlertsStateToStoppedAlert(states, sch.appURL, sch.clock)
@@ -387,7 +548,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				},
 			},
 		}
-		evalCtx := eval.Context(ctx, schedulerUser)
+		evalCtx := eval.Context(ctx, schedulerUser).WithAlertingResultsReader(sch.stateManager.AlertingResultsReader(e.rule))
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
 		var results eval.Results
 		var dur time.Duration
@@ -492,9 +653,36 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				continue
 			}
 
+			if sch.evalSemaphore != nil {
+				waitStart := sch.clock.Now()
+				err := sch.evalSemaphore.Acquire(grafanaCtx, 1)
+				evalQueueWait.Observe(sch.clock.Since(waitStart).Seconds())
+				if err != nil {
+					// the routine is stopped. The next iteration handles it.
+					continue
+				}
+				// while the evaluation was waiting in the queue, the next one could have been scheduled.
+				// In this case, the stale evaluation is skipped in favor of the newer one.
+				select {
+				case newer, ok := <-evalCh:
+					if !ok {
+						sch.evalSemaphore.Release(1)
+						logger.Debug("Evaluation channel has been closed. Exiting")
+						return nil
+					}
+					logger.Debug("Skip evaluation because a newer one was scheduled while waiting in the queue", "skipped", ctx.scheduledAt, "next", newer.scheduledAt)
+					evalSkipped.Inc()
+					ctx = newer
+				default:
+				}
+			}
+
 			func() {
 				evalRunning = true
 				defer func() {
+					if sch.evalSemaphore != nil {
+						sch.evalSemaphore.Release(1)
+					}
 					evalRunning = false
 					sch.evalApplied(key, ctx.scheduledAt)
 				}()
@@ -544,6 +732,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				states := sch.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, key), key, ngmodels.StateReasonRuleDeleted)
 				notify(states)
 			}
+			// the rule is evaluated by another member of the cluster, which loads the state from the database.
+			if errors.Is(grafanaCtx.Err(), errRuleNotOwned) {
+				sch.stateManager.ForgetStateByRuleUID(key)
+			}
 			logger.Debug("Stopping alert rule routine")
 			return nil
 		}
@@ -578,5 +770,12 @@ func (sch *schedule) getRuleExtraLabels(evalCtx *evaluation) map[string]string {
 	if !sch.disableGrafanaFolder {
 		extraLabels[ngmodels.FolderTitleLabel] = evalCtx.folderTitle
 	}
+
+	// The alerts of rules with notification settings are dispatched by the routes generated from the settings.
+	if settings := evalCtx.rule.GetNotificationSettings(); settings != nil {
+		for k, v := range settings.ToLabels() {
+			extraLabels[k] = v
+		}
+	}
 	return extraLabels
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -351,6 +352,196 @@ func TestProcessTicks(t *testing.T) {
 	})
 }
 
+func TestProcessTicks_Sharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sched := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+	peer := &fakeClusterPeer{name: "grafana-0", members: []string{"grafana-0", "grafana-1"}}
+	sched.peer = peer
+
+	evalAppliedCh := make(chan evalAppliedInfo, 2)
+	stopAppliedCh := make(chan models.AlertRuleKey, 2)
+	sched.evalAppliedFunc = func(alertDefKey models.AlertRuleKey, now time.Time) {
+		evalAppliedCh <- evalAppliedInfo{alertDefKey: alertDefKey, now: now}
+	}
+	sched.stopAppliedFunc = func(alertDefKey models.AlertRuleKey) {
+		stopAppliedCh <- alertDefKey
+	}
+
+	// find a rule for every member of the cluster
+	ring := newHashRing(peer.name, peer.members)
+	var ownRule, otherRule *models.AlertRule
+	gen := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Second), withQueryForState(t, eval.Normal))
+	for ownRule == nil || otherRule == nil {
+		rule := gen()
+		if ring.owns(rule.GetKey()) {
+			ownRule = rule
+		} else {
+			otherRule = rule
+		}
+	}
+	ruleStore.PutRule(ctx, ownRule, otherRule)
+
+	loadedRules := func() []string {
+		var result []string
+		for _, op := range instanceStore.RecordedOps {
+			if q, ok := op.(models.ListAlertInstancesQuery); ok {
+				result = append(result, q.RuleUID)
+			}
+		}
+		return result
+	}
+
+	tick := time.Time{}
+
+	t.Run("should evaluate only rules owned by the instance", func(t *testing.T) {
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+
+		require.Len(t, scheduled, 1)
+		require.Equal(t, ownRule, scheduled[0].rule)
+		require.Empty(t, stopped)
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey())
+		require.Empty(t, loadedRules(), "state should not be loaded on startup because the cache is warmed up")
+	})
+
+	t.Run("should take over rules of the member that left and load their state", func(t *testing.T) {
+		peer.members = []string{"grafana-0"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+
+		require.Len(t, scheduled, 2)
+		require.Empty(t, stopped)
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey(), otherRule.GetKey())
+		require.Equal(t, []string{otherRule.UID}, loadedRules())
+	})
+
+	t.Run("should stop rules of the member that joined", func(t *testing.T) {
+		peer.members = []string{"grafana-0", "grafana-1"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+
+		require.Len(t, scheduled, 1)
+		require.Equal(t, ownRule, scheduled[0].rule)
+		require.Empty(t, stopped, "rule evaluated by other member should not be reported as deleted")
+		assertStopRun(t, stopAppliedCh, otherRule.GetKey())
+		assertEvalRun(t, evalAppliedCh, tick, ownRule.GetKey())
+		require.False(t, sched.registry.exists(otherRule.GetKey()))
+	})
+}
+
+func TestProcessTicks_Dependencies(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	groupKey := models.GenerateGroupKey(1)
+	gen := models.AlertRuleGen(models.WithOrgID(groupKey.OrgID), models.WithInterval(time.Second), withQueryForState(t, eval.Normal), func(rule *models.AlertRule) {
+		rule.NamespaceUID = groupKey.NamespaceUID
+		rule.RuleGroup = groupKey.RuleGroup
+	})
+	database := gen()
+	backend := gen()
+	backend.DependsOn = []string{database.UID}
+	frontend := gen()
+	frontend.DependsOn = []string{backend.UID}
+	ruleStore.PutRule(ctx, frontend, backend, database)
+
+	scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, time.Time{}.Add(time.Second))
+
+	require.Len(t, scheduled, 3)
+	require.Equal(t, database.UID, scheduled[0].rule.UID)
+	require.Equal(t, backend.UID, scheduled[1].rule.UID)
+	require.Equal(t, frontend.UID, scheduled[2].rule.UID)
+	require.Less(t, scheduled[0].delay, scheduled[1].delay)
+	require.Less(t, scheduled[1].delay, scheduled[2].delay)
+}
+
+func TestProcessTicks_Jitter(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sched.jitterStrategy = JitterByRule
+
+	const intervalTicks = 10
+	rules := models.GenerateAlertRules(20, models.AlertRuleGen(models.WithInterval(intervalTicks*sched.baseInterval), withQueryForState(t, eval.Normal)))
+	ruleStore.PutRule(ctx, rules...)
+
+	scheduledAt := make(map[models.AlertRuleKey][]int)
+	tick := time.Unix(0, 0)
+	for i := 0; i < 2*intervalTicks; i++ {
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		for _, item := range scheduled {
+			_, delay := jitterOffset(item.rule, sched.baseInterval, sched.jitterStrategy)
+			require.Equal(t, delay, item.delay)
+			scheduledAt[item.rule.GetKey()] = append(scheduledAt[item.rule.GetKey()], i)
+		}
+		tick = tick.Add(sched.baseInterval)
+	}
+
+	offsets := make(map[int]struct{})
+	for _, rule := range rules {
+		ticks := scheduledAt[rule.GetKey()]
+		require.Lenf(t, ticks, 2, "rule should be evaluated once per interval")
+		require.Equal(t, intervalTicks, ticks[1]-ticks[0])
+		offset, _ := jitterOffset(rule, sched.baseInterval, sched.jitterStrategy)
+		require.EqualValues(t, offset, ticks[0])
+		offsets[ticks[0]] = struct{}{}
+	}
+	require.Greater(t, len(offsets), 1, "rules should be spread within the interval")
+}
+
+func TestProcessTicks_DependenciesWithJitter(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sched.jitterStrategy = JitterByRule
+
+	const intervalTicks = 10
+	groupKey := models.GenerateGroupKey(1)
+	gen := models.AlertRuleGen(models.WithOrgID(groupKey.OrgID), models.WithInterval(intervalTicks*sched.baseInterval), withQueryForState(t, eval.Normal), func(rule *models.AlertRule) {
+		rule.NamespaceUID = groupKey.NamespaceUID
+		rule.RuleGroup = groupKey.RuleGroup
+	})
+	database := gen()
+	backend := gen()
+	backend.DependsOn = []string{database.UID}
+	frontend := gen()
+	frontend.DependsOn = []string{backend.UID}
+	ruleStore.PutRule(ctx, frontend, backend, database)
+
+	tick := time.Unix(0, 0)
+	for i := 0; i < intervalTicks; i++ {
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		tick = tick.Add(sched.baseInterval)
+		if len(scheduled) == 0 {
+			continue
+		}
+
+		require.Len(t, scheduled, 3, "rules that depend on each other should be evaluated in the same tick")
+		delays := make(map[string]time.Duration, len(scheduled))
+		for _, item := range scheduled {
+			delays[item.rule.UID] = item.delay
+			require.Less(t, item.delay, sched.baseInterval)
+		}
+		_, groupDelay := jitterOffset(database, sched.baseInterval, JitterByGroup)
+		require.Equal(t, groupDelay, delays[database.UID])
+		require.Less(t, delays[database.UID], delays[backend.UID])
+		require.Less(t, delays[backend.UID], delays[frontend.UID])
+		return
+	}
+	require.Fail(t, "rules should be evaluated once per interval")
+}
+
 func TestSchedule_ruleRoutine(t *testing.T) {
 	createSchedule := func(
 		evalAppliedChan chan time.Time,
@@ -743,6 +934,55 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 	})
 }
 
+func TestSchedule_ruleRoutine_ConcurrencyLimit(t *testing.T) {
+	evalAppliedChan := make(chan time.Time)
+	ruleStore := newFakeRulesStore()
+	registry := prometheus.NewPedanticRegistry()
+	sch := setupScheduler(t, ruleStore, nil, registry, nil, nil)
+	sch.evalSemaphore = semaphore.NewWeighted(1)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, t time.Time) {
+		evalAppliedChan <- t
+	}
+
+	rule := models.AlertRuleGen(withQueryForState(t, eval.Normal))()
+	ruleStore.PutRule(context.Background(), rule)
+
+	evalChan := make(chan *evaluation)
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+	}()
+
+	// occupy the only slot so the evaluation has to wait in the queue
+	require.NoError(t, sch.evalSemaphore.Acquire(context.Background(), 1))
+
+	staleTime := time.UnixMicro(rand.Int63())
+	evalChan <- &evaluation{scheduledAt: staleTime, rule: rule}
+	nextTime := staleTime.Add(time.Second)
+	go func() {
+		evalChan <- &evaluation{scheduledAt: nextTime, rule: rule}
+	}()
+	// give the next evaluation time to be scheduled while the stale one is waiting in the queue.
+	time.Sleep(100 * time.Millisecond)
+	sch.evalSemaphore.Release(1)
+
+	actualTime := waitForTimeChannel(t, evalAppliedChan)
+	require.Equal(t, nextTime, actualTime)
+
+	expectedMetric := fmt.Sprintf(
+		`# HELP grafana_alerting_schedule_rule_evaluations_skipped_total The total number of rule evaluations skipped because a newer evaluation was scheduled while waiting in the evaluation queue.
+		# TYPE grafana_alerting_schedule_rule_evaluations_skipped_total counter
+		grafana_alerting_schedule_rule_evaluations_skipped_total{org="%[1]d"} 1
+	`, rule.OrgID)
+	require.NoError(t, testutil.GatherAndCompare(registry, bytes.NewBufferString(expectedMetric), "grafana_alerting_schedule_rule_evaluations_skipped_total"))
+
+	t.Run("should release the slot after evaluation", func(t *testing.T) {
+		require.True(t, sch.evalSemaphore.TryAcquire(1))
+		sch.evalSemaphore.Release(1)
+	})
+}
+
 func TestSchedule_deleteAlertRule(t *testing.T) {
 	t.Run("when rule exists", func(t *testing.T) {
 		t.Run("it should stop evaluation loop and remove the controller from registry", func(t *testing.T) {
@@ -929,3 +1169,26 @@ func assertStopRun(t *testing.T, ch <-chan models.AlertRuleKey, keys ...models.A
 		}
 	}
 }
+
+func TestSchedule_getRuleExtraLabels(t *testing.T) {
+	sch := setupScheduler(t, newFakeRulesStore(), &state.FakeInstanceStore{}, prometheus.NewPedanticRegistry(), nil, nil)
+
+	t.Run("should not add route labels if rule has no notification settings", func(t *testing.T) {
+		rule := models.AlertRuleGen()()
+		rule.NotificationSettings = nil
+		labels := sch.getRuleExtraLabels(&evaluation{rule: rule, folderTitle: "folder"})
+		require.NotContains(t, labels, models.AutogeneratedRouteLabel)
+		require.NotContains(t, labels, models.AutogeneratedRouteReceiverNameLabel)
+		require.Equal(t, "folder", labels[models.FolderTitleLabel])
+	})
+
+	t.Run("should add route labels of notification settings", func(t *testing.T) {
+		settings := models.NotificationSettings{Receiver: "team-a", GroupBy: []string{"alertname"}}
+		rule := models.AlertRuleGen(models.WithNotificationSettings(settings))()
+		labels := sch.getRuleExtraLabels(&evaluation{rule: rule, folderTitle: "folder"})
+		require.Equal(t, "true", labels[models.AutogeneratedRouteLabel])
+		require.Equal(t, "team-a", labels[models.AutogeneratedRouteReceiverNameLabel])
+		require.Equal(t, settings.Fingerprint(), labels[models.AutogeneratedRouteSettingsHashLabel])
+		require.Equal(t, rule.UID, labels[alertingModels.RuleUIDLabel])
+	})
+}
@@ -2,31 +2,380 @@ package historian
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 )
 
+const (
+	stateHistoryTable = "alert_state_history"
+	// defaultSQLBatchSize is the number of rows written per statement when no batch size is configured.
+	// This keeps statements below the bind variable limits of the supported databases.
+	defaultSQLBatchSize = 100
+	// sqlCleanupInterval is the minimum time between two retention cleanups.
+	sqlCleanupInterval = 10 * time.Minute
+	// defaultSQLQueryLimit is the maximum number of entries returned by a query that is not restricted to a rule.
+	// Like Loki, which returns a limited number of the most recent entries, only the most recent entries are returned.
+	defaultSQLQueryLimit = 1000
+)
+
+type SqlConfig struct {
+	// Retention is how long entries are kept. Entries older than this are deleted periodically. Zero disables cleanup.
+	Retention time.Duration
+	// BatchSize is the maximum number of rows inserted by a single statement.
+	BatchSize int
+	// ExternalLabels are added to the labels of every recorded entry.
+	ExternalLabels map[string]string
+}
+
+// stateHistoryRow is the database representation of a single state transition.
+type stateHistoryRow struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	RuleGroup     string `xorm:"rule_group"`
+	NamespaceUID  string `xorm:"namespace_uid"`
+	DashboardUID  string `xorm:"dashboard_uid"`
+	PanelID       int64  `xorm:"panel_id"`
+	Labels        string `xorm:"labels"`
+	PreviousState string `xorm:"previous_state"`
+	CurrentState  string `xorm:"current_state"`
+	Error         string `xorm:"error"`
+	StateValues   string `xorm:"state_values"`
+	TimeNano      int64  `xorm:"time_nano"`
+}
+
+// SqlBackend is a state.Historian that records state history to the Grafana database.
 type SqlBackend struct {
-	log log.Logger
+	db             db.DB
+	retention      time.Duration
+	batchSize      int
+	queryLimit     int
+	externalLabels map[string]string
+	clock          clock.Clock
+	metrics        *metrics.Historian
+	log            log.Logger
+
+	cleanupMtx  sync.Mutex
+	lastCleanup time.Time
 }
 
-func NewSqlBackend() *SqlBackend {
+func NewSqlBackend(cfg SqlConfig, store db.DB, metrics *metrics.Historian) *SqlBackend {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSQLBatchSize
+	}
 	return &SqlBackend{
-		log: log.New("ngalert.state.historian"),
+		db:             store,
+		retention:      cfg.Retention,
+		batchSize:      batchSize,
+		queryLimit:     defaultSQLQueryLimit,
+		externalLabels: cfg.ExternalLabels,
+		clock:          clock.New(),
+		metrics:        metrics,
+		log:            log.New("ngalert.state.historian", "backend", "sql"),
 	}
 }
 
-func (h *SqlBackend) Record(ctx context.Context, _ history_model.RuleMeta, _ []state.StateTransition) <-chan error {
-	errCh := make(chan error)
-	close(errCh)
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SqlBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build rows before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	rows := statesToRows(rule, states, h.externalLabels, logger)
+
+	errCh := make(chan error, 1)
+	if len(rows) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	go func() {
+		defer close(errCh)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org).Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(rows)))
+
+		if err := h.insertRows(ctx, rows); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org).Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(rows)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "rows", len(rows))
+
+		if err := h.cleanupIfDue(ctx); err != nil {
+			logger.Error("Failed to delete expired alert state history", "error", err)
+		}
+	}()
 	return errCh
 }
 
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+// The resulting frame has the same shape as the one produced by the Loki backend.
+// If the query is not restricted to a rule, only the most recent entries of the organization are returned.
 func (h *SqlBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
-	return data.NewFrame("states"), nil
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+
+	var rows []stateHistoryRow
+	matchers := query.Labels
+	err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if query.RuleUID != "" {
+			rows = make([]stateHistoryRow, 0)
+			return sess.Table(stateHistoryTable).
+				Where("org_id = ?", query.OrgID).
+				And("rule_uid = ?", query.RuleUID).
+				And("time_nano >= ?", query.From.UnixNano()).
+				And("time_nano <= ?", query.To.UnixNano()).
+				Asc("time_nano", "id").
+				Find(&rows)
+		}
+		var err error
+		rows, err = h.findRecentRows(sess, query)
+		// The labels of the recent entries are already matched.
+		matchers = nil
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+
+	return rowsToFrame(rows, matchers)
+}
+
+// findRecentRows returns the most recent entries of the organization that match the labels of the query, at most
+// queryLimit, in chronological order. The labels are stored as JSON and cannot be matched by the database, so the
+// entries are read page by page until enough of them match.
+func (h *SqlBackend) findRecentRows(sess *db.Session, query models.HistoryQuery) ([]stateHistoryRow, error) {
+	result := make([]stateHistoryRow, 0)
+	before, beforeID := query.To.UnixNano(), int64(0)
+	for len(result) < h.queryLimit {
+		page := make([]stateHistoryRow, 0, h.queryLimit)
+		q := sess.Table(stateHistoryTable).
+			Where("org_id = ?", query.OrgID).
+			And("time_nano >= ?", query.From.UnixNano())
+		if beforeID == 0 {
+			q = q.And("time_nano <= ?", before)
+		} else {
+			// Continue after the last entry of the previous page.
+			q = q.And("(time_nano < ? OR (time_nano = ? AND id < ?))", before, before, beforeID)
+		}
+		if err := q.Desc("time_nano", "id").Limit(h.queryLimit).Find(&page); err != nil {
+			return nil, err
+		}
+
+		for _, row := range page {
+			ok, err := rowMatches(row, query.Labels)
+			if err != nil {
+				return nil, err
+			}
+			if ok && len(result) < h.queryLimit {
+				result = append(result, row)
+			}
+		}
+		if len(page) < h.queryLimit {
+			break
+		}
+		last := page[len(page)-1]
+		before, beforeID = last.TimeNano, last.ID
+	}
+
+	// The most recent entries were selected first, restore the chronological order.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// insertRows writes the rows in batches of at most batchSize rows per statement, within a single transaction.
+func (h *SqlBackend) insertRows(ctx context.Context, rows []stateHistoryRow) error {
+	return h.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for start := 0; start < len(rows); start += h.batchSize {
+			end := start + h.batchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			batch := rows[start:end]
+			if _, err := sess.Table(stateHistoryTable).InsertMulti(&batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// cleanupIfDue deletes expired entries, at most once per sqlCleanupInterval.
+func (h *SqlBackend) cleanupIfDue(ctx context.Context) error {
+	if h.retention <= 0 {
+		return nil
+	}
+	now := h.clock.Now()
+	h.cleanupMtx.Lock()
+	if now.Sub(h.lastCleanup) < sqlCleanupInterval {
+		h.cleanupMtx.Unlock()
+		return nil
+	}
+	h.lastCleanup = now
+	h.cleanupMtx.Unlock()
+
+	_, err := h.DeleteExpired(ctx)
+	return err
+}
+
+// DeleteExpired deletes all entries older than the configured retention and returns the number of deleted rows.
+func (h *SqlBackend) DeleteExpired(ctx context.Context) (int64, error) {
+	if h.retention <= 0 {
+		return 0, nil
+	}
+	cutoff := h.clock.Now().Add(-h.retention).UnixNano()
+	var affected int64
+	err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM "+stateHistoryTable+" WHERE time_nano < ?", cutoff)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if affected > 0 {
+		h.log.Debug("Deleted expired alert state history", "count", affected)
+	}
+	return affected, nil
+}
+
+func statesToRows(rule history_model.RuleMeta, states []state.StateTransition, externalLabels map[string]string, logger log.Logger) []stateHistoryRow {
+	rows := make([]stateHistoryRow, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		labels := mergeLabels(removePrivateLabels(state.State.Labels), externalLabels)
+		labels[StateHistoryLabelKey] = StateHistoryLabelValue
+		labels[OrgIDLabel] = fmt.Sprint(rule.OrgID)
+		labels[RuleUIDLabel] = fmt.Sprint(rule.UID)
+		labels[GroupLabel] = fmt.Sprint(rule.Group)
+		labels[FolderUIDLabel] = fmt.Sprint(rule.NamespaceUID)
+		lblJsn, err := json.Marshal(labels)
+		if err != nil {
+			logger.Error("Failed to marshal labels to JSON", "error", err)
+			continue
+		}
+
+		vals, err := json.Marshal(valuesAsDataBlob(state.State))
+		if err != nil {
+			logger.Error("Failed to marshal state values to JSON, skipping", "error", err)
+			continue
+		}
+
+		row := stateHistoryRow{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			RuleGroup:     rule.Group,
+			NamespaceUID:  rule.NamespaceUID,
+			DashboardUID:  rule.DashboardUID,
+			PanelID:       rule.PanelID,
+			Labels:        string(lblJsn),
+			PreviousState: state.PreviousFormatted(),
+			CurrentState:  state.Formatted(),
+			StateValues:   string(vals),
+			TimeNano:      state.State.LastEvaluationTime.UnixNano(),
+		}
+		if state.State.State == eval.Error && state.Error != nil {
+			row.Error = state.Error.Error()
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// rowsToFrame converts rows, which must be sorted by time, into the state history frame format.
+// Rows whose labels do not match all the given labels are omitted.
+func rowsToFrame(rows []stateHistoryRow, matchers map[string]string) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(rows))
+	lines := make([]json.RawMessage, 0, len(rows))
+	labels := make([]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		ok, err := rowMatches(row, matchers)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		vals, err := simplejson.NewJson([]byte(row.StateValues))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal values of entry %d: %w", row.ID, err)
+		}
+		line, err := json.Marshal(lokiEntry{
+			SchemaVersion: 1,
+			Previous:      row.PreviousState,
+			Current:       row.CurrentState,
+			Error:         row.Error,
+			Values:        vals,
+			DashboardUID:  row.DashboardUID,
+			PanelID:       row.PanelID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize entry %d: %w", row.ID, err)
+		}
+
+		times = append(times, time.Unix(0, row.TimeNano))
+		lines = append(lines, line)
+		labels = append(labels, json.RawMessage(row.Labels))
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+
+	return frame, nil
+}
+
+// rowMatches returns whether the labels of the row match all the given labels.
+func rowMatches(row stateHistoryRow, matchers map[string]string) (bool, error) {
+	if len(matchers) == 0 {
+		return true, nil
+	}
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(row.Labels), &labels); err != nil {
+		return false, fmt.Errorf("failed to unmarshal labels of entry %d: %w", row.ID, err)
+	}
+	return labelsMatch(labels, matchers), nil
+}
+
+func labelsMatch(labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,277 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestSqlBackend(t *testing.T) {
+	t.Run("statesToRows", func(t *testing.T) {
+		t.Run("skips non-transitory states", func(t *testing.T) {
+			rule := createTestRule()
+			states := singleFromNormal(&state.State{State: eval.Normal})
+
+			res := statesToRows(rule, states, nil, log.NewNopLogger())
+
+			require.Empty(t, res)
+		})
+
+		t.Run("maps evaluation errors", func(t *testing.T) {
+			rule := createTestRule()
+			states := singleFromNormal(&state.State{State: eval.Error, Error: fmt.Errorf("oh no")})
+
+			res := statesToRows(rule, states, nil, log.NewNopLogger())
+
+			require.Len(t, res, 1)
+			require.Contains(t, res[0].Error, "oh no")
+		})
+
+		t.Run("includes rule and external labels", func(t *testing.T) {
+			rule := createTestRule()
+			states := singleFromNormal(&state.State{
+				State:  eval.Alerting,
+				Labels: data.Labels{"a": "b", "__private__": "c"},
+			})
+
+			res := statesToRows(rule, states, map[string]string{"extLabel": "val"}, log.NewNopLogger())
+
+			require.Len(t, res, 1)
+			var lbls map[string]string
+			require.NoError(t, json.Unmarshal([]byte(res[0].Labels), &lbls))
+			require.Equal(t, map[string]string{
+				StateHistoryLabelKey: StateHistoryLabelValue,
+				OrgIDLabel:           "1",
+				RuleUIDLabel:         "rule-uid",
+				GroupLabel:           "my-group",
+				FolderUIDLabel:       "my-folder",
+				"a":                  "b",
+				"extLabel":           "val",
+			}, lbls)
+		})
+	})
+
+	t.Run("rowsToFrame", func(t *testing.T) {
+		t.Run("produces the same frame shape as the loki backend", func(t *testing.T) {
+			rows := statesToRows(createTestRule(), singleFromNormal(&state.State{State: eval.Alerting}), nil, log.NewNopLogger())
+
+			frame, err := rowsToFrame(rows, nil)
+
+			require.NoError(t, err)
+			require.Equal(t, "states", frame.Name)
+			require.Len(t, frame.Fields, 3)
+			require.Equal(t, dfTime, frame.Fields[0].Name)
+			require.Equal(t, dfLine, frame.Fields[1].Name)
+			require.Equal(t, dfLabels, frame.Fields[2].Name)
+			require.Equal(t, 1, frame.Rows())
+
+			var entry lokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(0).(json.RawMessage), &entry))
+			require.Equal(t, "Normal", entry.Previous)
+			require.Equal(t, "Alerting", entry.Current)
+			require.Equal(t, "dash-uid", entry.DashboardUID)
+			require.Equal(t, int64(123), entry.PanelID)
+		})
+
+		t.Run("filters rows by labels", func(t *testing.T) {
+			rule := createTestRule()
+			states := []state.StateTransition{
+				{PreviousState: eval.Normal, State: &state.State{State: eval.Alerting, Labels: data.Labels{"a": "b"}}},
+				{PreviousState: eval.Normal, State: &state.State{State: eval.Alerting, Labels: data.Labels{"a": "c"}}},
+			}
+			rows := statesToRows(rule, states, nil, log.NewNopLogger())
+
+			frame, err := rowsToFrame(rows, map[string]string{"a": "c"})
+
+			require.NoError(t, err)
+			require.Equal(t, 1, frame.Rows())
+		})
+	})
+}
+
+func TestIntegrationSqlBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	t.Run("recorded transitions are queryable", func(t *testing.T) {
+		h := createTestSqlBackendSut(t, SqlConfig{BatchSize: 2})
+		rule := createTestRule()
+		start := time.Now()
+		states := make([]state.StateTransition, 0, 5)
+		for i := 0; i < 5; i++ {
+			states = append(states, state.StateTransition{
+				PreviousState: eval.Normal,
+				State: &state.State{
+					State:              eval.Alerting,
+					Labels:             data.Labels{"instance": fmt.Sprint(i)},
+					LastEvaluationTime: start.Add(time.Duration(i) * time.Second),
+				},
+			})
+		}
+
+		require.NoError(t, <-h.Record(context.Background(), rule, states))
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID:   rule.OrgID,
+			RuleUID: rule.UID,
+			From:    start.Add(-time.Minute),
+			To:      start.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, frame.Rows())
+		for i := 1; i < frame.Rows(); i++ {
+			require.True(t, frame.Fields[0].At(i-1).(time.Time).Before(frame.Fields[0].At(i).(time.Time)))
+		}
+	})
+
+	t.Run("query honours rule UID, labels and time range", func(t *testing.T) {
+		h := createTestSqlBackendSut(t, SqlConfig{})
+		rule := createTestRule()
+		other := createTestRule()
+		other.UID = "other-rule"
+		now := time.Now()
+
+		require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+			State: eval.Alerting, Labels: data.Labels{"a": "b"}, LastEvaluationTime: now,
+		})))
+		require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+			State: eval.Alerting, Labels: data.Labels{"a": "c"}, LastEvaluationTime: now,
+		})))
+		require.NoError(t, <-h.Record(context.Background(), other, singleFromNormal(&state.State{
+			State: eval.Alerting, Labels: data.Labels{"a": "b"}, LastEvaluationTime: now,
+		})))
+		require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+			State: eval.Alerting, Labels: data.Labels{"a": "b"}, LastEvaluationTime: now.Add(-2 * time.Hour),
+		})))
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID:   rule.OrgID,
+			RuleUID: rule.UID,
+			Labels:  map[string]string{"a": "b"},
+			From:    now.Add(-time.Hour),
+			To:      now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+
+		frame, err = h.Query(context.Background(), models.HistoryQuery{
+			OrgID: rule.OrgID,
+			From:  now.Add(-time.Hour),
+			To:    now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+	})
+
+	t.Run("query without rule UID returns the most recent entries", func(t *testing.T) {
+		h := createTestSqlBackendSut(t, SqlConfig{})
+		h.queryLimit = 3
+		rule := createTestRule()
+		other := createTestRule()
+		other.UID = "other-rule"
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			r := rule
+			if i%2 == 1 {
+				r = other
+			}
+			require.NoError(t, <-h.Record(context.Background(), r, singleFromNormal(&state.State{
+				State: eval.Alerting, Labels: data.Labels{"i": fmt.Sprint(i)}, LastEvaluationTime: start.Add(time.Duration(i) * time.Second),
+			})))
+		}
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID: rule.OrgID,
+			From:  start.Add(-time.Minute),
+			To:    start.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, start.Add(time.Duration(i+2)*time.Second).UnixNano(), frame.Fields[0].At(i).(time.Time).UnixNano())
+		}
+
+		frame, err = h.Query(context.Background(), models.HistoryQuery{
+			OrgID:   rule.OrgID,
+			RuleUID: rule.UID,
+			From:    start.Add(-time.Minute),
+			To:      start.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+	})
+
+	t.Run("query without rule UID returns the most recent entries matching the labels", func(t *testing.T) {
+		h := createTestSqlBackendSut(t, SqlConfig{})
+		h.queryLimit = 2
+		rule := createTestRule()
+		start := time.Now()
+		// The matching entries are older than the first pages of entries.
+		for i := 0; i < 8; i++ {
+			team := "b"
+			if i < 3 {
+				team = "a"
+			}
+			require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+				State: eval.Alerting, Labels: data.Labels{"team": team}, LastEvaluationTime: start.Add(time.Duration(i) * time.Second),
+			})))
+		}
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID:  rule.OrgID,
+			Labels: map[string]string{"team": "a"},
+			From:   start.Add(-time.Minute),
+			To:     start.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, start.Add(time.Duration(i+1)*time.Second).UnixNano(), frame.Fields[0].At(i).(time.Time).UnixNano())
+		}
+	})
+
+	t.Run("expired entries are deleted", func(t *testing.T) {
+		h := createTestSqlBackendSut(t, SqlConfig{Retention: time.Hour})
+		clk := clock.NewMock()
+		clk.Set(time.Now())
+		h.clock = clk
+		rule := createTestRule()
+
+		require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+			State: eval.Alerting, LastEvaluationTime: clk.Now().Add(-2 * time.Hour),
+		})))
+		require.NoError(t, <-h.Record(context.Background(), rule, singleFromNormal(&state.State{
+			State: eval.Alerting, LastEvaluationTime: clk.Now(),
+		})))
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID: rule.OrgID,
+			From:  clk.Now().Add(-3 * time.Hour),
+			To:    clk.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+	})
+}
+
+func createTestSqlBackendSut(t *testing.T, cfg SqlConfig) *SqlBackend {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+	return NewSqlBackend(cfg, sqlStore, met)
+}
@@ -3,10 +3,13 @@ package state
 import (
 	"context"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
+	alertingModels "github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -40,6 +43,10 @@ type Manager struct {
 	externalURL   *url.URL
 
 	doNotSaveNormalState bool
+
+	// replaceMtx is held for reading while states are changed and persisted, and for writing while the persisted
+	// states are replaced, see Replace.
+	replaceMtx sync.RWMutex
 }
 
 type ManagerCfg struct {
@@ -136,24 +143,8 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
+			s := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
@@ -161,6 +152,67 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// Replace calls replace, which is expected to replace the alert instances in the instance store, and then loads the
+// state cache from the instance store. Evaluation results are not processed in the meantime, so that they neither
+// overwrite the new alert instances nor get lost. The cache is loaded even if replace fails, because the
+// alert instances could be partially replaced.
+func (st *Manager) Replace(ctx context.Context, rulesReader RuleReader, replace func(ctx context.Context) error) error {
+	st.replaceMtx.Lock()
+	defer st.replaceMtx.Unlock()
+
+	err := replace(ctx)
+	st.Warm(ctx, rulesReader)
+	return err
+}
+
+// LoadStateByRuleUID replaces the cached state of the rule with the state persisted in the instance store.
+// It is used when the rule was evaluated by another instance of Grafana, and therefore the cache might not be up-to-date.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) error {
+	if st.instanceStore == nil {
+		return nil
+	}
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	}
+	if err := st.instanceStore.ListAlertInstances(ctx, &cmd); err != nil {
+		return err
+	}
+	rulesStates := &ruleStates{states: make(map[string]*State, len(cmd.Result))}
+	for _, entry := range cmd.Result {
+		s := st.stateFromInstance(entry, rule)
+		rulesStates.states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.GetKey(), rulesStates)
+	return nil
+}
+
+// ForgetStateByRuleUID removes the state of the rule from the cache but, unlike DeleteStateByRuleUID, keeps it in the
+// instance store. It is used when another instance of Grafana takes over the evaluation of the rule.
+func (st *Manager) ForgetStateByRuleUID(ruleKey ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("Error getting cacheId for entry", "error", err)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          rule.Annotations,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -169,6 +221,9 @@ func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
 // to gracefully handle the clear state step in scheduler in case we do not need to use the historian to save state
 // history.
 func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.AlertRuleKey, reason string) []StateTransition {
+	st.replaceMtx.RLock()
+	defer st.replaceMtx.RUnlock()
+
 	logger := st.log.FromContext(ctx)
 	logger.Debug("Resetting state of the rule")
 
@@ -235,12 +290,20 @@ func (st *Manager) ResetStateByRuleUID(ctx context.Context, rule *ngModels.Alert
 // ProcessEvalResults updates the current states that belong to a rule with the evaluation results.
 // if extraLabels is not empty, those labels will be added to every state. The extraLabels take precedence over rule labels and result labels
 func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels) []StateTransition {
+	st.replaceMtx.RLock()
+	defer st.replaceMtx.RUnlock()
+
 	logger := st.log.FromContext(ctx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
 	states := make([]StateTransition, 0, len(results))
 
+	suppressedBy := st.firingDependency(alertRule)
+	if suppressedBy != "" {
+		logger.Debug("Suppressing notifications because a rule the rule depends on is firing", "dependency", suppressedBy)
+	}
+
 	for _, result := range results {
-		s := st.setNextState(ctx, alertRule, result, extraLabels, logger)
+		s := st.setNextState(ctx, alertRule, result, extraLabels, suppressedBy != "", logger)
 		states = append(states, s)
 	}
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
@@ -255,8 +318,22 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	return allChanges
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, logger log.Logger) StateTransition {
+// firingDependency returns the UID of the first rule that the rule depends on and that has an Alerting state.
+// Returns an empty string if none of them is firing. Rules that do not exist anymore are ignored.
+func (st *Manager) firingDependency(alertRule *ngModels.AlertRule) string {
+	for _, uid := range alertRule.DependsOn {
+		for _, s := range st.cache.getStatesForRuleUID(alertRule.OrgID, uid, true) {
+			if s.State == eval.Alerting {
+				return uid
+			}
+		}
+	}
+	return ""
+}
+
+// Set the current state based on evaluation results.
+// If suppressed is true, the reason Suppressed is added to the instances that are not Normal or Pending so that they are not sent to the Alertmanager.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, suppressed bool, logger log.Logger) StateTransition {
 	currentState := st.cache.getOrCreate(ctx, st.log, alertRule, result, extraLabels, st.externalURL)
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
@@ -292,15 +369,26 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		logger.Debug("Ignoring set next state as result is pending")
 	}
 
+	// The instance can only be kept firing while it is Alerting
+	if currentState.State != eval.Alerting {
+		currentState.KeepFiringSince = time.Time{}
+	}
+
 	// Set reason iff: result and state are different, reason is not Alerting or Normal
 	currentState.StateReason = ""
 
-	if currentState.State != result.State &&
+	if !currentState.KeepFiringSince.IsZero() {
+		currentState.StateReason = ngModels.StateReasonKeepFiring
+	} else if currentState.State != result.State &&
 		result.State != eval.Normal &&
 		result.State != eval.Alerting {
 		currentState.StateReason = result.State.String()
 	}
 
+	if suppressed && (currentState.State == eval.Alerting || currentState.State == eval.NoData || currentState.State == eval.Error) {
+		currentState.StateReason = ngModels.ConcatReasons(currentState.StateReason, ngModels.StateReasonSuppressed)
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
@@ -336,6 +424,44 @@ func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*Stat
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID, st.doNotSaveNormalState)
 }
 
+// AlertingResultsReader returns a reader of the labels of the evaluation results of the rule that are alerting.
+func (st *Manager) AlertingResultsReader(rule *ngModels.AlertRule) eval.AlertingResultsReader {
+	return alertingResultsReader{manager: st, rule: rule}
+}
+
+type alertingResultsReader struct {
+	manager *Manager
+	rule    *ngModels.AlertRule
+}
+
+func (r alertingResultsReader) Read() []data.Labels {
+	var result []data.Labels
+	for _, s := range r.manager.GetStatesForRuleUID(r.rule.OrgID, r.rule.UID) {
+		if s.State == eval.Alerting {
+			result = append(result, resultLabelsFromStateLabels(r.rule, s.Labels))
+		}
+	}
+	return result
+}
+
+// resultLabelsFromStateLabels derives the labels of the evaluation result from the labels of a state,
+// by removing the custom labels of the rule and the labels added by Grafana. Labels of the result
+// that are overridden by the rule are lost.
+func resultLabelsFromStateLabels(rule *ngModels.AlertRule, labels data.Labels) data.Labels {
+	result := make(data.Labels, len(labels))
+	for k, v := range labels {
+		if _, ok := rule.Labels[k]; ok {
+			continue
+		}
+		switch k {
+		case alertingModels.NamespaceUIDLabel, alertingModels.RuleUIDLabel, prometheusModel.AlertNameLabel, ngModels.FolderTitleLabel:
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
 func (st *Manager) Put(states []*State) {
 	for _, s := range states {
 		st.cache.set(s)
@@ -212,6 +212,132 @@ func TestWarmStateCache(t *testing.T) {
 	})
 }
 
+func TestLoadStateByRuleUID(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	cfg := state.ManagerCfg{
+		Metrics:       testMetrics.GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+	}
+	st := state.NewManager(cfg)
+	st.Warm(ctx, dbstore)
+	require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+	// another instance of Grafana evaluates the rule and persists its state
+	labels := models.InstanceLabels{"test1": "testValue1"}
+	_, hash, _ := labels.StringAndHash()
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:      models.InstanceStateFiring,
+		LastEvalTime:      evaluationTime,
+		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		Labels:            labels,
+	}
+	require.NoError(t, dbstore.SaveAlertInstances(ctx, instance))
+
+	t.Run("should load the state of the rule from the database", func(t *testing.T) {
+		require.NoError(t, st.LoadStateByRuleUID(ctx, rule))
+
+		expected := &state.State{
+			AlertRuleUID:       rule.UID,
+			OrgID:              rule.OrgID,
+			CacheID:            `[["test1","testValue1"]]`,
+			Labels:             data.Labels{"test1": "testValue1"},
+			State:              eval.Alerting,
+			StartsAt:           evaluationTime.Add(-1 * time.Minute),
+			EndsAt:             evaluationTime.Add(1 * time.Minute),
+			LastEvaluationTime: evaluationTime,
+			Annotations:        rule.Annotations,
+		}
+		require.Equal(t, expected, st.Get(rule.OrgID, rule.UID, expected.CacheID))
+	})
+
+	t.Run("should forget the state but keep it in the database", func(t *testing.T) {
+		st.ForgetStateByRuleUID(rule.GetKey())
+		require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+		q := &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, dbstore.ListAlertInstances(ctx, q))
+		require.Len(t, q.Result, 1)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	cfg := state.ManagerCfg{
+		Metrics:       testMetrics.GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+	}
+	st := state.NewManager(cfg)
+	st.Warm(ctx, dbstore)
+
+	labels := models.InstanceLabels{"test1": "testValue1"}
+	_, hash, _ := labels.StringAndHash()
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:      models.InstanceStateFiring,
+		LastEvalTime:      evaluationTime,
+		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
+		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		Labels:            labels,
+	}
+
+	processed := make(chan struct{})
+	err = st.Replace(ctx, dbstore, func(ctx context.Context) error {
+		go func() {
+			defer close(processed)
+			st.ProcessEvalResults(ctx, evaluationTime, rule, eval.Results{{
+				Instance:    data.Labels{"test2": "testValue2"},
+				State:       eval.Normal,
+				EvaluatedAt: evaluationTime,
+			}}, nil)
+		}()
+		select {
+		case <-processed:
+			require.Fail(t, "evaluation results must not be processed while the state is replaced")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return dbstore.SaveAlertInstances(ctx, instance)
+	})
+	require.NoError(t, err)
+	<-processed
+
+	s := st.Get(rule.OrgID, rule.UID, `[["test1","testValue1"]]`)
+	require.NotNil(t, s)
+	require.Equal(t, eval.Alerting, s.State)
+	require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 2)
+}
+
 func TestDashboardAnnotations(t *testing.T) {
 	evaluationTime, err := time.Parse("2006-01-02", "2022-01-01")
 	require.NoError(t, err)
@@ -2277,6 +2403,102 @@ func TestProcessEvalResults(t *testing.T) {
 			require.Contains(t, savedStates, s.CacheID)
 		}
 	})
+
+	t.Run("should keep firing for KeepFiringFor after the condition stopped being met", func(t *testing.T) {
+		cfg := state.ManagerCfg{
+			Metrics:       testMetrics.GetStateMetrics(),
+			ExternalURL:   nil,
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clock.New(),
+			Historian:     &state.FakeHistorian{},
+		}
+		st := state.NewManager(cfg)
+		rule := models.AlertRuleGen(models.WithFor(0), models.WithKeepFiringFor(20*time.Second), func(rule *models.AlertRule) {
+			rule.IntervalSeconds = 10
+		})()
+		instance := data.Labels{"instance": "a"}
+
+		steps := []struct {
+			result         eval.State
+			expectedState  eval.State
+			expectedReason string
+		}{
+			{eval.Alerting, eval.Alerting, ""},
+			{eval.Normal, eval.Alerting, models.StateReasonKeepFiring},
+			{eval.Normal, eval.Alerting, models.StateReasonKeepFiring},
+			// the condition is met again, so the timer is reset
+			{eval.Alerting, eval.Alerting, ""},
+			{eval.Normal, eval.Alerting, models.StateReasonKeepFiring},
+			{eval.Normal, eval.Alerting, models.StateReasonKeepFiring},
+			{eval.Normal, eval.Normal, ""},
+			// instances that were not firing go to Normal right away
+			{eval.Normal, eval.Normal, ""},
+		}
+		for i, step := range steps {
+			now := evaluationTime.Add(time.Duration(i) * 10 * time.Second)
+			transitions := st.ProcessEvalResults(context.Background(), now, rule, eval.Results{
+				{Instance: instance, State: step.result, EvaluatedAt: now},
+			}, nil)
+			require.Len(t, transitions, 1)
+			s := transitions[0]
+			require.Equalf(t, step.expectedState, s.State.State, "unexpected state at step %d", i)
+			require.Equalf(t, step.expectedReason, s.StateReason, "unexpected reason at step %d", i)
+			require.Equalf(t, s.State.State == eval.Normal && s.PreviousState == eval.Alerting, s.Resolved, "unexpected resolved at step %d", i)
+		}
+	})
+
+	t.Run("should suppress the instances while a rule the rule depends on is firing", func(t *testing.T) {
+		historian := &state.FakeHistorian{}
+		cfg := state.ManagerCfg{
+			Metrics:       testMetrics.GetStateMetrics(),
+			ExternalURL:   nil,
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clock.New(),
+			Historian:     historian,
+		}
+		st := state.NewManager(cfg)
+		gen := models.AlertRuleGen(models.WithFor(0), models.WithOrgID(1))
+		database := gen()
+		downstream := gen()
+		downstream.DependsOn = []string{database.UID}
+		downstream.NoDataState = models.NoData
+		downstream.ExecErrState = models.AlertingErrState
+		instance := data.Labels{"instance": "a"}
+
+		steps := []struct {
+			database       eval.State
+			downstream     eval.State
+			expectedState  eval.State
+			expectedReason string
+			expectedSend   bool
+		}{
+			{eval.Normal, eval.Alerting, eval.Alerting, "", true},
+			{eval.Alerting, eval.Alerting, eval.Alerting, models.StateReasonSuppressed, false},
+			{eval.Alerting, eval.Error, eval.Alerting, models.ConcatReasons(models.StateReasonError, models.StateReasonSuppressed), false},
+			{eval.Alerting, eval.NoData, eval.NoData, models.StateReasonSuppressed, false},
+			{eval.Alerting, eval.Normal, eval.Normal, "", false},
+			{eval.Normal, eval.Alerting, eval.Alerting, "", true},
+		}
+		for i, step := range steps {
+			now := evaluationTime.Add(time.Duration(i) * 10 * time.Second)
+			st.ProcessEvalResults(context.Background(), now, database, eval.Results{
+				{Instance: instance, State: step.database, EvaluatedAt: now},
+			}, nil)
+			historian.StateTransitions = nil
+			transitions := st.ProcessEvalResults(context.Background(), now, downstream, eval.Results{
+				{Instance: instance, State: step.downstream, EvaluatedAt: now},
+			}, nil)
+			require.Len(t, transitions, 1)
+			s := transitions[0]
+			require.Equalf(t, step.expectedState, s.State.State, "unexpected state at step %d", i)
+			require.Equalf(t, step.expectedReason, s.StateReason, "unexpected reason at step %d", i)
+			require.Equalf(t, step.expectedSend, s.NeedsSending(0), "unexpected sending at step %d", i)
+			// the transitions from and to the suppressed state are recorded by the historian
+			require.Equalf(t, historian.StateTransitions, transitions, "unexpected recorded transitions at step %d", i)
+		}
+	})
 }
 
 func printAllAnnotations(annos map[int64]annotations.Item) string {
@@ -2820,3 +3042,31 @@ func TestResetStateByRuleUID(t *testing.T) {
 		})
 	}
 }
+
+func TestAlertingResultsReader(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	st := state.NewManager(state.ManagerCfg{
+		Metrics:       testMetrics.GetStateMetrics(),
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+	})
+
+	rule := models.AlertRuleGen(models.WithFor(0))()
+	rule.Labels = map[string]string{"team": "a"}
+	results := eval.Results{
+		eval.ResultGen(eval.WithState(eval.Alerting), eval.WithEvaluatedAt(clk.Now()), eval.WithLabels(data.Labels{"host": "a"}))(),
+		eval.ResultGen(eval.WithState(eval.Normal), eval.WithEvaluatedAt(clk.Now()), eval.WithLabels(data.Labels{"host": "b"}))(),
+	}
+	st.ProcessEvalResults(ctx, clk.Now(), rule, results, data.Labels{
+		"alertname":                    rule.Title,
+		"__alert_rule_namespace_uid__": rule.NamespaceUID,
+		"__alert_rule_uid__":           rule.UID,
+	})
+
+	loaded := st.AlertingResultsReader(rule).Read()
+
+	require.Equal(t, []data.Labels{{"host": "a"}}, loaded)
+}
@@ -42,6 +42,10 @@ type State struct {
 	// All subsequent states will be false until the next transition from Firing to Normal.
 	Resolved bool
 
+	// KeepFiringSince is the time of the first evaluation at which the condition stopped being met
+	// while the state is kept Alerting because of the KeepFiringFor of the rule. It is zero otherwise.
+	KeepFiringSince time.Time
+
 	// Image contains an optional image for the state. It tends to be included in notifications
 	// as a visualization to show why the alert fired.
 	Image *models.Image
@@ -184,7 +188,20 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
+	if state.State == eval.Alerting && rule.KeepFiringFor > 0 {
+		if state.KeepFiringSince.IsZero() {
+			state.KeepFiringSince = result.EvaluatedAt
+		}
+		// If the previous state is Alerting then check if the KeepFiringFor duration has been observed
+		if result.EvaluatedAt.Sub(state.KeepFiringSince) < rule.KeepFiringFor {
+			logger.Debug("Keeping state because of keep firing for", "state", state.State, "keep_firing_since", state.KeepFiringSince)
+			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+			return
+		}
+	}
+	state.KeepFiringSince = time.Time{}
+
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
 	} else {
@@ -198,6 +215,7 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 	switch state.State {
 	case eval.Alerting:
 		logger.Debug("Keeping state", "state", state.State)
+		state.KeepFiringSince = time.Time{}
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 	case eval.Pending:
 		// If the previous state is Pending then check if the For duration has been observed
@@ -261,6 +279,7 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 
 func resultNoData(state *State, rule *models.AlertRule, result eval.Result, _ log.Logger) {
 	state.Error = result.Error
+	state.KeepFiringSince = time.Time{}
 
 	if state.StartsAt.IsZero() {
 		state.StartsAt = result.EvaluatedAt
@@ -278,6 +297,10 @@ func resultNoData(state *State, rule *models.AlertRule, result eval.Result, _ lo
 }
 
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
+	if models.HasReason(a.StateReason, models.StateReasonSuppressed) {
+		// The notifications are suppressed while a rule the rule depends on is firing
+		return false
+	}
 	switch a.State {
 	case eval.Pending:
 		// We do not send notifications for pending states
@@ -381,6 +381,17 @@ func TestNeedsSending(t *testing.T) {
 				LastSentAt:         evaluationTime.Add(-1 * time.Minute),
 			},
 		},
+		{
+			name:        "state: alerting and suppressed by a dependency",
+			resendDelay: 1 * time.Minute,
+			expected:    false,
+			testState: &State{
+				State:              eval.Alerting,
+				StateReason:        ngmodels.StateReasonSuppressed,
+				LastEvaluationTime: evaluationTime,
+				LastSentAt:         evaluationTime.Add(-2 * time.Minute),
+			},
+		},
 		{
 			name:        "state: pending",
 			resendDelay: 1 * time.Minute,
@@ -0,0 +1,256 @@
+package statearchive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// Version is the version of the archive format created by the service.
+const Version = 1
+
+var ErrInvalidArchive = errors.New("invalid state archive")
+
+// InstanceStore is the database interface used to read and write alert instances.
+type InstanceStore interface {
+	ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) error
+	SaveAlertInstances(ctx context.Context, cmd ...models.AlertInstance) error
+	DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error
+}
+
+// ConfigStore is the database interface used to read and write configurations of the Grafana Alertmanager.
+type ConfigStore interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
+	SaveAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error
+}
+
+// Service exports the runtime state of Grafana Alerting to an archive and imports it back.
+// It makes it possible to move the state between Grafana instances or databases without re-sending notifications of firing alerts.
+//
+// The service works with the state persisted to the database. The Grafana Alertmanager persists silences and
+// the notification log periodically and when it stops, and the scheduler persists alert instances after every evaluation.
+// Therefore, the running Alertmanagers must persist their state before it is exported.
+// Therefore, the state must be imported while the Alertmanagers of the organizations are stopped and the scheduler
+// does not process evaluation results, and the state cache of the scheduler must be reloaded after the import.
+type Service struct {
+	instances InstanceStore
+	configs   ConfigStore
+	orgs      store.OrgStore
+	kv        kvstore.KVStore
+	log       log.Logger
+}
+
+func NewService(instances InstanceStore, configs ConfigStore, orgs store.OrgStore, kv kvstore.KVStore, log log.Logger) *Service {
+	return &Service{
+		instances: instances,
+		configs:   configs,
+		orgs:      orgs,
+		kv:        kv,
+		log:       log,
+	}
+}
+
+// Export creates an archive of the state of all organizations.
+func (s *Service) Export(ctx context.Context) (*apimodels.StateArchive, error) {
+	orgIDs, err := s.orgs.GetOrgs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organizations: %w", err)
+	}
+
+	archive := &apimodels.StateArchive{
+		Version: Version,
+		Created: time.Now().UTC(),
+		Orgs:    make([]apimodels.OrgStateArchive, 0, len(orgIDs)),
+	}
+	for _, orgID := range orgIDs {
+		orgState, err := s.exportOrg(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export state of organization %d: %w", orgID, err)
+		}
+		archive.Orgs = append(archive.Orgs, orgState)
+	}
+	return archive, nil
+}
+
+func (s *Service) exportOrg(ctx context.Context, orgID int64) (apimodels.OrgStateArchive, error) {
+	result := apimodels.OrgStateArchive{
+		OrgID:          orgID,
+		AlertInstances: []apimodels.ArchivedAlertInstance{},
+	}
+
+	q := models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := s.configs.GetLatestAlertmanagerConfiguration(ctx, &q); err != nil {
+		if !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return result, err
+		}
+	} else {
+		result.AlertmanagerConfiguration = q.Result.AlertmanagerConfiguration
+	}
+
+	files := notifier.NewFileStore(orgID, s.kv, "")
+	silences, _, err := files.Get(ctx, notifier.SilencesFilename)
+	if err != nil {
+		return result, err
+	}
+	result.Silences = silences
+	nflog, _, err := files.Get(ctx, notifier.NotificationLogFilename)
+	if err != nil {
+		return result, err
+	}
+	result.NotificationLog = nflog
+
+	instances := models.ListAlertInstancesQuery{RuleOrgID: orgID}
+	if err := s.instances.ListAlertInstances(ctx, &instances); err != nil {
+		return result, err
+	}
+	for _, instance := range instances.Result {
+		result.AlertInstances = append(result.AlertInstances, apimodels.ArchivedAlertInstance{
+			RuleUID:        instance.RuleUID,
+			Labels:         instance.Labels,
+			State:          string(instance.CurrentState),
+			Reason:         instance.CurrentReason,
+			StateSince:     instance.CurrentStateSince,
+			StateEnd:       instance.CurrentStateEnd,
+			LastEvaluation: instance.LastEvalTime,
+		})
+	}
+	return result, nil
+}
+
+// Validate checks that the archive can be imported: the format is supported, the organizations exist,
+// and the Alertmanager configurations and alert instances are valid.
+func (s *Service) Validate(ctx context.Context, archive *apimodels.StateArchive) error {
+	if archive.Version != Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, archive.Version)
+	}
+
+	orgIDs, err := s.orgs.GetOrgs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get organizations: %w", err)
+	}
+	existing := make(map[int64]struct{}, len(orgIDs))
+	for _, orgID := range orgIDs {
+		existing[orgID] = struct{}{}
+	}
+
+	seen := make(map[int64]struct{}, len(archive.Orgs))
+	for _, orgState := range archive.Orgs {
+		if _, ok := existing[orgState.OrgID]; !ok {
+			return fmt.Errorf("%w: organization %d does not exist", ErrInvalidArchive, orgState.OrgID)
+		}
+		if _, ok := seen[orgState.OrgID]; ok {
+			return fmt.Errorf("%w: organization %d is archived more than once", ErrInvalidArchive, orgState.OrgID)
+		}
+		seen[orgState.OrgID] = struct{}{}
+
+		if orgState.AlertmanagerConfiguration != "" {
+			if _, err := notifier.Load([]byte(orgState.AlertmanagerConfiguration)); err != nil {
+				return fmt.Errorf("%w: invalid Alertmanager configuration of organization %d: %s", ErrInvalidArchive, orgState.OrgID, err)
+			}
+		}
+		for _, instance := range orgState.AlertInstances {
+			if _, err := toAlertInstance(orgState.OrgID, instance); err != nil {
+				return fmt.Errorf("%w: invalid alert instance of organization %d: %s", ErrInvalidArchive, orgState.OrgID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Import replaces the state of the organizations in the archive. Organizations that are not in the archive are not changed.
+func (s *Service) Import(ctx context.Context, archive *apimodels.StateArchive) error {
+	if err := s.Validate(ctx, archive); err != nil {
+		return err
+	}
+	for _, orgState := range archive.Orgs {
+		if err := s.importOrg(ctx, orgState); err != nil {
+			return fmt.Errorf("failed to import state of organization %d: %w", orgState.OrgID, err)
+		}
+		s.log.Info("Imported alerting state", "org", orgState.OrgID, "instances", len(orgState.AlertInstances))
+	}
+	return nil
+}
+
+func (s *Service) importOrg(ctx context.Context, orgState apimodels.OrgStateArchive) error {
+	if orgState.AlertmanagerConfiguration != "" {
+		err := s.configs.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: orgState.AlertmanagerConfiguration,
+			ConfigurationVersion:      fmt.Sprintf("v%d", models.AlertConfigurationVersion),
+			OrgID:                     orgState.OrgID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save Alertmanager configuration: %w", err)
+		}
+	}
+
+	files := notifier.NewFileStore(orgState.OrgID, s.kv, "")
+	for filename, content := range map[string][]byte{
+		notifier.SilencesFilename:        orgState.Silences,
+		notifier.NotificationLogFilename: orgState.NotificationLog,
+	} {
+		var err error
+		if len(content) == 0 {
+			err = files.Delete(ctx, filename)
+		} else {
+			err = files.Set(ctx, filename, content)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save %s: %w", filename, err)
+		}
+	}
+
+	existing := models.ListAlertInstancesQuery{RuleOrgID: orgState.OrgID}
+	if err := s.instances.ListAlertInstances(ctx, &existing); err != nil {
+		return err
+	}
+	keys := make([]models.AlertInstanceKey, 0, len(existing.Result))
+	for _, instance := range existing.Result {
+		keys = append(keys, instance.AlertInstanceKey)
+	}
+	if err := s.instances.DeleteAlertInstances(ctx, keys...); err != nil {
+		return fmt.Errorf("failed to delete alert instances: %w", err)
+	}
+
+	instances := make([]models.AlertInstance, 0, len(orgState.AlertInstances))
+	for _, archived := range orgState.AlertInstances {
+		instance, err := toAlertInstance(orgState.OrgID, archived)
+		if err != nil {
+			return err
+		}
+		instances = append(instances, instance)
+	}
+	if err := s.instances.SaveAlertInstances(ctx, instances...); err != nil {
+		return fmt.Errorf("failed to save alert instances: %w", err)
+	}
+	return nil
+}
+
+func toAlertInstance(orgID int64, archived apimodels.ArchivedAlertInstance) (models.AlertInstance, error) {
+	labels := models.InstanceLabels(archived.Labels)
+	_, hash, err := labels.StringAndHash()
+	if err != nil {
+		return models.AlertInstance{}, err
+	}
+	instance := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  orgID,
+			RuleUID:    archived.RuleUID,
+			LabelsHash: hash,
+		},
+		Labels:            labels,
+		CurrentState:      models.InstanceStateType(archived.State),
+		CurrentReason:     archived.Reason,
+		CurrentStateSince: archived.StateSince,
+		CurrentStateEnd:   archived.StateEnd,
+		LastEvalTime:      archived.LastEvaluation,
+	}
+	return instance, models.ValidateAlertInstance(instance)
+}
@@ -0,0 +1,160 @@
+package statearchive
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationStateArchive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*Service, *store.DBstore, kvstore.KVStore) {
+		sqlStore := db.InitTestDB(t)
+		dbstore := &store.DBstore{
+			Cfg:            setting.NewCfg().UnifiedAlerting,
+			FeatureToggles: featuremgmt.WithFeatures(),
+			SQLStore:       sqlStore,
+			Logger:         log.New("test-dbstore"),
+		}
+		kv := kvstore.ProvideService(sqlStore)
+		orgs := notifier.NewFakeOrgStore(t, []int64{1, 2})
+		return NewService(dbstore, dbstore, &orgs, kv, log.New("test")), dbstore, kv
+	}
+
+	source, sourceStore, sourceKV := setup(t)
+	config := setting.GetAlertmanagerDefaultConfiguration()
+	require.NoError(t, sourceStore.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: config,
+		ConfigurationVersion:      "v1",
+		OrgID:                     1,
+	}))
+	files := notifier.NewFileStore(1, sourceKV, "")
+	require.NoError(t, files.Set(ctx, notifier.SilencesFilename, []byte("silences")))
+	require.NoError(t, files.Set(ctx, notifier.NotificationLogFilename, []byte("nflog")))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	labels := models.InstanceLabels{"__alert_rule_uid__": "rule", "team": "a"}
+	_, hash, err := labels.StringAndHash()
+	require.NoError(t, err)
+	require.NoError(t, sourceStore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: hash},
+		Labels:            labels,
+		CurrentState:      models.InstanceStateFiring,
+		CurrentStateSince: now.Add(-time.Hour),
+		CurrentStateEnd:   now.Add(time.Minute),
+		LastEvalTime:      now,
+	}))
+
+	archive, err := source.Export(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Version, archive.Version)
+	require.Len(t, archive.Orgs, 2)
+
+	org1 := archive.Orgs[0]
+	require.Equal(t, int64(1), org1.OrgID)
+	require.Equal(t, config, org1.AlertmanagerConfiguration)
+	require.Equal(t, []byte("silences"), org1.Silences)
+	require.Equal(t, []byte("nflog"), org1.NotificationLog)
+	require.Equal(t, []apimodels.ArchivedAlertInstance{{
+		RuleUID:        "rule",
+		Labels:         labels,
+		State:          string(models.InstanceStateFiring),
+		StateSince:     now.Add(-time.Hour),
+		StateEnd:       now.Add(time.Minute),
+		LastEvaluation: now,
+	}}, normalizeInstances(org1.AlertInstances))
+
+	org2 := archive.Orgs[1]
+	require.Equal(t, int64(2), org2.OrgID)
+	require.Empty(t, org2.AlertmanagerConfiguration)
+	require.Empty(t, org2.Silences)
+	require.Empty(t, org2.AlertInstances)
+
+	// The archive is transferred as JSON.
+	raw, err := json.Marshal(archive)
+	require.NoError(t, err)
+	var imported apimodels.StateArchive
+	require.NoError(t, json.Unmarshal(raw, &imported))
+
+	t.Run("should replace the state of the organizations", func(t *testing.T) {
+		target, targetStore, targetKV := setup(t)
+		targetFiles := notifier.NewFileStore(2, targetKV, "")
+		require.NoError(t, targetFiles.Set(ctx, notifier.SilencesFilename, []byte("other silences")))
+		stale := models.InstanceLabels{"__alert_rule_uid__": "stale"}
+		_, staleHash, err := stale.StringAndHash()
+		require.NoError(t, err)
+		require.NoError(t, targetStore.SaveAlertInstance(ctx, models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "stale", LabelsHash: staleHash},
+			Labels:           stale,
+			CurrentState:     models.InstanceStateNormal,
+		}))
+
+		require.NoError(t, target.Import(ctx, &imported))
+
+		exported, err := target.Export(ctx)
+		require.NoError(t, err)
+		require.Equal(t, archive.Orgs[0].AlertmanagerConfiguration, exported.Orgs[0].AlertmanagerConfiguration)
+		require.Equal(t, archive.Orgs[0].Silences, exported.Orgs[0].Silences)
+		require.Equal(t, archive.Orgs[0].NotificationLog, exported.Orgs[0].NotificationLog)
+		require.Equal(t, normalizeInstances(archive.Orgs[0].AlertInstances), normalizeInstances(exported.Orgs[0].AlertInstances))
+		// silences of the second organization are removed because the archive has none
+		require.Empty(t, exported.Orgs[1].Silences)
+	})
+
+	t.Run("should reject invalid archives", func(t *testing.T) {
+		target, _, _ := setup(t)
+		testCases := map[string]func(a *apimodels.StateArchive){
+			"unsupported version": func(a *apimodels.StateArchive) {
+				a.Version = Version + 1
+			},
+			"unknown organization": func(a *apimodels.StateArchive) {
+				a.Orgs = append(a.Orgs, apimodels.OrgStateArchive{OrgID: 3})
+			},
+			"duplicated organization": func(a *apimodels.StateArchive) {
+				a.Orgs = append(a.Orgs, apimodels.OrgStateArchive{OrgID: 1})
+			},
+			"invalid Alertmanager configuration": func(a *apimodels.StateArchive) {
+				a.Orgs[0].AlertmanagerConfiguration = "{"
+			},
+			"invalid alert instance": func(a *apimodels.StateArchive) {
+				a.Orgs[0].AlertInstances = []apimodels.ArchivedAlertInstance{{RuleUID: "rule", State: "Unknown"}}
+			},
+		}
+		for name, mutate := range testCases {
+			t.Run(name, func(t *testing.T) {
+				invalid := &apimodels.StateArchive{Version: Version, Orgs: []apimodels.OrgStateArchive{{OrgID: 1}}}
+				mutate(invalid)
+				require.ErrorIs(t, target.Import(ctx, invalid), ErrInvalidArchive)
+			})
+		}
+	})
+}
+
+// normalizeInstances converts the times to UTC because the database returns them in the local time zone.
+func normalizeInstances(instances []apimodels.ArchivedAlertInstance) []apimodels.ArchivedAlertInstance {
+	result := make([]apimodels.ArchivedAlertInstance, 0, len(instances))
+	for _, instance := range instances {
+		instance.StateSince = instance.StateSince.UTC()
+		instance.StateEnd = instance.StateEnd.UTC()
+		instance.LastEvaluation = instance.LastEvaluation.UTC()
+		result = append(result, instance)
+	}
+	return result
+}
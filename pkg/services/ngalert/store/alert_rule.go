@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -113,6 +114,7 @@ func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmode
 // InsertAlertRules is a handler for creating/updating alert rules.
 func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) (map[string]int64, error) {
 	ids := make(map[string]int64, len(rules))
+	createdBy := versionAuthor(ctx)
 	return ids, st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		newRules := make([]ngmodels.AlertRule, 0, len(rules))
 		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
@@ -134,22 +136,27 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 			}
 			newRules = append(newRules, r)
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleUID:          r.UID,
-				RuleOrgID:        r.OrgID,
-				RuleNamespaceUID: r.NamespaceUID,
-				RuleGroup:        r.RuleGroup,
-				ParentVersion:    0,
-				Version:          r.Version,
-				Created:          r.Updated,
-				Condition:        r.Condition,
-				Title:            r.Title,
-				Data:             r.Data,
-				IntervalSeconds:  r.IntervalSeconds,
-				NoDataState:      r.NoDataState,
-				ExecErrState:     r.ExecErrState,
-				For:              r.For,
-				Annotations:      r.Annotations,
-				Labels:           r.Labels,
+				RuleUID:              r.UID,
+				RuleOrgID:            r.OrgID,
+				RuleNamespaceUID:     r.NamespaceUID,
+				RuleGroup:            r.RuleGroup,
+				ParentVersion:        0,
+				Version:              r.Version,
+				Created:              r.Updated,
+				Condition:            r.Condition,
+				Title:                r.Title,
+				Data:                 r.Data,
+				IntervalSeconds:      r.IntervalSeconds,
+				NoDataState:          r.NoDataState,
+				ExecErrState:         r.ExecErrState,
+				For:                  r.For,
+				KeepFiringFor:        r.KeepFiringFor,
+				Annotations:          r.Annotations,
+				Labels:               r.Labels,
+				IsPaused:             r.IsPaused,
+				NotificationSettings: r.NotificationSettings,
+				DependsOn:            r.DependsOn,
+				CreatedBy:            createdBy,
 			})
 		}
 		if len(newRules) > 0 {
@@ -177,6 +184,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 
 // UpdateAlertRules is a handler for updating alert rules.
 func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateRule) error {
+	createdBy := versionAuthor(ctx)
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
 		for _, r := range rules {
@@ -201,23 +209,29 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				RuleGroupIndex:   r.New.RuleGroupIndex,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version + 1,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
+				RuleOrgID:            r.New.OrgID,
+				RuleUID:              r.New.UID,
+				RuleNamespaceUID:     r.New.NamespaceUID,
+				RuleGroup:            r.New.RuleGroup,
+				RuleGroupIndex:       r.New.RuleGroupIndex,
+				ParentVersion:        parentVersion,
+				RestoredFrom:         r.RestoredFrom,
+				Version:              r.New.Version + 1,
+				Created:              r.New.Updated,
+				Condition:            r.New.Condition,
+				Title:                r.New.Title,
+				Data:                 r.New.Data,
+				IntervalSeconds:      r.New.IntervalSeconds,
+				NoDataState:          r.New.NoDataState,
+				ExecErrState:         r.New.ExecErrState,
+				For:                  r.New.For,
+				KeepFiringFor:        r.New.KeepFiringFor,
+				Annotations:          r.New.Annotations,
+				Labels:               r.New.Labels,
+				IsPaused:             r.New.IsPaused,
+				NotificationSettings: r.New.NotificationSettings,
+				DependsOn:            r.New.DependsOn,
+				CreatedBy:            createdBy,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -229,6 +243,51 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 	})
 }
 
+// versionAuthor returns the login of the user that makes the change, or an empty string if the change
+// is not made on behalf of a user.
+func versionAuthor(ctx context.Context) string {
+	u, err := appcontext.User(ctx)
+	if err != nil || u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+// ListAlertRuleVersions is a handler for retrieving all versions of an alert rule, latest first.
+func (st DBstore) ListAlertRuleVersions(ctx context.Context, query *ngmodels.ListAlertRuleVersionsQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		err := sess.Table("alert_rule_version").
+			Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID).
+			Desc("version").
+			Find(&versions)
+		if err != nil {
+			return err
+		}
+		query.Result = versions
+		return nil
+	})
+}
+
+// GetAlertRuleVersion is a handler for retrieving a single version of an alert rule.
+// It returns ngmodels.ErrAlertRuleVersionNotFound if the version does not exist.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		version := ngmodels.AlertRuleVersion{}
+		has, err := sess.Table("alert_rule_version").
+			Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.RuleUID, query.Version).
+			Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		query.Result = &version
+		return nil
+	})
+}
+
 // CountAlertRulesInFolder is a handler for retrieving the number of alert rules of
 // specific organisation associated with a given namespace (parent folder).
 func (st DBstore) CountAlertRulesInFolder(ctx context.Context, query *ngmodels.CountAlertRulesQuery) (int64, error) {
@@ -300,6 +359,26 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 	})
 }
 
+// ListNotificationSettings returns the notification settings of the alert rules of the organization that have any.
+func (st DBstore) ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error) {
+	var result []ngmodels.NotificationSettings
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rules []ngmodels.AlertRule
+		// Rules without notification settings have NULL, or the JSON of an empty list, which is 'null' for a nil slice.
+		err := sess.Table("alert_rule").Cols("uid", "notification_settings").
+			Where("org_id = ? AND notification_settings IS NOT NULL AND notification_settings NOT IN ('null', '[]')", orgID).
+			Find(&rules)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			result = append(result, rule.NotificationSettings...)
+		}
+		return nil
+	})
+	return result, err
+}
+
 // Count returns either the number of the alert rules under a specific org (if orgID is not zero)
 // or the number of all the alert rules
 func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
@@ -568,5 +647,24 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if len(alertRule.NotificationSettings) > 1 {
+		return fmt.Errorf("%w: an alert rule can have only one notification settings", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	for _, settings := range alertRule.NotificationSettings {
+		if err := settings.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+		}
+	}
+
+	for _, uid := range alertRule.DependsOn {
+		if uid == "" || uid == alertRule.UID {
+			return fmt.Errorf("%w: field `depends_on` must contain UIDs of other rules of the group", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
 	return nil
 }
@@ -11,8 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/rand"
 
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -68,6 +70,51 @@ func TestIntegrationUpdateAlertRules(t *testing.T) {
 	})
 }
 
+func TestIntegrationAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval: time.Duration(rand.Int63n(100)+1) * time.Second,
+		},
+	}
+
+	rule := createRule(t, store)
+	newRule := models.CopyRule(rule)
+	newRule.Title = util.GenerateShortUID()
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{Login: "editor"})
+	require.NoError(t, store.UpdateAlertRules(ctx, []models.UpdateRule{{
+		Existing:     rule,
+		New:          *newRule,
+		RestoredFrom: 1,
+	}}))
+
+	t.Run("should list versions latest first", func(t *testing.T) {
+		q := models.ListAlertRuleVersionsQuery{OrgID: rule.OrgID, RuleUID: rule.UID}
+		require.NoError(t, store.ListAlertRuleVersions(context.Background(), &q))
+		require.Len(t, q.Result, 1)
+		require.Equal(t, rule.Version+1, q.Result[0].Version)
+		require.Equal(t, rule.Version, q.Result[0].ParentVersion)
+		require.Equal(t, int64(1), q.Result[0].RestoredFrom)
+		require.Equal(t, newRule.Title, q.Result[0].Title)
+		require.Equal(t, "editor", q.Result[0].CreatedBy)
+	})
+
+	t.Run("should get version", func(t *testing.T) {
+		q := models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, RuleUID: rule.UID, Version: rule.Version + 1}
+		require.NoError(t, store.GetAlertRuleVersion(context.Background(), &q))
+		require.Equal(t, newRule.Title, q.Result.Title)
+	})
+
+	t.Run("should return ErrAlertRuleVersionNotFound if version does not exist", func(t *testing.T) {
+		q := models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, RuleUID: rule.UID, Version: rule.Version + 2}
+		require.ErrorIs(t, store.GetAlertRuleVersion(context.Background(), &q), models.ErrAlertRuleVersionNotFound)
+	})
+}
+
 func withIntervalMatching(baseInterval time.Duration) func(*models.AlertRule) {
 	return func(rule *models.AlertRule) {
 		rule.IntervalSeconds = int64(baseInterval.Seconds()) * rand.Int63n(10)
@@ -196,3 +243,31 @@ func createRule(t *testing.T, store *DBstore) *models.AlertRule {
 	require.NoError(t, err)
 	return rule
 }
+
+func TestIntegrationListNotificationSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{SQLStore: sqlStore, Cfg: setting.UnifiedAlertingSettings{BaseInterval: time.Duration(rand.Int63n(100)+1) * time.Second}}
+
+	settings := models.NotificationSettings{Receiver: "receiver", GroupBy: []string{"alertname"}}
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithUniqueID(), models.WithOrgID(1))
+	withSettings := gen()
+	withSettings.NotificationSettings = []models.NotificationSettings{settings}
+	withoutSettings := gen()
+	withoutSettings.NotificationSettings = nil
+	withEmptySettings := gen()
+	withEmptySettings.NotificationSettings = []models.NotificationSettings{}
+
+	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Table(models.AlertRule{}).Insert(withSettings, withoutSettings, withEmptySettings)
+		return err
+	})
+	require.NoError(t, err)
+
+	result, err := store.ListNotificationSettings(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, []models.NotificationSettings{settings}, result)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -24,6 +25,8 @@ type RuleStore struct {
 	Hook        func(cmd interface{}) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []interface{}
 	Folders     map[int64][]*folder.Folder
+	// OrgID -> Versions of the rules
+	Versions map[int64][]*models.AlertRuleVersion
 }
 
 type GenericRecordedQuery struct {
@@ -38,7 +41,8 @@ func NewRuleStore(t *testing.T) *RuleStore {
 		Hook: func(interface{}) error {
 			return nil
 		},
-		Folders: map[int64][]*folder.Folder{},
+		Folders:  map[int64][]*folder.Folder{},
+		Versions: map[int64][]*models.AlertRuleVersion{},
 	}
 }
 
@@ -127,20 +131,60 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	if err := f.Hook(*q); err != nil {
 		return err
 	}
-	rules, ok := f.Rules[q.OrgID]
-	if !ok {
-		return nil
-	}
-
+	rules := f.Rules[q.OrgID]
 	for _, rule := range rules {
 		if rule.UID == q.UID {
 			q.Result = rule
-			break
+			return nil
+		}
+	}
+	return models.ErrAlertRuleNotFound
+}
+
+// PutRuleVersion adds the versions to the Versions map.
+func (f *RuleStore) PutRuleVersion(versions ...*models.AlertRuleVersion) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, v := range versions {
+		f.Versions[v.RuleOrgID] = append(f.Versions[v.RuleOrgID], v)
+	}
+}
+
+func (f *RuleStore) ListAlertRuleVersions(_ context.Context, q *models.ListAlertRuleVersionsQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	q.Result = nil
+	for _, v := range f.Versions[q.OrgID] {
+		if v.RuleUID == q.RuleUID {
+			q.Result = append(q.Result, v)
 		}
 	}
+	sort.Slice(q.Result, func(i, j int) bool {
+		return q.Result[i].Version > q.Result[j].Version
+	})
 	return nil
 }
 
+func (f *RuleStore) GetAlertRuleVersion(_ context.Context, q *models.GetAlertRuleVersionQuery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return err
+	}
+	for _, v := range f.Versions[q.OrgID] {
+		if v.RuleUID == q.RuleUID && v.Version == q.Version {
+			q.Result = v
+			return nil
+		}
+	}
+	return models.ErrAlertRuleVersionNotFound
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -67,18 +67,68 @@ type AlertRuleGroupWithFolderTitle struct {
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	DependsOn     []values.StringValue  `json:"dependsOn" yaml:"dependsOn"`
+
+	NotificationSettings *NotificationSettingsV1 `json:"notificationSettings" yaml:"notificationSettings"`
+}
+
+type NotificationSettingsV1 struct {
+	Receiver          values.StringValue   `json:"receiver" yaml:"receiver"`
+	GroupBy           []values.StringValue `json:"groupBy" yaml:"groupBy"`
+	GroupWait         values.StringValue   `json:"groupWait" yaml:"groupWait"`
+	GroupInterval     values.StringValue   `json:"groupInterval" yaml:"groupInterval"`
+	RepeatInterval    values.StringValue   `json:"repeatInterval" yaml:"repeatInterval"`
+	MuteTimeIntervals []values.StringValue `json:"muteTimeIntervals" yaml:"muteTimeIntervals"`
+}
+
+func (settings *NotificationSettingsV1) mapToModel() (models.NotificationSettings, error) {
+	result := models.NotificationSettings{
+		Receiver: strings.TrimSpace(settings.Receiver.Value()),
+	}
+	for _, l := range settings.GroupBy {
+		result.GroupBy = append(result.GroupBy, l.Value())
+	}
+	for _, name := range settings.MuteTimeIntervals {
+		result.MuteTimeIntervals = append(result.MuteTimeIntervals, name.Value())
+	}
+	parseDuration := func(name string, value values.StringValue) (*model.Duration, error) {
+		v := strings.TrimSpace(value.Value())
+		if v == "" {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return &d, nil
+	}
+	var err error
+	if result.GroupWait, err = parseDuration("groupWait", settings.GroupWait); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.GroupInterval, err = parseDuration("groupInterval", settings.GroupInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.RepeatInterval, err = parseDuration("repeatInterval", settings.RepeatInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if err := result.Validate(); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	return result, nil
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -97,6 +147,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
 	alertRule.For = time.Duration(duration)
+	if keepFiringFor := strings.TrimSpace(rule.KeepFiringFor.Value()); keepFiringFor != "" {
+		duration, err := model.ParseDuration(keepFiringFor)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse keepFiringFor: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
+	}
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -136,6 +193,16 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	for _, uid := range rule.DependsOn {
+		alertRule.DependsOn = append(alertRule.DependsOn, strings.TrimSpace(uid.Value()))
+	}
+	if rule.NotificationSettings != nil {
+		settings, err := rule.NotificationSettings.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse notification settings: %w", alertRule.Title, err)
+		}
+		alertRule.NotificationSettings = []models.NotificationSettings{settings}
+	}
 	return alertRule, nil
 }
 
@@ -181,8 +248,12 @@ func (queryV1 *QueryV1) mapToModel() (models.AlertQuery, error) {
 // AlertingFileExport is the full provisioned file export.
 // swagger:model
 type AlertingFileExport struct {
-	APIVersion int64                  `json:"apiVersion" yaml:"apiVersion"`
-	Groups     []AlertRuleGroupExport `json:"groups" yaml:"groups"`
+	APIVersion    int64                        `json:"apiVersion" yaml:"apiVersion"`
+	Groups        []AlertRuleGroupExport       `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints []ContactPointExport         `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies      []NotificationPolicyExport   `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimes     []MuteTimeIntervalExport     `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	Templates     []NotificationTemplateExport `json:"templates,omitempty" yaml:"templates,omitempty"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
@@ -192,22 +263,40 @@ type AlertRuleGroupExport struct {
 	Folder   string            `json:"folder" yaml:"folder"`
 	Interval model.Duration    `json:"interval" yaml:"interval"`
 	Rules    []AlertRuleExport `json:"rules" yaml:"rules"`
+
+	// FolderUID is not part of the provisioning file, where the folder is referenced by its title.
+	// It is used by exports to formats that reference the folder by its UID, such as Terraform.
+	FolderUID string `json:"-" yaml:"-"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
 type AlertRuleExport struct {
-	UID          string                     `json:"uid" yaml:"uid"`
-	Title        string                     `json:"title" yaml:"title"`
-	Condition    string                     `json:"condition" yaml:"condition"`
-	Data         []AlertQueryExport         `json:"data" yaml:"data"`
-	DashboardUID string                     `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
-	PanelID      int64                      `json:"panelId,omitempty" yaml:"panelId,omitempty"`
-	NoDataState  models.NoDataState         `json:"noDataState" yaml:"noDataState"`
-	ExecErrState models.ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
-	For          model.Duration             `json:"for" yaml:"for"`
-	Annotations  map[string]string          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Labels       map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
-	IsPaused     bool                       `json:"isPaused" yaml:"isPaused"`
+	UID           string                     `json:"uid" yaml:"uid"`
+	Title         string                     `json:"title" yaml:"title"`
+	Condition     string                     `json:"condition" yaml:"condition"`
+	Data          []AlertQueryExport         `json:"data" yaml:"data"`
+	DashboardUID  string                     `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	PanelID       int64                      `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	NoDataState   models.NoDataState         `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  models.ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
+	For           model.Duration             `json:"for" yaml:"for"`
+	KeepFiringFor model.Duration             `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	Annotations   map[string]string          `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels        map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
+	IsPaused      bool                       `json:"isPaused" yaml:"isPaused"`
+	DependsOn     []string                   `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+
+	NotificationSettings *NotificationSettingsExport `json:"notificationSettings,omitempty" yaml:"notificationSettings,omitempty"`
+}
+
+// NotificationSettingsExport is the provisioned file export of models.NotificationSettings.
+type NotificationSettingsExport struct {
+	Receiver          string          `json:"receiver" yaml:"receiver"`
+	GroupBy           []string        `json:"groupBy,omitempty" yaml:"groupBy,omitempty"`
+	GroupWait         *model.Duration `json:"groupWait,omitempty" yaml:"groupWait,omitempty"`
+	GroupInterval     *model.Duration `json:"groupInterval,omitempty" yaml:"groupInterval,omitempty"`
+	RepeatInterval    *model.Duration `json:"repeatInterval,omitempty" yaml:"repeatInterval,omitempty"`
+	MuteTimeIntervals []string        `json:"muteTimeIntervals,omitempty" yaml:"muteTimeIntervals,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -243,11 +332,12 @@ func newAlertRuleGroupExport(d AlertRuleGroupWithFolderTitle) (AlertRuleGroupExp
 		rules = append(rules, alert)
 	}
 	return AlertRuleGroupExport{
-		OrgID:    d.OrgID,
-		Name:     d.Title,
-		Folder:   d.FolderTitle,
-		Interval: model.Duration(time.Duration(d.Interval) * time.Second),
-		Rules:    rules,
+		OrgID:     d.OrgID,
+		Name:      d.Title,
+		Folder:    d.FolderTitle,
+		FolderUID: d.FolderUID,
+		Interval:  model.Duration(time.Duration(d.Interval) * time.Second),
+		Rules:     rules,
 	}, nil
 }
 
@@ -272,19 +362,35 @@ func newAlertRuleExport(rule models.AlertRule) (AlertRuleExport, error) {
 		panelID = *rule.PanelID
 	}
 
+	var notificationSettings *NotificationSettingsExport
+	if len(rule.NotificationSettings) > 0 {
+		s := rule.NotificationSettings[0]
+		notificationSettings = &NotificationSettingsExport{
+			Receiver:          s.Receiver,
+			GroupBy:           s.GroupBy,
+			GroupWait:         s.GroupWait,
+			GroupInterval:     s.GroupInterval,
+			RepeatInterval:    s.RepeatInterval,
+			MuteTimeIntervals: s.MuteTimeIntervals,
+		}
+	}
+
 	return AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: dashboardUID,
-		PanelID:      panelID,
-		NoDataState:  rule.NoDataState,
-		ExecErrState: rule.ExecErrState,
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		IsPaused:     rule.IsPaused,
+		UID:                  rule.UID,
+		Title:                rule.Title,
+		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
+		Condition:            rule.Condition,
+		Data:                 data,
+		DashboardUID:         dashboardUID,
+		PanelID:              panelID,
+		NoDataState:          rule.NoDataState,
+		ExecErrState:         rule.ExecErrState,
+		Annotations:          rule.Annotations,
+		Labels:               rule.Labels,
+		IsPaused:             rule.IsPaused,
+		DependsOn:            rule.DependsOn,
+		NotificationSettings: notificationSettings,
 	}, nil
 }
 
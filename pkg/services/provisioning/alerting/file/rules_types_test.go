@@ -125,6 +125,31 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with out a keep firing for duration should not keep firing", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with a keep firing for duration should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keep firing for duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -187,6 +212,62 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.NoDataState, models.NoData)
 	})
+	t.Run("a rule with dependencies should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		err := yaml.Unmarshal([]byte(`[database-down, network-down]`), &rule.DependsOn)
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"database-down", "network-down"}, ruleMapped.DependsOn)
+	})
+	t.Run("a rule without notification settings should have none", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Nil(t, ruleMapped.NotificationSettings)
+	})
+	t.Run("a rule with notification settings should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		settings := NotificationSettingsV1{}
+		err := yaml.Unmarshal([]byte(`
+receiver: team-a
+groupBy: [alertname, instance]
+repeatInterval: 4h
+muteTimeIntervals: [weekends]`), &settings)
+		require.NoError(t, err)
+		rule.NotificationSettings = &settings
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Len(t, ruleMapped.NotificationSettings, 1)
+		mapped := ruleMapped.NotificationSettings[0]
+		require.Equal(t, "team-a", mapped.Receiver)
+		require.Equal(t, []string{"alertname", "instance"}, mapped.GroupBy)
+		require.Nil(t, mapped.GroupWait)
+		require.Nil(t, mapped.GroupInterval)
+		require.NotNil(t, mapped.RepeatInterval)
+		require.Equal(t, 4*time.Hour, time.Duration(*mapped.RepeatInterval))
+		require.Equal(t, []string{"weekends"}, mapped.MuteTimeIntervals)
+	})
+	t.Run("a rule with notification settings without a receiver should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		settings := NotificationSettingsV1{}
+		err := yaml.Unmarshal([]byte(`groupWait: 30s`), &settings)
+		require.NoError(t, err)
+		rule.NotificationSettings = &settings
+		_, err = rule.mapToModel(1)
+		require.ErrorIs(t, err, models.ErrNotificationSettingsInvalid)
+	})
+	t.Run("a rule with notification settings with an invalid duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		settings := NotificationSettingsV1{}
+		err := yaml.Unmarshal([]byte(`
+receiver: team-a
+groupInterval: 10x`), &settings)
+		require.NoError(t, err)
+		rule.NotificationSettings = &settings
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
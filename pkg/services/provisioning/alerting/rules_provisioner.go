@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/slugify"
@@ -50,7 +51,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 				"folder", group.FolderTitle,
 				"folderUID", folderUID,
 				"name", group.Title)
-			for _, rule := range group.Rules {
+			for _, rule := range orderByDependencies(group.Rules) {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
 				err = prov.provisionRule(ctx, group.OrgID, rule)
@@ -74,6 +75,22 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 	return nil
 }
 
+// orderByDependencies returns the rules of a group in the order they must be provisioned in,
+// so that every rule is provisioned after the rules it depends on.
+func orderByDependencies(rules []alert_models.AlertRule) []alert_models.AlertRule {
+	result := make([]alert_models.AlertRule, len(rules))
+	copy(result, rules)
+	ptrs := make([]*alert_models.AlertRule, 0, len(rules))
+	for i := range rules {
+		ptrs = append(ptrs, &rules[i])
+	}
+	depths := alert_models.DependencyDepths(ptrs)
+	sort.SliceStable(result, func(i, j int) bool {
+		return depths[result[i].UID] < depths[result[j].UID]
+	})
+	return result
+}
+
 func (prov *defaultAlertRuleProvisioner) provisionRule(
 	ctx context.Context,
 	orgID int64,
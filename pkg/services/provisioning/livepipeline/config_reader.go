@@ -0,0 +1,166 @@
+package livepipeline
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader interface {
+	readConfig(ctx context.Context, path string) ([]*livePipelineAsConfig, error)
+}
+
+type configReaderImpl struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return &configReaderImpl{log: logger}
+}
+
+func (cr *configReaderImpl) readConfig(ctx context.Context, path string) ([]*livePipelineAsConfig, error) {
+	var configs []*livePipelineAsConfig
+	cr.log.Debug("Looking for Live pipeline provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read Live pipeline provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing Live pipeline provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseLivePipelineConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating Live pipeline configs")
+	if err := validateRequiredField(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	return configs, nil
+}
+
+func (cr *configReaderImpl) parseLivePipelineConfig(path string, file fs.DirEntry) (*livePipelineAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion != nil && apiVersion.APIVersion > 1 {
+		return nil, fmt.Errorf("unsupported apiVersion %d in %s", apiVersion.APIVersion, filename)
+	}
+
+	var cfg *livePipelineAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToLivePipelineFromConfig()
+}
+
+func validateRequiredField(configs []*livePipelineAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, rule := range configs[i].ChannelRules {
+			if rule.Pattern == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("channel rule item %d in configuration doesn't contain required field pattern", index+1),
+				)
+			}
+		}
+
+		for index, rule := range configs[i].DeleteChannelRules {
+			if rule.Pattern == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("delete channel rule item %d in configuration doesn't contain required field pattern", index+1),
+				)
+			}
+		}
+
+		for index, writeConfig := range configs[i].WriteConfigs {
+			if writeConfig.UID == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("write config item %d in configuration doesn't contain required field uid", index+1),
+				)
+			}
+		}
+
+		for index, writeConfig := range configs[i].DeleteWriteConfigs {
+			if writeConfig.UID == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("delete write config item %d in configuration doesn't contain required field uid", index+1),
+				)
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf(strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func checkOrgIDAndOrgName(configs []*livePipelineAsConfig) {
+	normalize := func(orgID *int64, orgName string) {
+		if *orgID < 1 {
+			if orgName == "" {
+				*orgID = 1
+			} else {
+				*orgID = 0
+			}
+		}
+	}
+
+	for i := range configs {
+		for _, rule := range configs[i].ChannelRules {
+			normalize(&rule.OrgID, rule.OrgName)
+		}
+		for _, rule := range configs[i].DeleteChannelRules {
+			normalize(&rule.OrgID, rule.OrgName)
+		}
+		for _, writeConfig := range configs[i].WriteConfigs {
+			normalize(&writeConfig.OrgID, writeConfig.OrgName)
+		}
+		for _, writeConfig := range configs[i].DeleteWriteConfigs {
+			normalize(&writeConfig.OrgID, writeConfig.OrgName)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package livepipeline
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+const (
+	incorrectSettings  = "./testdata/test-configs/incorrect-settings"
+	brokenYaml         = "./testdata/test-configs/broken-yaml"
+	emptyFolder        = "./testdata/test-configs/empty_folder"
+	unsupportedVersion = "./testdata/test-configs/unsupported-version"
+	correctProperties  = "./testdata/test-configs/correct-properties"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(context.Background(), brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(context.Background(), emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Unsupported api version should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(context.Background(), unsupportedVersion)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported apiVersion 2")
+	})
+
+	t.Run("Read incorrect properties", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(context.Background(), incorrectSettings)
+		require.Error(t, err)
+		require.Equal(t, "channel rule item 1 in configuration doesn't contain required field pattern\n"+
+			"write config item 1 in configuration doesn't contain required field uid", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		err := os.Setenv("REMOTE_WRITE_PASSWORD", "secret")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = os.Unsetenv("REMOTE_WRITE_PASSWORD")
+		})
+
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(context.Background(), correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*deleteChannelRuleConfig{{OrgID: 2, Pattern: "stream/test/old"}}, cfg[0].DeleteChannelRules)
+		require.Equal(t, []*deleteWriteConfigConfig{{OrgID: 1, UID: "old-remote-write"}}, cfg[0].DeleteWriteConfigs)
+
+		require.Equal(t, []*writeConfigFromConfig{
+			{
+				OrgID:   0,
+				OrgName: "Org 3",
+				UID:     "remote-write",
+				Settings: pipeline.WriteSettings{
+					Endpoint:  "http://localhost:9090/api/v1/write",
+					BasicAuth: &pipeline.BasicAuth{User: "admin"},
+				},
+				SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+			},
+		}, cfg[0].WriteConfigs)
+
+		require.Equal(t, []*channelRuleFromConfig{
+			{
+				OrgID:   1,
+				Pattern: "stream/test/json",
+				Settings: pipeline.ChannelRuleSettings{
+					Converter: &pipeline.ConverterConfig{Type: pipeline.ConverterTypeJsonAuto},
+					FrameOutputters: []*pipeline.FrameOutputterConfig{
+						{Type: "remoteWrite", RemoteWriteOutputConfig: &pipeline.RemoteWriteOutputConfig{UID: "remote-write"}},
+					},
+				},
+			},
+			{
+				OrgID:   2,
+				Pattern: "stream/test/influx",
+				Settings: pipeline.ChannelRuleSettings{
+					Converter: &pipeline.ConverterConfig{
+						Type:                      pipeline.ConverterTypeInfluxAuto,
+						AutoInfluxConverterConfig: &pipeline.AutoInfluxConverterConfig{FrameFormat: "labels_column"},
+					},
+				},
+			},
+		}, cfg[0].ChannelRules)
+	})
+}
@@ -0,0 +1,142 @@
+package livepipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the Live pipeline channel rules and write configs in those files.
+func Provision(ctx context.Context, configDirectory string, storage pipeline.Storage, orgService org.Service) error {
+	logger := log.New("provisioning.live_pipeline")
+	lp := LivePipelineProvisioner{
+		log:         logger,
+		cfgProvider: newConfigReader(logger),
+		storage:     storage,
+		orgService:  orgService,
+	}
+	return lp.applyChanges(ctx, configDirectory)
+}
+
+// LivePipelineProvisioner is responsible for provisioning Live pipeline channel rules
+// and write configs based on configuration read by the `configReader`
+type LivePipelineProvisioner struct {
+	log         log.Logger
+	cfgProvider configReader
+	storage     pipeline.Storage
+	orgService  org.Service
+}
+
+func (lp *LivePipelineProvisioner) apply(ctx context.Context, cfg *livePipelineAsConfig) error {
+	for _, rule := range cfg.DeleteChannelRules {
+		orgID, err := lp.resolveOrgID(ctx, rule.OrgID, rule.OrgName)
+		if err != nil {
+			return err
+		}
+		exists, err := lp.channelRuleExists(ctx, orgID, rule.Pattern)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		lp.log.Info("Deleting Live channel rule from configuration", "pattern", rule.Pattern, "orgId", orgID)
+		if err := lp.storage.DeleteChannelRule(ctx, orgID, pipeline.ChannelRuleDeleteCmd{Pattern: rule.Pattern}); err != nil {
+			return err
+		}
+	}
+
+	for _, writeConfig := range cfg.DeleteWriteConfigs {
+		orgID, err := lp.resolveOrgID(ctx, writeConfig.OrgID, writeConfig.OrgName)
+		if err != nil {
+			return err
+		}
+		_, exists, err := lp.storage.GetWriteConfig(ctx, orgID, pipeline.WriteConfigGetCmd{UID: writeConfig.UID})
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		lp.log.Info("Deleting Live write config from configuration", "uid", writeConfig.UID, "orgId", orgID)
+		if err := lp.storage.DeleteWriteConfig(ctx, orgID, pipeline.WriteConfigDeleteCmd{UID: writeConfig.UID}); err != nil {
+			return err
+		}
+	}
+
+	// Write configs go first, so that the channel rules referencing them are valid once provisioned.
+	for _, writeConfig := range cfg.WriteConfigs {
+		orgID, err := lp.resolveOrgID(ctx, writeConfig.OrgID, writeConfig.OrgName)
+		if err != nil {
+			return err
+		}
+		lp.log.Info("Updating Live write config from configuration", "uid", writeConfig.UID, "orgId", orgID)
+		if _, err := lp.storage.UpdateWriteConfig(ctx, orgID, pipeline.WriteConfigUpdateCmd{
+			UID:            writeConfig.UID,
+			Settings:       writeConfig.Settings,
+			SecureSettings: writeConfig.SecureSettings,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range cfg.ChannelRules {
+		orgID, err := lp.resolveOrgID(ctx, rule.OrgID, rule.OrgName)
+		if err != nil {
+			return err
+		}
+		lp.log.Info("Updating Live channel rule from configuration", "pattern", rule.Pattern, "orgId", orgID)
+		if _, err := lp.storage.UpdateChannelRule(ctx, orgID, pipeline.ChannelRuleUpdateCmd{
+			Pattern:  rule.Pattern,
+			Settings: rule.Settings,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lp *LivePipelineProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := lp.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := lp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lp *LivePipelineProvisioner) resolveOrgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID == 0 && orgName != "" {
+		res, err := lp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+		if err != nil {
+			return 0, err
+		}
+		return res.ID, nil
+	} else if orgID < 0 {
+		return 1, nil
+	}
+	return orgID, nil
+}
+
+func (lp *LivePipelineProvisioner) channelRuleExists(ctx context.Context, orgID int64, pattern string) (bool, error) {
+	rules, err := lp.storage.ListChannelRules(ctx, orgID)
+	if err != nil {
+		return false, err
+	}
+	for _, rule := range rules {
+		if rule.Pattern == pattern {
+			return true, nil
+		}
+	}
+	return false, nil
+}
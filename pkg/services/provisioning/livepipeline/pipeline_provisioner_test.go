@@ -0,0 +1,151 @@
+package livepipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+func TestLivePipelineProvisioner(t *testing.T) {
+	t.Run("Should return error when config reader returns error", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		reader := &testConfigReader{err: expectedErr}
+		lp := LivePipelineProvisioner{log: log.New("test"), cfgProvider: reader}
+		err := lp.applyChanges(context.Background(), "")
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("Should apply configurations", func(t *testing.T) {
+		cfg := []*livePipelineAsConfig{
+			{
+				DeleteChannelRules: []*deleteChannelRuleConfig{
+					{OrgID: 1, Pattern: "stream/test/old"},
+					{OrgID: 1, Pattern: "stream/test/missing"},
+				},
+				DeleteWriteConfigs: []*deleteWriteConfigConfig{
+					{OrgID: 1, UID: "old"},
+					{OrgID: 1, UID: "missing"},
+				},
+				WriteConfigs: []*writeConfigFromConfig{
+					{OrgName: "Org 4", UID: "remote-write", Settings: pipeline.WriteSettings{Endpoint: "http://localhost"}},
+				},
+				ChannelRules: []*channelRuleFromConfig{
+					{OrgID: 2, Pattern: "stream/test/json"},
+					{OrgID: -1, Pattern: "stream/test/influx"},
+				},
+			},
+		}
+		reader := &testConfigReader{result: cfg}
+		storage := &fakeStorage{
+			rules:        map[int64][]string{1: {"stream/test/old"}},
+			writeConfigs: map[int64][]string{1: {"old"}},
+		}
+		orgMock := orgtest.NewOrgServiceFake()
+		orgMock.ExpectedOrg = &org.Org{ID: 4}
+		lp := LivePipelineProvisioner{log: log.New("test"), cfgProvider: reader, storage: storage, orgService: orgMock}
+
+		err := lp.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"1/stream/test/old"}, storage.deletedRules)
+		require.Equal(t, []string{"1/old"}, storage.deletedWriteConfigs)
+		require.Equal(t, map[int64][]string{2: {"stream/test/json"}, 1: {"stream/test/influx"}}, storage.rules)
+		require.Equal(t, map[int64][]string{4: {"remote-write"}}, storage.writeConfigs)
+	})
+}
+
+type testConfigReader struct {
+	result []*livePipelineAsConfig
+	err    error
+}
+
+func (tcr *testConfigReader) readConfig(_ context.Context, _ string) ([]*livePipelineAsConfig, error) {
+	return tcr.result, tcr.err
+}
+
+// fakeStorage keeps track of the channel rule patterns and write config UIDs per organization.
+type fakeStorage struct {
+	rules               map[int64][]string
+	writeConfigs        map[int64][]string
+	deletedRules        []string
+	deletedWriteConfigs []string
+}
+
+func (s *fakeStorage) ListWriteConfigs(_ context.Context, orgID int64) ([]pipeline.WriteConfig, error) {
+	var result []pipeline.WriteConfig
+	for _, uid := range s.writeConfigs[orgID] {
+		result = append(result, pipeline.WriteConfig{OrgId: orgID, UID: uid})
+	}
+	return result, nil
+}
+
+func (s *fakeStorage) GetWriteConfig(_ context.Context, orgID int64, cmd pipeline.WriteConfigGetCmd) (pipeline.WriteConfig, bool, error) {
+	for _, uid := range s.writeConfigs[orgID] {
+		if uid == cmd.UID {
+			return pipeline.WriteConfig{OrgId: orgID, UID: uid}, true, nil
+		}
+	}
+	return pipeline.WriteConfig{}, false, nil
+}
+
+func (s *fakeStorage) CreateWriteConfig(_ context.Context, _ int64, _ pipeline.WriteConfigCreateCmd) (pipeline.WriteConfig, error) {
+	return pipeline.WriteConfig{}, errors.New("not implemented")
+}
+
+func (s *fakeStorage) UpdateWriteConfig(_ context.Context, orgID int64, cmd pipeline.WriteConfigUpdateCmd) (pipeline.WriteConfig, error) {
+	s.writeConfigs[orgID] = append(s.writeConfigs[orgID], cmd.UID)
+	return pipeline.WriteConfig{OrgId: orgID, UID: cmd.UID, Settings: cmd.Settings}, nil
+}
+
+func (s *fakeStorage) DeleteWriteConfig(_ context.Context, orgID int64, cmd pipeline.WriteConfigDeleteCmd) error {
+	s.writeConfigs[orgID] = remove(s.writeConfigs[orgID], cmd.UID)
+	if len(s.writeConfigs[orgID]) == 0 {
+		delete(s.writeConfigs, orgID)
+	}
+	s.deletedWriteConfigs = append(s.deletedWriteConfigs, fmt.Sprintf("%d/%s", orgID, cmd.UID))
+	return nil
+}
+
+func (s *fakeStorage) ListChannelRules(_ context.Context, orgID int64) ([]pipeline.ChannelRule, error) {
+	var result []pipeline.ChannelRule
+	for _, pattern := range s.rules[orgID] {
+		result = append(result, pipeline.ChannelRule{OrgId: orgID, Pattern: pattern})
+	}
+	return result, nil
+}
+
+func (s *fakeStorage) CreateChannelRule(_ context.Context, _ int64, _ pipeline.ChannelRuleCreateCmd) (pipeline.ChannelRule, error) {
+	return pipeline.ChannelRule{}, errors.New("not implemented")
+}
+
+func (s *fakeStorage) UpdateChannelRule(_ context.Context, orgID int64, cmd pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	s.rules[orgID] = append(s.rules[orgID], cmd.Pattern)
+	return pipeline.ChannelRule{OrgId: orgID, Pattern: cmd.Pattern, Settings: cmd.Settings}, nil
+}
+
+func (s *fakeStorage) DeleteChannelRule(_ context.Context, orgID int64, cmd pipeline.ChannelRuleDeleteCmd) error {
+	s.rules[orgID] = remove(s.rules[orgID], cmd.Pattern)
+	if len(s.rules[orgID]) == 0 {
+		delete(s.rules, orgID)
+	}
+	s.deletedRules = append(s.deletedRules, fmt.Sprintf("%d/%s", orgID, cmd.Pattern))
+	return nil
+}
+
+func remove(items []string, item string) []string {
+	var result []string
+	for _, i := range items {
+		if i != item {
+			result = append(result, i)
+		}
+	}
+	return result
+}
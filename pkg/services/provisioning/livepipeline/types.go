@@ -0,0 +1,152 @@
+package livepipeline
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// livePipelineAsConfig is a normalized data object for Live pipeline config data. Any config version
+// should be mappable to this type.
+type livePipelineAsConfig struct {
+	ChannelRules       []*channelRuleFromConfig
+	DeleteChannelRules []*deleteChannelRuleConfig
+	WriteConfigs       []*writeConfigFromConfig
+	DeleteWriteConfigs []*deleteWriteConfigConfig
+}
+
+type channelRuleFromConfig struct {
+	OrgID    int64
+	OrgName  string
+	Pattern  string
+	Settings pipeline.ChannelRuleSettings
+}
+
+type deleteChannelRuleConfig struct {
+	OrgID   int64
+	OrgName string
+	Pattern string
+}
+
+type writeConfigFromConfig struct {
+	OrgID          int64
+	OrgName        string
+	UID            string
+	Settings       pipeline.WriteSettings
+	SecureSettings map[string]string
+}
+
+type deleteWriteConfigConfig struct {
+	OrgID   int64
+	OrgName string
+	UID     string
+}
+
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// livePipelineAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type livePipelineAsConfigV1 struct {
+	ChannelRules       []*channelRuleFromConfigV1   `json:"channelRules" yaml:"channelRules"`
+	DeleteChannelRules []*deleteChannelRuleConfigV1 `json:"deleteChannelRules" yaml:"deleteChannelRules"`
+	WriteConfigs       []*writeConfigFromConfigV1   `json:"writeConfigs" yaml:"writeConfigs"`
+	DeleteWriteConfigs []*deleteWriteConfigConfigV1 `json:"deleteWriteConfigs" yaml:"deleteWriteConfigs"`
+}
+
+type channelRuleFromConfigV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName  values.StringValue `json:"orgName" yaml:"orgName"`
+	Pattern  values.StringValue `json:"pattern" yaml:"pattern"`
+	Settings values.JSONValue   `json:"settings" yaml:"settings"`
+}
+
+type deleteChannelRuleConfigV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+	Pattern values.StringValue `json:"pattern" yaml:"pattern"`
+}
+
+type writeConfigFromConfigV1 struct {
+	OrgID          values.Int64Value     `json:"orgId" yaml:"orgId"`
+	OrgName        values.StringValue    `json:"orgName" yaml:"orgName"`
+	UID            values.StringValue    `json:"uid" yaml:"uid"`
+	Settings       values.JSONValue      `json:"settings" yaml:"settings"`
+	SecureSettings values.StringMapValue `json:"secureSettings" yaml:"secureSettings"`
+}
+
+type deleteWriteConfigConfigV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+	UID     values.StringValue `json:"uid" yaml:"uid"`
+}
+
+// mapToLivePipelineFromConfig maps config syntax to a normalized livePipelineAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *livePipelineAsConfigV1) mapToLivePipelineFromConfig() (*livePipelineAsConfig, error) {
+	r := &livePipelineAsConfig{}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, rule := range cfg.ChannelRules {
+		var settings pipeline.ChannelRuleSettings
+		if err := convertSettings(&rule.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("invalid settings of channel rule %q: %w", rule.Pattern.Value(), err)
+		}
+		r.ChannelRules = append(r.ChannelRules, &channelRuleFromConfig{
+			OrgID:    rule.OrgID.Value(),
+			OrgName:  rule.OrgName.Value(),
+			Pattern:  rule.Pattern.Value(),
+			Settings: settings,
+		})
+	}
+
+	for _, rule := range cfg.DeleteChannelRules {
+		r.DeleteChannelRules = append(r.DeleteChannelRules, &deleteChannelRuleConfig{
+			OrgID:   rule.OrgID.Value(),
+			OrgName: rule.OrgName.Value(),
+			Pattern: rule.Pattern.Value(),
+		})
+	}
+
+	for _, writeConfig := range cfg.WriteConfigs {
+		var settings pipeline.WriteSettings
+		if err := convertSettings(&writeConfig.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("invalid settings of write config %q: %w", writeConfig.UID.Value(), err)
+		}
+		r.WriteConfigs = append(r.WriteConfigs, &writeConfigFromConfig{
+			OrgID:          writeConfig.OrgID.Value(),
+			OrgName:        writeConfig.OrgName.Value(),
+			UID:            writeConfig.UID.Value(),
+			Settings:       settings,
+			SecureSettings: writeConfig.SecureSettings.Value(),
+		})
+	}
+
+	for _, writeConfig := range cfg.DeleteWriteConfigs {
+		r.DeleteWriteConfigs = append(r.DeleteWriteConfigs, &deleteWriteConfigConfig{
+			OrgID:   writeConfig.OrgID.Value(),
+			OrgName: writeConfig.OrgName.Value(),
+			UID:     writeConfig.UID.Value(),
+		})
+	}
+
+	return r, nil
+}
+
+// convertSettings converts the interpolated settings into the typed pipeline settings
+// by going through their JSON representation.
+func convertSettings(settings *values.JSONValue, target interface{}) error {
+	raw := settings.Value()
+	if raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
@@ -21,6 +21,10 @@ type PublicDashboardStoreImpl struct {
 
 var LogPrefix = "publicdashboards.store"
 
+// notInTrash excludes public dashboards of dashboards moved to the trash. They are
+// kept so that restoring the dashboard restores its public dashboard as well.
+const notInTrash = "NOT EXISTS (SELECT 1 FROM dashboard WHERE dashboard.uid = dashboard_public.dashboard_uid AND dashboard.org_id = dashboard_public.org_id AND dashboard.deleted IS NOT NULL)"
+
 // Gives us a compile time error if our database does not adhere to contract of
 // the interface
 var _ publicdashboards.Store = (*PublicDashboardStoreImpl)(nil)
@@ -42,6 +46,7 @@ func (d *PublicDashboardStoreImpl) FindAll(ctx context.Context, orgId int64) ([]
 			"dashboard_public.uid, dashboard_public.access_token, dashboard.uid as dashboard_uid, dashboard_public.is_enabled, dashboard.title").
 			Join("LEFT", "dashboard", "dashboard.uid = dashboard_public.dashboard_uid AND dashboard.org_id = dashboard_public.org_id").
 			Where("dashboard_public.org_id = ?", orgId).
+			And(notInTrash).
 			OrderBy(" is_enabled DESC, dashboard.title IS NULL, dashboard.title ASC")
 
 		err := sess.Find(&resp)
@@ -62,7 +67,7 @@ func (d *PublicDashboardStoreImpl) FindDashboard(ctx context.Context, orgId int6
 	var found bool
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
-		found, err = sess.Get(dashboard)
+		found, err = sess.Where("deleted IS NULL").Get(dashboard)
 		return err
 	})
 
@@ -112,7 +117,7 @@ func (d *PublicDashboardStoreImpl) FindByAccessToken(ctx context.Context, access
 	publicDashboard := &PublicDashboard{AccessToken: accessToken}
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
-		found, err = sess.Get(publicDashboard)
+		found, err = sess.Where(notInTrash).Get(publicDashboard)
 		return err
 	})
 
@@ -177,7 +182,7 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true AND " + notInTrash
 
 		result, err := dbSession.SQL(sql, accessToken).Count()
 		if err != nil {
@@ -195,7 +200,7 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Contex
 func (d *PublicDashboardStoreImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	var orgId int64
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT org_id FROM dashboard_public WHERE access_token=?"
+		sql := "SELECT org_id FROM dashboard_public WHERE access_token=? AND " + notInTrash
 
 		_, err := dbSession.SQL(sql, accessToken).Get(&orgId)
 		if err != nil {
@@ -370,6 +370,41 @@ func TestIntegrationFindByAccessToken(t *testing.T) {
 		require.NoError(t, err)
 		assert.Nil(t, pubdash)
 	})
+
+	t.Run("returns nil while the dashboard is in the trash", func(t *testing.T) {
+		setup()
+		savedPubdash := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true)
+		err := dashboardStore.SoftDeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: savedDashboard.ID, OrgID: savedDashboard.OrgID})
+		require.NoError(t, err)
+
+		pubdash, err := publicdashboardStore.FindByAccessToken(context.Background(), savedPubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Nil(t, pubdash)
+
+		exists, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), savedPubdash.AccessToken)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		orgId, err := publicdashboardStore.GetOrgIdByAccessToken(context.Background(), savedPubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Zero(t, orgId)
+
+		dashboard, err := publicdashboardStore.FindDashboard(context.Background(), savedDashboard.OrgID, savedDashboard.UID)
+		require.NoError(t, err)
+		assert.Nil(t, dashboard)
+
+		list, err := publicdashboardStore.FindAll(context.Background(), savedDashboard.OrgID)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		// Restoring the dashboard brings the public dashboard back.
+		_, err = dashboardStore.RestoreDeletedDashboard(context.Background(), &dashboards.RestoreDeletedDashboardCommand{UID: savedDashboard.UID, OrgID: savedDashboard.OrgID})
+		require.NoError(t, err)
+
+		pubdash, err = publicdashboardStore.FindByAccessToken(context.Background(), savedPubdash.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, savedPubdash, pubdash)
+	})
 }
 
 func TestIntegrationCreatePublicDashboard(t *testing.T) {
@@ -85,6 +85,24 @@ func (f FolderFilter) Where() (string, []interface{}) {
 	return sqlIDin("dashboard.folder_id", f.IDs)
 }
 
+// DeletedFilter excludes dashboards and folders in the trash, or returns only them if
+// Deleted is set. Dashboards in a deleted folder are left out of the trash, they are
+// restored together with the folder.
+type DeletedFilter struct {
+	Deleted bool
+}
+
+func (f DeletedFilter) Where() (string, []interface{}) {
+	if !f.Deleted {
+		return "dashboard.deleted IS NULL", nil
+	}
+	return "dashboard.deleted IS NOT NULL AND dashboard.folder_id NOT IN (SELECT id FROM dashboard WHERE deleted IS NOT NULL)", nil
+}
+
+func (f DeletedFilter) Select() string {
+	return "dashboard.deleted"
+}
+
 type DashboardIDFilter struct {
 	IDs []int64
 }
@@ -253,6 +253,8 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	// DashboardTrashRetention is how long deleted dashboards and folders are kept before they are permanently removed.
+	DashboardTrashRetention time.Duration
 
 	// Auth
 	LoginCookieName              string
@@ -456,6 +458,12 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LiveManagedStreamHistorySize is a maximum number of frames kept in history
+	// of every managed stream channel. 0 disables history.
+	LiveManagedStreamHistorySize int
+	// LiveManagedStreamHistoryDuration is a maximum age of frames returned from
+	// managed stream channel history to new subscribers.
+	LiveManagedStreamHistoryDuration time.Duration
 
 	// GitHub OAuth
 	GitHubAuthEnabled     bool
@@ -1016,6 +1024,16 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
 
+	trashRetention, err := gtime.ParseDuration(valueAsString(dashboards, "trash_retention", "30d"))
+	if err != nil {
+		return err
+	}
+	// Dashboards older than the retention are purged from the trash, a retention of zero would empty it on every cleanup.
+	if trashRetention <= 0 {
+		return fmt.Errorf("unexpected value %s for [dashboards] trash_retention, it must be positive", trashRetention)
+	}
+	cfg.DashboardTrashRetention = trashRetention
+
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
 	}
@@ -1863,6 +1881,11 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		return fmt.Errorf("unsupported live HA engine type: %s", cfg.LiveHAEngine)
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
+	cfg.LiveManagedStreamHistorySize = section.Key("managed_stream_history_size").MustInt(100)
+	if cfg.LiveManagedStreamHistorySize < 0 {
+		return fmt.Errorf("unexpected value %d for [live] managed_stream_history_size", cfg.LiveManagedStreamHistorySize)
+	}
+	cfg.LiveManagedStreamHistoryDuration = section.Key("managed_stream_history_duration").MustDuration(5 * time.Minute)
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
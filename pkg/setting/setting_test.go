@@ -251,6 +251,25 @@ func TestLoadingSettings(t *testing.T) {
 		require.Equal(t, 2, cfg.AuthProxySyncTTL)
 	})
 
+	t.Run("trash_retention must be positive", func(t *testing.T) {
+		cfg := NewCfg()
+		err := cfg.Load(CommandLineArgs{
+			HomePath: "../../",
+			Args:     []string{"cfg:dashboards.trash_retention=7d"},
+		})
+		require.Nil(t, err)
+		require.Equal(t, 7*24*time.Hour, cfg.DashboardTrashRetention)
+
+		for _, value := range []string{"0", "-1h"} {
+			cfg := NewCfg()
+			err := cfg.Load(CommandLineArgs{
+				HomePath: "../../",
+				Args:     []string{"cfg:dashboards.trash_retention=" + value},
+			})
+			require.Error(t, err)
+		}
+	})
+
 	t.Run("Test reading string values from .ini file", func(t *testing.T) {
 		iniFile, err := ini.Load(path.Join(HomePath, "pkg/setting/testdata/invalid.ini"))
 		require.Nil(t, err)
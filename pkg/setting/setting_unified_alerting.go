@@ -48,6 +48,7 @@ const (
 	schedulereDefaultExecuteAlerts          = true
 	schedulerDefaultMaxAttempts             = 3
 	schedulerDefaultLegacyMinInterval       = 1
+	schedulerDefaultEvaluationJitter        = "none"
 	screenshotsDefaultCapture               = false
 	screenshotsDefaultCaptureTimeout        = 10 * time.Second
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
@@ -60,6 +61,9 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+	// stateHistorySQLDefaultRetention is how long the sql state history backend keeps entries by default.
+	stateHistorySQLDefaultRetention = 30 * 24 * time.Hour
+	stateHistorySQLDefaultBatchSize = 100
 )
 
 type UnifiedAlertingSettings struct {
@@ -71,8 +75,11 @@ type UnifiedAlertingSettings struct {
 	HAPeerTimeout                  time.Duration
 	HAGossipInterval               time.Duration
 	HAPushPullInterval             time.Duration
+	HAShardRuleEvaluation          bool
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
+	EvaluationJitter               string
+	MaxConcurrentEvaluations       int64
 	EvaluationTimeout              time.Duration
 	ExecuteAlerts                  bool
 	DefaultConfiguration           string
@@ -113,6 +120,10 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLRetention is how long the sql backend keeps state history entries.
+	SQLRetention time.Duration
+	// SQLBatchSize is the maximum number of rows the sql backend writes per insert statement.
+	SQLBatchSize int
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -232,6 +243,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 			uaCfg.HAPeers = append(uaCfg.HAPeers, peer)
 		}
 	}
+	uaCfg.HAShardRuleEvaluation = ua.Key("ha_shard_rule_evaluation").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -292,6 +304,18 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.MinInterval = uaMinInterval
 
+	uaCfg.EvaluationJitter = ua.Key("evaluation_jitter").MustString(schedulerDefaultEvaluationJitter)
+	switch uaCfg.EvaluationJitter {
+	case "none", "group", "rule":
+	default:
+		return fmt.Errorf("value of setting 'evaluation_jitter' should be one of 'none', 'group' or 'rule', got %q", uaCfg.EvaluationJitter)
+	}
+
+	uaCfg.MaxConcurrentEvaluations = ua.Key("max_concurrent_evaluations").MustInt64(0)
+	if uaCfg.MaxConcurrentEvaluations < 0 {
+		return fmt.Errorf("value of setting 'max_concurrent_evaluations' cannot be negative")
+	}
+
 	uaCfg.DefaultRuleEvaluationInterval = DefaultRuleEvaluationInterval
 	if uaMinInterval > uaCfg.DefaultRuleEvaluationInterval {
 		uaCfg.DefaultRuleEvaluationInterval = uaMinInterval
@@ -335,6 +359,8 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLRetention:          stateHistory.Key("sql_retention").MustDuration(stateHistorySQLDefaultRetention),
+		SQLBatchSize:          stateHistory.Key("sql_batch_size").MustInt(stateHistorySQLDefaultBatchSize),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
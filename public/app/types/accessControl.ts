@@ -119,6 +119,7 @@ export enum AccessControlAction {
 
   // Alerting provisioning actions
   AlertingProvisioningRead = 'alert.provisioning:read',
+  AlertingProvisioningReadSecrets = 'alert.provisioning.secrets:read',
   AlertingProvisioningWrite = 'alert.provisioning:write',
 
   ActionAPIKeysRead = 'apikeys:read',